@@ -0,0 +1,453 @@
+package Calendar
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+// Kinds of entries on the academic calendar.
+const (
+	Semester = "semester" // the days classes are held on, every day outside the semesters is closed
+	Holiday  = "holiday"
+	Exams    = "exams"   // there are no classes, but students still come for their exams
+	Closure  = "closure" // added from the chat by an admin (eg. for bad weather), never imported
+)
+
+// dateFormat : how a day is written in the files, the commands and the entries.
+const dateFormat = "2006-01-02"
+
+// Store : keeps the academic calendar.
+type Store interface {
+	Entries() ([]DB.CalendarEntry, error)
+	Replace(entries []DB.CalendarEntry) error
+	Add(entry *DB.CalendarEntry) error
+}
+
+// DBStore : the store that keeps the calendar in the database.
+type DBStore struct{}
+
+// Entries : loads the calendar from the database.
+func (DBStore) Entries() ([]DB.CalendarEntry, error) { return DB.GetCalendar() }
+
+// Replace : replaces the imported entries in the database, keeping the closures.
+func (DBStore) Replace(entries []DB.CalendarEntry) error { return DB.ReplaceCalendar(entries) }
+
+// Add : adds an entry to the calendar in the database.
+func (DBStore) Add(entry *DB.CalendarEntry) error { return DB.InsertCalendarEntry(entry) }
+
+// MemoryStore : a store that keeps the calendar in memory, for tests and running locally.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	entries []DB.CalendarEntry
+	lastID  uint64
+}
+
+// NewMemoryStore : creates a memory store with the given entries.
+func NewMemoryStore(entries ...DB.CalendarEntry) *MemoryStore {
+	m := &MemoryStore{}
+	for i := range entries {
+		m.Add(&entries[i])
+	}
+	return m
+}
+
+// Entries : returns the calendar from memory.
+func (m *MemoryStore) Entries() ([]DB.CalendarEntry, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]DB.CalendarEntry{}, m.entries...), nil
+}
+
+// Replace : replaces the imported entries in memory, keeping the closures.
+func (m *MemoryStore) Replace(entries []DB.CalendarEntry) error {
+	m.mutex.Lock()
+	kept := []DB.CalendarEntry{}
+	for _, entry := range m.entries {
+		if entry.Kind == Closure {
+			kept = append(kept, entry)
+		}
+	}
+	m.entries = kept
+	m.mutex.Unlock()
+	for i := range entries {
+		m.Add(&entries[i])
+	}
+	return nil
+}
+
+// Add : adds an entry to the calendar in memory, giving it the next ID.
+func (m *MemoryStore) Add(entry *DB.CalendarEntry) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastID++
+	entry.ID = m.lastID
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+// Year : the academic calendar, loaded to check days against it. The zero Year has no entries, so every day is open.
+type Year struct {
+	entries []DB.CalendarEntry
+}
+
+// Load : loads the academic calendar from the store.
+func Load(store Store) (Year, error) {
+	entries, err := store.Entries()
+	return Year{entries: entries}, err
+}
+
+// Closed : returns the entry the campus is closed for on the day of the time at the GUC: a holiday, a closure, or a day outside all the semesters.
+func (y Year) Closed(t time.Time) (DB.CalendarEntry, bool) {
+	day := t.In(Timezone.Location).Format(dateFormat)
+	inSemester, hasSemesters := false, false
+	for _, entry := range y.entries {
+		switch entry.Kind {
+		case Holiday, Closure:
+			if covers(entry, day) {
+				return entry, true
+			}
+		case Semester:
+			hasSemesters = true
+			inSemester = inSemester || covers(entry, day)
+		}
+	}
+	if hasSemesters && !inSemester {
+		return DB.CalendarEntry{Kind: Semester, Name: "between semesters", Start: day, End: day}, true
+	}
+	return DB.CalendarEntry{}, false
+}
+
+// NoClasses : returns the entry there are no classes for on the day of the time. It is closed, or it is during the exams.
+func (y Year) NoClasses(t time.Time) (DB.CalendarEntry, bool) {
+	if entry, closed := y.Closed(t); closed {
+		return entry, true
+	}
+	day := t.In(Timezone.Location).Format(dateFormat)
+	for _, entry := range y.entries {
+		if entry.Kind == Exams && covers(entry, day) {
+			return entry, true
+		}
+	}
+	return DB.CalendarEntry{}, false
+}
+
+// Function that checks the day (eg. "2026-10-06") is one of the days of the entry. Days written this way sort like the dates they are.
+func covers(entry DB.CalendarEntry, day string) bool {
+	return entry.Start <= day && day <= entry.End
+}
+
+// EntryToString : describes the entry (eg. "Armed Forces Day (holiday, 2026-10-06)").
+func EntryToString(entry DB.CalendarEntry) string {
+	days := entry.Start
+	if entry.End != entry.Start {
+		days += " to " + entry.End
+	}
+	return entry.Name + " (" + entry.Kind + ", " + days + ")"
+}
+
+// NewClosure : makes the entry of a closure of the campus from one day to another, added by an admin.
+func NewClosure(from time.Time, to time.Time, reason string, GUCID string) (DB.CalendarEntry, error) {
+	if to.Before(from) {
+		return DB.CalendarEntry{}, Problem.New("problem.closure", nil, "the campus has to open again after it closes")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "Campus closed"
+	}
+	return DB.CalendarEntry{Kind: Closure, Name: reason, Start: from.In(Timezone.Location).Format(dateFormat), End: to.In(Timezone.Location).Format(dateFormat), AddedBy: GUCID}, nil
+}
+
+// Function that makes an entry, checking its days.
+func newEntry(kind string, name string, start string, end string) (DB.CalendarEntry, error) {
+	if end == "" {
+		end = start
+	}
+	for _, day := range []string{start, end} {
+		if _, err := time.Parse(dateFormat, day); err != nil {
+			return DB.CalendarEntry{}, fmt.Errorf("%q is not a day written as year-month-day", day)
+		}
+	}
+	if end < start {
+		return DB.CalendarEntry{}, fmt.Errorf("%s ends on %s, before it starts", name, end)
+	}
+	if name == "" {
+		name = kind
+	}
+	return DB.CalendarEntry{Kind: kind, Name: name, Start: start, End: end}, nil
+}
+
+// ParseCSV : reads a calendar CSV with the columns kind (semester, holiday or exams), name, first day and last day (eg. "holiday,Armed Forces Day,2026-10-06,"). The last day can be left out for one day. A header row is skipped.
+func ParseCSV(reader io.Reader) ([]DB.CalendarEntry, error) {
+	rows := csv.NewReader(reader)
+	rows.FieldsPerRecord = -1
+	rows.TrimLeadingSpace = true
+	var entries []DB.CalendarEntry
+	for line := 1; ; line++ {
+		row, err := rows.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+		if line == 1 && strings.EqualFold(row[0], "kind") {
+			continue
+		}
+		if len(row) != 3 && len(row) != 4 {
+			return nil, fmt.Errorf("line %d: expected the kind, name, first day and last day", line)
+		}
+		kind := strings.ToLower(row[0])
+		switch kind {
+		case "exam":
+			kind = Exams
+		case Semester, Holiday, Exams:
+		case Closure:
+			return nil, fmt.Errorf("line %d: closures are added from the chat, not imported", line)
+		default:
+			return nil, fmt.Errorf("line %d: %q is not semester, holiday or exams", line, row[0])
+		}
+		end := ""
+		if len(row) == 4 {
+			end = row[3]
+		}
+		entry, err := newEntry(kind, row[1], row[2], end)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("the calendar is empty")
+	}
+	return entries, nil
+}
+
+// Event : the properties of an iCalendar event by name (eg. "SUMMARY"). The parameters of a property are kept under its name and theirs (eg. "DTSTART;TZID").
+type Event map[string]string
+
+// ReadEvents : reads the events of an iCalendar file.
+func ReadEvents(reader io.Reader) ([]Event, error) {
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// Long lines are folded onto the next ones, which start with a space.
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var event Event
+	for _, line := range lines {
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			continue
+		}
+		params, value := strings.Split(line[:colon], ";"), line[colon+1:]
+		name := strings.ToUpper(params[0])
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = Event{}
+		case name == "END" && value == "VEVENT" && event != nil:
+			events = append(events, event)
+			event = nil
+		case event != nil:
+			event[name] = value
+			for _, param := range params[1:] {
+				if equals := strings.Index(param, "="); equals >= 0 {
+					event[name+";"+strings.ToUpper(param[:equals])] = strings.Trim(param[equals+1:], `"`)
+				}
+			}
+		}
+	}
+	if len(events) == 0 {
+		return nil, errors.New("the calendar has no events")
+	}
+	return events, nil
+}
+
+var icsEscapes = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`)
+
+// Summary : the summary of the event, without the escapes of iCalendar.
+func (e Event) Summary() string {
+	return strings.TrimSpace(icsEscapes.Replace(e["SUMMARY"]))
+}
+
+// Time : reads a date and time of the event (eg. "DTSTART"), in its time zone, and returns it as a time at the GUC. Times in UTC end with "Z", and times without a zone are at the GUC.
+func (e Event) Time(name string) (time.Time, error) {
+	value := e[name]
+	location := Timezone.Location
+	if zone := e[name+";TZID"]; zone != "" {
+		if zoneLocation, err := time.LoadLocation(zone); err == nil {
+			location = zoneLocation
+		}
+	}
+	if strings.HasSuffix(value, "Z") {
+		value, location = strings.TrimSuffix(value, "Z"), time.UTC
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an iCalendar date and time", e[name])
+	}
+	return t.In(Timezone.Location), nil
+}
+
+// The words that tell the kind of an iCalendar event, in the order they are looked for.
+var eventKinds = []struct {
+	kind  string
+	words *regexp.Regexp
+}{
+	{Exams, regexp.MustCompile(`\b(exams?|examinations?|finals?|midterms?)\b`)},
+	{Holiday, regexp.MustCompile(`\b(holidays?|vacation|break|eid|christmas|feast|recess|day off|no classes)\b`)},
+	{Semester, regexp.MustCompile(`\b(semester|term)\b`)},
+}
+
+// ParseICS : reads the all day events of an iCalendar file. The kind of an event is taken from the words in its categories and its summary: exams if it mentions exams, a holiday if it mentions a holiday or a break, and a semester if it mentions a semester or a term. The summaries of the events of no known kind are returned, and they are left out. A semester is one event from its first day to its last.
+func ParseICS(reader io.Reader) ([]DB.CalendarEntry, []string, error) {
+	events, err := ReadEvents(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	var entries []DB.CalendarEntry
+	var skipped []string
+	for _, event := range events {
+		entry, known, err := eventEntry(event)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !known {
+			skipped = append(skipped, event.Summary())
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, skipped, nil
+}
+
+// Function that makes the entry of an iCalendar event, if it is of a known kind.
+func eventEntry(event Event) (DB.CalendarEntry, bool, error) {
+	summary := event.Summary()
+	start, err := icsDay(event["DTSTART"])
+	if err != nil {
+		return DB.CalendarEntry{}, false, fmt.Errorf("%s: %s", summary, err.Error())
+	}
+	end := start
+	if event["DTEND"] != "" {
+		end, err = icsDay(event["DTEND"])
+		if err != nil {
+			return DB.CalendarEntry{}, false, fmt.Errorf("%s: %s", summary, err.Error())
+		}
+		// The end of an all day event, or of one that ends at midnight, is the day after its last day.
+		endValue := strings.TrimSuffix(event["DTEND"], "Z")
+		if end.After(start) && (!strings.Contains(endValue, "T") || strings.HasSuffix(endValue, "T000000")) {
+			end = end.AddDate(0, 0, -1)
+		}
+	}
+	text := strings.ToLower(event["CATEGORIES"] + " " + summary)
+	for _, eventKind := range eventKinds {
+		if eventKind.words.MatchString(text) {
+			entry, err := newEntry(eventKind.kind, summary, start.Format(dateFormat), end.Format(dateFormat))
+			return entry, err == nil, err
+		}
+	}
+	return DB.CalendarEntry{}, false, nil
+}
+
+// Function that reads the day of an iCalendar date (eg. "20261006") or date and time (eg. "20261006T080000Z").
+func icsDay(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("%q is not an iCalendar date", value)
+	}
+	day, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an iCalendar date", value)
+	}
+	return day, nil
+}
+
+// FeedEvent : an event of a calendar feed (eg. a ride of a student).
+type FeedEvent struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+}
+
+var icsText = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// WriteFeed : writes the events as an iCalendar file that calendar apps can subscribe to. They check it again every hour, so the changes show up.
+func WriteFeed(w io.Writer, name string, events []FeedEvent, now time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//GUC Carpool//Rides//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + icsText.Replace(name),
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+		"X-PUBLISHED-TTL:PT1H",
+	}
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+now.UTC().Format("20060102T150405Z"),
+			"DTSTART:"+event.Start.UTC().Format("20060102T150405Z"),
+			"DTEND:"+event.End.UTC().Format("20060102T150405Z"),
+			"SUMMARY:"+icsText.Replace(event.Summary),
+		)
+		if event.Location != "" {
+			lines = append(lines, "LOCATION:"+icsText.Replace(event.Location))
+		}
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+icsText.Replace(event.Description))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+	for _, line := range lines {
+		_, err := io.WriteString(w, fold(line))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Function that ends the line with CRLF, and folds it onto the next lines if it is longer than the 75 bytes iCalendar allows, without splitting a character.
+func fold(line string) string {
+	folded := ""
+	length := 0
+	for _, char := range line {
+		size := len(string(char))
+		if length+size > 75 {
+			folded += "\r\n "
+			length = 1
+		}
+		folded += string(char)
+		length += size
+	}
+	return folded + "\r\n"
+}
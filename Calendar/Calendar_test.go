@@ -0,0 +1,146 @@
+package Calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+const ics = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20260913\r\nDTEND;VALUE=DATE:20270101\r\nSUMMARY:Winter Semester\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261006\r\nDTEND;VALUE=DATE:20261007\r\nSUMMARY:Armed Forces\r\n  Day\r\nCATEGORIES:HOLIDAY\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261101\r\nDTEND;VALUE=DATE:20261108\r\nSUMMARY:Midterms\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261115\r\nSUMMARY:Mid-semester break\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20260901\r\nSUMMARY:Course registration\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART:20261220T080000Z\r\nDTEND:20261231T180000Z\r\nSUMMARY:Finals\r\nCATEGORIES:EXAMS\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	entries, skipped, err := ParseICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DB.CalendarEntry{
+		{Kind: Semester, Name: "Winter Semester", Start: "2026-09-13", End: "2026-12-31"},
+		{Kind: Holiday, Name: "Armed Forces Day", Start: "2026-10-06", End: "2026-10-06"},
+		{Kind: Exams, Name: "Midterms", Start: "2026-11-01", End: "2026-11-07"},
+		{Kind: Holiday, Name: "Mid-semester break", Start: "2026-11-15", End: "2026-11-15"},
+		{Kind: Exams, Name: "Finals", Start: "2026-12-20", End: "2026-12-31"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatal("expected 5 entries, got", entries)
+	}
+	// An event that isn't about the days classes are held on is left out.
+	if len(skipped) != 1 || skipped[0] != "Course registration" {
+		t.Error("wrong events left out", skipped)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Error("wrong entry", entries[i])
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	entries, err := ParseCSV(strings.NewReader("Kind,Name,First day,Last day\nsemester,Winter,2026-09-13,2026-12-31\nholiday, Armed Forces Day ,2026-10-06\nexam,Finals,2026-12-20,2026-12-31\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[1] != (DB.CalendarEntry{Kind: Holiday, Name: "Armed Forces Day", Start: "2026-10-06", End: "2026-10-06"}) || entries[2].Kind != Exams {
+		t.Error("wrong entries", entries)
+	}
+
+	cases := map[string]string{
+		"unknown kind": "party,Fun,2026-10-06\n",
+		"closure":      "closure,Storm,2026-10-06\n",
+		"bad day":      "holiday,Eid,6/10/2026\n",
+		"end first":    "holiday,Eid,2026-10-06,2026-10-05\n",
+		"few columns":  "holiday,Eid\n",
+		"empty":        "kind,name,start,end\n",
+	}
+	for name, calendar := range cases {
+		if _, err := ParseCSV(strings.NewReader(calendar)); err == nil {
+			t.Error("accepted a calendar with " + name)
+		}
+	}
+}
+
+func TestClosed(t *testing.T) {
+	store := NewMemoryStore(
+		DB.CalendarEntry{Kind: Semester, Name: "Winter", Start: "2026-09-13", End: "2026-12-31"},
+		DB.CalendarEntry{Kind: Holiday, Name: "Armed Forces Day", Start: "2026-10-06", End: "2026-10-06"},
+		DB.CalendarEntry{Kind: Exams, Name: "Finals", Start: "2026-12-20", End: "2026-12-31"},
+	)
+	year, _ := Load(store)
+	day := func(date string) time.Time {
+		t, _ := time.ParseInLocation(dateFormat+" 15:04", date+" 08:30", Timezone.Location)
+		return t
+	}
+
+	if _, closed := year.Closed(day("2026-10-05")); closed {
+		t.Error("a normal day is closed")
+	}
+	if entry, closed := year.Closed(day("2026-10-06")); !closed || entry.Name != "Armed Forces Day" {
+		t.Error("a holiday is open", entry)
+	}
+	if entry, closed := year.Closed(day("2027-01-05")); !closed || entry.Kind != Semester {
+		t.Error("a day between semesters is open", entry)
+	}
+	if _, closed := year.Closed(day("2026-12-21")); closed {
+		t.Error("the campus is closed during the exams")
+	}
+	if entry, none := year.NoClasses(day("2026-12-21")); !none || entry.Kind != Exams {
+		t.Error("there are classes during the exams", entry)
+	}
+	if _, closed := (Year{}).Closed(day("2026-10-06")); closed {
+		t.Error("an empty calendar closes the campus")
+	}
+
+	// Importing again keeps the closures.
+	closure, err := NewClosure(day("2026-11-02"), day("2026-11-03"), "Storm", "34-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(&closure)
+	store.Replace([]DB.CalendarEntry{{Kind: Holiday, Name: "Eid", Start: "2026-11-20", End: "2026-11-20"}})
+	year, _ = Load(store)
+	if entry, closed := year.Closed(day("2026-11-03")); !closed || entry.Name != "Storm" {
+		t.Error("the closure was lost", entry)
+	}
+	if _, closed := year.Closed(day("2026-10-06")); closed {
+		t.Error("the old holiday was kept")
+	}
+	if _, err := NewClosure(day("2026-11-03"), day("2026-11-02"), "", "34-1"); err == nil {
+		t.Error("accepted a closure that ends before it starts")
+	}
+}
+
+func TestWriteFeed(t *testing.T) {
+	start := time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)
+	long := strings.Repeat("Passengers: Ahmed Ali, Sara Omar; ", 5) + "أحمد"
+	events := []FeedEvent{{UID: "carpool-12@guc-carpool", Start: start, End: start.Add(2 * time.Hour), Summary: "Carpool 12 to the GUC", Location: "Maadi, Cairo", Description: long}}
+	buffer := &strings.Builder{}
+	err := WriteFeed(buffer, "GUC Carpool", events, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Error("line is too long: " + line)
+		}
+	}
+
+	read, err := ReadEvents(strings.NewReader(buffer.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 1 || read[0]["UID"] != "carpool-12@guc-carpool" || read[0]["LOCATION"] != `Maadi\, Cairo` || icsEscapes.Replace(read[0]["DESCRIPTION"]) != long {
+		t.Error("wrong events", read)
+	}
+	if begins, _ := read[0].Time("DTSTART"); !begins.Equal(start) {
+		t.Error("wrong start", begins)
+	}
+}
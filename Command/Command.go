@@ -0,0 +1,139 @@
+package Command
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/When"
+)
+
+// The actions a command starts.
+const (
+	Create  = "create"
+	Request = "request"
+)
+
+// Command : a carpool or a request written in one message (eg. "create to guc from Rehab tomorrow 8am 3 seats" or "request from guc to Maadi today 4pm"). The details that were not written are left empty.
+type Command struct {
+	Action string // Create or Request, empty when the message only has details
+	// Direction is set when the message says which way the ride goes, FromGUC is the way.
+	Direction bool
+	FromGUC   bool
+	Place     string // where the student is picked up from or goes to, other than the GUC
+	Time      string // the words of the time, to be read with the When package
+	Seats     int
+}
+
+var (
+	action    = regexp.MustCompile(`(?i)^(create|offer|request|find|join)\b`)
+	seats     = regexp.MustCompile(`(?i)\b(\d+)\s*(?:more\s+)?(?:seats?|passengers?|places?|people)\b`)
+	direction = regexp.MustCompile(`(?i)\b(to|from)\s+(?:the\s+)?guc\b`)
+	place     = regexp.MustCompile(`(?i)\b(from|to)\s+`)
+	filler    = regexp.MustCompile(`(?i)\s+(?:please|pls|plz)$`)
+)
+
+// Phrases of Egyptian Arabic, in Arabic letters or in Franco-Arabic, and the commands they are read as. Longer phrases come before the words they contain.
+var intents = [][2]string{
+	{"3ayez a3mel carpool", "create"}, {"3awez a3mel carpool", "create"}, {"3ayza a3mel carpool", "create"}, {"3ayez asou2", "create"}, {"3awez asou2", "create"}, {"3ayza asou2", "create"},
+	{"hasou2", "create"}, {"ha-sou2", "create"}, {"hasoo2", "create"}, {"ha sou2", "create"}, {"عايز اسوق", "create"}, {"عايزة اسوق", "create"}, {"هسوق", "create"}, {"هاسوق", "create"},
+	{"3ayez arkab", "request"}, {"3awez arkab", "request"}, {"3ayza arkab", "request"}, {"3awza arkab", "request"}, {"ayez arkab", "request"}, {"3ayez tawseela", "request"}, {"3ayza tawseela", "request"}, {"me7tag tawseela", "request"}, {"m7tag tawseela", "request"},
+	{"عايز اركب", "request"}, {"عايزة اركب", "request"}, {"عاوز اركب", "request"}, {"عايز توصيلة", "request"}, {"عايزة توصيلة", "request"}, {"محتاج توصيلة", "request"}, {"محتاجة توصيلة", "request"},
+	{"rayeh el gam3a", "to guc"}, {"rayha el gam3a", "to guc"}, {"ray7 el gam3a", "to guc"}, {"raye7 el gam3a", "to guc"}, {"rayeh el guc", "to guc"}, {"rayha el guc", "to guc"}, {"رايح الجامعة", "to guc"}, {"رايحة الجامعة", "to guc"}, {"رايح الجامعه", "to guc"},
+	{"rage3 men el gam3a", "from guc"}, {"rag3a men el gam3a", "from guc"}, {"mrawa7 men el gam3a", "from guc"}, {"mrawa7a men el gam3a", "from guc"}, {"mrawa7 mn el gam3a", "from guc"}, {"5areg men el gam3a", "from guc"}, {"5arga men el gam3a", "from guc"}, {"nazel men el gam3a", "from guc"}, {"rage3 men el guc", "from guc"}, {"mrawa7 men el guc", "from guc"},
+	{"راجع من الجامعة", "from guc"}, {"راجعة من الجامعة", "from guc"}, {"مروح من الجامعة", "from guc"}, {"مروحة من الجامعة", "from guc"}, {"خارج من الجامعة", "from guc"}, {"خارجة من الجامعة", "from guc"}, {"نازل من الجامعة", "from guc"},
+}
+
+// The words that say where the ride goes, read as English once the message is written as a command in Arabic.
+var prepositions = [][2]string{
+	{"men", "from"}, {"mn", "from"}, {"من", "from"}, {"le", "to"}, {"ela", "to"}, {"إلى", "to"}, {"الى", "to"}, {"لـ", "to"},
+}
+
+// The phrases and words, found on their own and in any case.
+var (
+	intentWords      = compilePhrases(intents)
+	prepositionWords = compilePhrases(prepositions)
+)
+
+// Function that finds each phrase between spaces or at the ends of the message. Go's \b only knows Latin letters, so it can't be used for Arabic.
+func compilePhrases(phrases [][2]string) []*regexp.Regexp {
+	compiled := []*regexp.Regexp{}
+	for _, phrase := range phrases {
+		compiled = append(compiled, regexp.MustCompile(`(?i)(^|\s)`+regexp.QuoteMeta(phrase[0])+`(\s|$)`))
+	}
+	return compiled
+}
+
+// Translate : reads a command written in Egyptian Arabic or in Franco-Arabic as the English the bot understands (eg. "3ayez arkab rayeh el gam3a men Maadi bokra 8" as "request to guc from Maadi bokra 8"). The words for "from" and "to" are only read once the message says what the student wants, so the names of places are left alone. The time is left to the When package.
+func Translate(message string) string {
+	text, found := replacePhrases(strings.Join(strings.Fields(message), " "), intents, intentWords)
+	if !found {
+		return message
+	}
+	text, _ = replacePhrases(text, prepositions, prepositionWords)
+	return text
+}
+
+// Function that replaces the phrases found in the text, and checks one of them was.
+func replacePhrases(text string, phrases [][2]string, compiled []*regexp.Regexp) (string, bool) {
+	found := false
+	for i, exp := range compiled {
+		if !exp.MatchString(text) {
+			continue
+		}
+		found = true
+		// Replacing twice catches the phrases right next to each other, which share a space.
+		for j := 0; j < 2; j++ {
+			text = exp.ReplaceAllString(text, "${1}"+phrases[i][1]+"${2}")
+		}
+	}
+	return text, found
+}
+
+// Parse : reads the details of a carpool or a request written in one message. The place ends where the time starts.
+func Parse(message string) Command {
+	text := strings.Join(strings.Fields(message), " ")
+	c := Command{}
+	if parts := action.FindStringSubmatch(text); parts != nil {
+		c.Action = Create
+		if word := strings.ToLower(parts[1]); word == "request" || word == "find" || word == "join" {
+			c.Action = Request
+		}
+		text = text[len(parts[0]):]
+	}
+	if parts := seats.FindStringSubmatch(text); parts != nil {
+		c.Seats, _ = strconv.Atoi(parts[1])
+		text = strings.Replace(text, parts[0], " ", 1)
+	}
+	if parts := direction.FindStringSubmatch(text); parts != nil {
+		c.Direction = true
+		c.FromGUC = strings.EqualFold(parts[1], "from")
+		text = strings.Replace(text, parts[0], " ", 1)
+	}
+	// The place is the words after "from" or "to" that are not the time, like "Nasr City" in "from Nasr City at 8".
+	for _, match := range place.FindAllStringSubmatchIndex(text, -1) {
+		words := strings.Fields(text[match[1]:])
+		n := 0
+		for n < len(words) && !When.Starts(strings.Join(words[n:], " ")) && !strings.EqualFold(words[n], "from") && !strings.EqualFold(words[n], "to") {
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		c.Place = filler.ReplaceAllString(strings.Join(words[:n], " "), "")
+		if !c.Direction {
+			// Going to a place is leaving the GUC.
+			c.Direction = true
+			c.FromGUC = strings.EqualFold(text[match[2]:match[3]], "to")
+		}
+		text = text[:match[0]] + " " + strings.Join(words[n:], " ")
+		break
+	}
+	c.Time = strings.Join(strings.Fields(text), " ")
+	return c
+}
+
+// Details : checks the message has the direction, the place or the seats of a ride, and is not only a time.
+func (c Command) Details() bool {
+	return c.Direction || c.Place != "" || c.Seats > 0
+}
@@ -0,0 +1,47 @@
+package Command
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := map[string]Command{
+		"create to guc from Rehab tomorrow 8am 3 seats":     {Action: Create, Direction: true, FromGUC: false, Place: "Rehab", Time: "tomorrow 8am", Seats: 3},
+		"request from guc to Maadi today 4pm":               {Action: Request, Direction: true, FromGUC: true, Place: "Maadi", Time: "today 4pm"},
+		"Request to Maadi from 3 to 4pm":                    {Action: Request, Direction: true, FromGUC: true, Place: "Maadi", Time: "from 3 to 4pm"},
+		"offer from Nasr City at 7:30 give or take 15 mins": {Action: Create, Direction: true, FromGUC: false, Place: "Nasr City", Time: "at 7:30 give or take 15 mins"},
+		"request to the GUC from 6th of october bokra 8":    {Action: Request, Direction: true, FromGUC: false, Place: "6th of october", Time: "bokra 8"},
+		"create to guc":       {Action: Create, Direction: true, FromGUC: false},
+		"create tomorrow 8am": {Action: Create, Time: "tomorrow 8am"},
+		"to El Rehab please":  {Direction: true, FromGUC: true, Place: "El Rehab"},
+		"4 seats":             {Seats: 4},
+	}
+	for message, expected := range cases {
+		if c := Parse(message); c != expected {
+			t.Errorf("%s: got %+v", message, c)
+		}
+	}
+	if Parse("tomorrow 8am").Details() || !Parse("from Rehab").Details() {
+		t.Error("wrong details")
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	cases := map[string]string{
+		"3ayez arkab rayeh el gam3a men Maadi bokra 8": "request to guc from Maadi bokra 8",
+		"3ayza arkab le Nasr City el sa3a 3":           "request to Nasr City el sa3a 3",
+		"Hasou2 rage3 men el gam3a le Rehab 3 seats":   "create from guc to Rehab 3 seats",
+		"عايز اركب رايح الجامعة من المعادي بكرة 8":     "request to guc from المعادي بكرة 8",
+		"rayeh el gam3a":      "to guc",
+		"mrawa7 men el gam3a": "from guc",
+		"Le Reve compound":    "Le Reve compound",
+		"view all":            "view all",
+	}
+	for message, expected := range cases {
+		if translated := Translate(message); translated != expected {
+			t.Errorf("%s: got %s", message, translated)
+		}
+	}
+	c := Parse(Translate("3ayez arkab rayeh el gam3a men Maadi bokra 8"))
+	if c != (Command{Action: Request, Direction: true, FromGUC: false, Place: "Maadi", Time: "bokra 8"}) {
+		t.Errorf("got %+v", c)
+	}
+}
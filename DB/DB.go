@@ -1,11 +1,11 @@
 package DB
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
 	"github.com/night-codes/mgo-ai"
 	mgo "gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -23,6 +23,17 @@ var (
 	IsDrop = false
 )
 
+// Errors the students get when the carpool they picked can't be found or has no seat left.
+var (
+	errNoPost = Problem.New("problem.noPost", nil, "no post with this id")
+	errNoSeat = Problem.New("problem.noSeat", nil, "no seat available")
+)
+
+// Function that keeps the status of a carpool in an error, so it is written in the language of the student too.
+func statusWord(status string) *Problem.Error {
+	return Problem.New("status."+status, nil, status)
+}
+
 //CarpoolRequest begin
 /////////////////////////////
 
@@ -32,22 +43,31 @@ func UpdateDB(postid uint64, Longitude float64, Latitude float64, FromGUC bool,
 	if err != nil {
 		return err
 	}
+	defer session.Close()
 	c := session.DB("carpool").C("CarpoolRequest")
-	colQuerier := bson.M{"_id": postid}
-	change := bson.M{"$set": bson.M{"starttime": Time, "currentpassengers": CurrentPassengers, "possiblepassengers": PossiblePassengers, "longitude": Longitude, "latitude": Latitude, "fromguc": FromGUC, "availableseats": AvailableSeats, "time": time.Now()}}
+	// Carpools can't be changed once the ride started.
+	colQuerier := bson.M{"_id": postid, "status": bson.M{"$nin": []string{StatusDeparted, StatusCompleted, StatusCancelled}}}
+	change := bson.M{"$set": bson.M{"starttime": Time, "currentpassengers": CurrentPassengers, "possiblepassengers": PossiblePassengers, "longitude": Longitude, "latitude": Latitude, "fromguc": FromGUC, "availableseats": AvailableSeats, "status": seatStatus(AvailableSeats), "time": time.Now()}}
 	err = c.Update(colQuerier, change)
-	if err != nil {
-		fmt.Println(err.Error())
+	if err == mgo.ErrNotFound {
+		return Problem.New("problem.carpoolGone", nil, "this carpool does not exist or already departed")
 	}
+	return err
+}
+
+// SetCarpoolShift : sets how many minutes the driver of the carpool can leave before or after its start time.
+func SetCarpoolShift(PostID uint64, Shift int) error {
+	session, err := initDBSession()
 	if err != nil {
 		return err
 	}
-	fmt.Println("update succ")
 	defer session.Close()
-	return nil
+
+	c := session.DB("carpool").C("CarpoolRequest")
+	return c.UpdateId(PostID, bson.M{"$set": bson.M{"shift": Shift}})
 }
 
-//QueryAll return all the requests in the DB
+//QueryAll return all the requests in the DB that did not start yet
 func QueryAll() ([]CarpoolRequest, error) { //TODO should be renamed with the package name
 	session, err := initDBSession()
 	if err != nil {
@@ -56,7 +76,8 @@ func QueryAll() ([]CarpoolRequest, error) { //TODO should be renamed with the pa
 
 	c := session.DB("carpool").C("CarpoolRequest")
 	var results []CarpoolRequest
-	err = c.Find(bson.M{}).All(&results) //c.Find(bson.M{"name": "Ahmed"}).All(&results) for filtering
+	// Carpools that already started or departed are left out, they will be archived by ExpireCarpools.
+	err = c.Find(bson.M{"starttime": bson.M{"$gte": time.Now()}, "status": bson.M{"$nin": []string{StatusDeparted, StatusCompleted, StatusCancelled}}}).All(&results)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +102,65 @@ func GetPostByID(PostID uint64) ([]CarpoolRequest, error) {
 	return results, nil
 }
 
+// GetPostsByGUCID : return the carpools a driver has that are not archived yet
+func GetPostsByGUCID(GUCID string) ([]CarpoolRequest, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("CarpoolRequest")
+	var results []CarpoolRequest
+	err = c.Find(bson.M{"gucid": GUCID}).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetPostsByPassenger : return the carpools a passenger was accepted in that are not over yet
+func GetPostsByPassenger(GUCID string) ([]CarpoolRequest, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("CarpoolRequest")
+	var results []CarpoolRequest
+	err = c.Find(bson.M{
+		"currentpassengers": GUCID,
+		"status":            bson.M{"$nin": []string{StatusCompleted, StatusCancelled}},
+	}).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetPostsAround : return the carpools of a driver that start after From and before To, leaving out the carpool with the ID Except
+func GetPostsAround(GUCID string, From time.Time, To time.Time, Except uint64) ([]CarpoolRequest, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("CarpoolRequest")
+	var results []CarpoolRequest
+	err = c.Find(bson.M{
+		"gucid":     GUCID,
+		"_id":       bson.M{"$ne": Except},
+		"starttime": bson.M{"$gt": From, "$lt": To},
+		"status":    bson.M{"$nin": []string{StatusCompleted, StatusCancelled}},
+	}).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 //InsertDB insert func
 func InsertDB(req *CarpoolRequest) error {
 
@@ -105,7 +185,7 @@ func InsertDB(req *CarpoolRequest) error {
 	return nil
 }
 
-//DeleteDB : Delete carpool request
+//DeleteDB : Delete carpool request, keeping a cancelled copy of it in the archive
 func DeleteDB(PostID uint64) error {
 
 	session, err := initDBSession()
@@ -114,6 +194,21 @@ func DeleteDB(PostID uint64) error {
 	}
 	c := session.DB("carpool").C("CarpoolRequest")
 
+	posts, err := GetPostByID(PostID)
+	if err != nil {
+		return err
+	}
+	if len(posts) == 0 {
+		return errNoPost
+	}
+	if !CanTransition(posts[0].CurrentStatus(), StatusCancelled) {
+		return Problem.New("problem.deleteStatus", map[string]interface{}{"Status": statusWord(posts[0].CurrentStatus())}, "you can not delete a carpool that is "+posts[0].CurrentStatus())
+	}
+	// Keep a copy of the cancelled carpool in the archive.
+	err = saveToArchive(PostID, StatusCancelled)
+	if err != nil {
+		return err
+	}
 	PassengerRequests, err := GetPassengerRequestsByPostID(PostID)
 	if err != nil {
 		return err
@@ -276,7 +371,7 @@ func RejectPassenger(GUCID string, PostID uint64) error {
 		return err
 	}
 	if len(carpoolRequests) == 0 {
-		return errors.New("no post with this ID")
+		return errNoPost
 	}
 	wasCurrent := false
 	carpoolRequest := carpoolRequests[0]
@@ -310,10 +405,10 @@ func RejectPassenger(GUCID string, PostID uint64) error {
 	}
 
 	if len(passengerRequests) == 0 {
-		return errors.New("The passenger you're trying to reject did not request a carpool")
+		return Problem.New("problem.rejectUnknown", nil, "The passenger you're trying to reject did not request a carpool")
 	}
 	if PostID != passengerRequests[0].PostID {
-		return errors.New("You can not reject a passenger that did not request your carpool")
+		return Problem.New("problem.rejectOther", nil, "You can not reject a passenger that did not request your carpool")
 	}
 
 	passengerRequest := passengerRequests[0]
@@ -333,7 +428,7 @@ func AcceptPassenger(GUCID string, PostID uint64) error {
 		return err
 	}
 	if (len(posts)) == 0 {
-		return errors.New("no post with this id")
+		return errNoPost
 	}
 	possiblepassengers := posts[0].PossiblePassengers
 	currentpassengers := posts[0].CurrentPassengers
@@ -344,28 +439,30 @@ func AcceptPassenger(GUCID string, PostID uint64) error {
 		return err
 	}
 	if len(passengers) == 0 {
-		return errors.New("The passenger you're trying to accept did not request your carpool")
+		return Problem.New("problem.acceptUnknown", nil, "The passenger you're trying to accept did not request your carpool")
 	}
 	if PostID != passengers[0].PostID {
-		return errors.New("you can not accept a passenger that did not request your carpool")
+		return Problem.New("problem.acceptOther", nil, "you can not accept a passenger that did not request your carpool")
 	}
 	if passengers[0].Notify == 3 {
-		return errors.New(" This passenger has cancelled his request. ")
+		return Problem.New("problem.passengerCancelled", nil, "this passenger has cancelled their request")
 	}
 	if availableseats == 0 {
-		return errors.New("no seat available")
+		return errNoSeat
 	}
 
 	for index := 0; index < len(possiblepassengers); index++ {
 		if possiblepassengers[index] == GUCID {
 			currentpassengers = append(currentpassengers, GUCID)
 			possiblepassengers = append(possiblepassengers[:index], possiblepassengers[index+1:]...)
-			UpdatePassengerRequest(GUCID, passengers[0].Passenger.Name, PostID, 2) //notify
-			UpdateDB(PostID, posts[0].Longitude, posts[0].Latitude, posts[0].FromGUC, availableseats-1, currentpassengers, possiblepassengers, posts[0].Time)
-			return nil
+			err = UpdateDB(PostID, posts[0].Longitude, posts[0].Latitude, posts[0].FromGUC, availableseats-1, currentpassengers, possiblepassengers, posts[0].StartTime)
+			if err != nil {
+				return err
+			}
+			return UpdatePassengerRequest(GUCID, passengers[0].Passenger.Name, PostID, 2) //notify
 		}
 	}
-	return errors.New("not a possible passenger")
+	return Problem.New("problem.notPossible", nil, "not a possible passenger")
 }
 
 //Passenger request functions end
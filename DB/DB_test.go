@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
 )
 
 func TestGetPostByID(t *testing.T) {
@@ -59,8 +61,8 @@ func TestPassengerQueryALL(t *testing.T) {
 }
 
 func TestPassengerQueryONE(t *testing.T) {
-	fmt.Println(GetPassengerRequestByGUCID("4"))
-	fmt.Println(GetPassengerRequestByGUCID("3-4578"))
+	fmt.Println(GetPassengerRequestsByGUCID("4"))
+	fmt.Println(GetPassengerRequestsByGUCID("3-4578"))
 }
 
 func TestUpdatePassenger(t *testing.T) {
@@ -79,7 +81,7 @@ func TestAcceptPassenger(t *testing.T) {
 
 func TestInsert(t *testing.T) {
 
-	newC, err := NewCarpool("34-111", 31.25, 32.56, "mohamed", true, 5, "Jan 2, 2006 at 3:04pm (EET)") //newC will have default values
+	newC, err := NewCarpool("34-111", 31.25, 32.56, "mohamed", true, 5, time.Date(2006, 1, 2, 15, 4, 0, 0, Timezone.Location)) //newC will have default values
 	if err != nil {
 		fmt.Println(err.Error())
 		fmt.Println(newC)
@@ -95,7 +97,7 @@ func TestRejectPassenger(t *testing.T) {
 }
 
 func TestDBInsertGUCID(t *testing.T) {
-	v1, _ := GetPassengerRequestByGUCID("34-14269 ")
+	v1, _ := GetPassengerRequestsByGUCID("34-14269 ")
 	fmt.Println(v1[0].Notify)
 }
 
@@ -104,3 +106,62 @@ func TestDBIgepostid(t *testing.T) {
 	fmt.Println(v1[0].Notify)
 
 }
+
+func TestExpireCarpools(t *testing.T) {
+	newC, err := NewCarpool("34-222", 31.25, 32.56, "ahmed", true, 3, time.Date(2006, 1, 2, 15, 4, 0, 0, Timezone.Location)) //already started
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = InsertDB(&newC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := ExpireCarpools(time.Now())
+	if err != nil || len(expired) < 1 {
+		t.Error("expected the old carpool to be archived", expired, err)
+	}
+	res, _ := GetPostByID(newC.PostID)
+	if len(res) != 0 {
+		t.Error("archived carpool is still in the listings")
+	}
+	// No one checked in, so no one can tell it took place.
+	session, err := initDBSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	var archived ArchivedCarpool
+	err = session.DB("carpool").C("ArchivedCarpool").FindId(newC.PostID).One(&archived)
+	if err != nil || archived.Status != StatusExpired {
+		t.Error("expected the carpool to be archived as expired", archived.Status, err)
+	}
+}
+
+func TestCheckedIn(t *testing.T) {
+	carpool := CarpoolRequest{CurrentPassengers: []string{"34-1", "34-2"}, CheckedIn: []string{"34-1"}}
+	if !checkedIn(carpool, "34-1") || checkedIn(carpool, "34-2") {
+		t.Error("wrong check-ins", carpool.CheckedIn)
+	}
+}
+
+func TestCanTransition(t *testing.T) {
+	if !CanTransition("", StatusDeparted) || !CanTransition(StatusFull, StatusOpen) || !CanTransition(StatusDeparted, StatusCompleted) {
+		t.Error("allowed transition was refused")
+	}
+	if CanTransition(StatusDeparted, StatusOpen) || CanTransition(StatusCompleted, StatusDeparted) || CanTransition(StatusCancelled, StatusOpen) {
+		t.Error("forbidden transition was allowed")
+	}
+}
+
+func TestAverageStars(t *testing.T) {
+	if averageStars(nil) != 0 {
+		t.Error("no ratings should average to 0")
+	}
+	ratings := []Rating{{Stars: 5}, {Stars: 4}, {Stars: 3}, {Stars: 4}}
+	if averageStars(ratings) != 4 {
+		t.Error("wrong average", averageStars(ratings))
+	}
+	if ratingToString(AverageRating{}) != "" || ratingToString(AverageRating{Stars: 4, Ratings: 1}) != " (4.0/5 from 1 rating)" {
+		t.Error("wrong rating text")
+	}
+}
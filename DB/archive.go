@@ -0,0 +1,97 @@
+package DB
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ArchiveCarpool : moves a carpool to the archive with the given status, together with its passengers, and removes its passenger requests and reminders.
+func ArchiveCarpool(PostID uint64, Status string) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	err = saveToArchive(PostID, Status)
+	if err != nil {
+		return err
+	}
+	_, err = session.DB("carpool").C("PassengerRequest").RemoveAll(bson.M{"postid": PostID})
+	if err != nil {
+		return err
+	}
+	err = DeleteScheduledJobsByPostID(PostID)
+	if err != nil {
+		return err
+	}
+	_, err = session.DB("carpool").C("CarpoolRequest").RemoveAll(bson.M{"_id": PostID})
+	return err
+}
+
+// saveToArchive : copies a carpool and its accepted passengers to the archive, without removing anything.
+func saveToArchive(PostID uint64, Status string) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	posts, err := GetPostByID(PostID)
+	if err != nil {
+		return err
+	}
+	if len(posts) == 0 {
+		return errNoPost
+	}
+	passengerRequests, err := GetPassengerRequestsByPostID(PostID)
+	if err != nil {
+		return err
+	}
+	passengers := make([]Passenger, 0)
+	for _, passengerRequest := range passengerRequests {
+		for _, gucID := range posts[0].CurrentPassengers {
+			if passengerRequest.Passenger.GUCID == gucID {
+				passengers = append(passengers, passengerRequest.Passenger)
+			}
+		}
+	}
+	archived := ArchivedCarpool{
+		CarpoolRequest: posts[0],
+		Passengers:     passengers,
+		ArchivedAt:     time.Now(),
+	}
+	archived.Status = Status
+
+	// Upsert, so two instances archiving the same carpool don't fail on each other.
+	_, err = session.DB("carpool").C("ArchivedCarpool").UpsertId(PostID, archived)
+	return err
+}
+
+// ExpireCarpools : archives every carpool that started before the given time, and returns the ones that were archived, with the status they were archived with. A carpool the driver started or a passenger checked in to is completed, the others expired, since no one can tell if they took place.
+func ExpireCarpools(before time.Time) ([]CarpoolRequest, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	var expired []CarpoolRequest
+	err = session.DB("carpool").C("CarpoolRequest").Find(bson.M{"starttime": bson.M{"$lt": before}}).All(&expired)
+	if err != nil {
+		return nil, err
+	}
+	for index, carpoolRequest := range expired {
+		status := StatusExpired
+		if carpoolRequest.CurrentStatus() == StatusDeparted || len(carpoolRequest.CheckedIn) > 0 {
+			status = StatusCompleted
+		}
+		err = ArchiveCarpool(carpoolRequest.PostID, status)
+		if err != nil {
+			return expired[:index], err
+		}
+		expired[index].Status = status
+	}
+	return expired, nil
+}
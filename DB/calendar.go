@@ -0,0 +1,83 @@
+package DB
+
+import (
+	"time"
+
+	"github.com/night-codes/mgo-ai"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// GetCalendar : returns all the entries of the academic calendar.
+func GetCalendar() ([]CalendarEntry, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Calendar")
+	var results []CalendarEntry
+	err = c.Find(nil).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ReplaceCalendar : replaces the imported entries of the academic calendar with new ones. The closures added by the admins are kept.
+func ReplaceCalendar(entries []CalendarEntry) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Calendar")
+	_, err = c.RemoveAll(bson.M{"kind": bson.M{"$ne": "closure"}})
+	if err != nil {
+		return err
+	}
+	ai.Connect(c)
+	for i := range entries {
+		entries[i].ID = ai.Next("Calendar")
+		err = c.Insert(&entries[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertCalendarEntry : adds one entry to the academic calendar, giving it the next ID.
+func InsertCalendarEntry(entry *CalendarEntry) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Calendar")
+	ai.Connect(c)
+	entry.ID = ai.Next("Calendar")
+	return c.Insert(entry)
+}
+
+// GetPostsBetween : returns the carpools of all drivers that start between the two times and are not over yet.
+func GetPostsBetween(From time.Time, To time.Time) ([]CarpoolRequest, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("CarpoolRequest")
+	var results []CarpoolRequest
+	err = c.Find(bson.M{
+		"starttime": bson.M{"$gte": From, "$lt": To},
+		"status":    bson.M{"$nin": []string{StatusCompleted, StatusCancelled}},
+	}).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
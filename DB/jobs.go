@@ -0,0 +1,91 @@
+package DB
+
+import (
+	"time"
+
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// SaveScheduledJob : inserts the job, or replaces it if a job with the same ID exists.
+func SaveScheduledJob(job *ScheduledJob) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("ScheduledJob")
+	_, err = c.UpsertId(job.ID, job)
+	return err
+}
+
+// GetDueScheduledJobs : returns the jobs that should have been sent by now and were not sent yet.
+func GetDueScheduledJobs(now time.Time) ([]ScheduledJob, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("ScheduledJob")
+	var results []ScheduledJob
+	err = c.Find(bson.M{"sent": false, "runat": bson.M{"$lte": now}}).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ClaimScheduledJob : marks the job as taken by an instance. Returns false if another instance already holds it and its lease did not run out.
+func ClaimScheduledJob(ID string, instance string, now time.Time, lease time.Duration) (bool, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("ScheduledJob")
+	colQuerier := bson.M{
+		"_id":  ID,
+		"sent": false,
+		"$or": []bson.M{
+			{"claimedby": ""},
+			{"claimedat": bson.M{"$lt": now.Add(-lease)}},
+		},
+	}
+	change := bson.M{"$set": bson.M{"claimedby": instance, "claimedat": now}}
+	err = c.Update(colQuerier, change)
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MarkScheduledJobSent : marks the job as done so it never gets sent again.
+func MarkScheduledJobSent(ID string) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("ScheduledJob")
+	return c.UpdateId(ID, bson.M{"$set": bson.M{"sent": true}})
+}
+
+// DeleteScheduledJobsByPostID : removes all the jobs of a carpool.
+func DeleteScheduledJobsByPostID(PostID uint64) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("ScheduledJob")
+	_, err = c.RemoveAll(bson.M{"postid": PostID})
+	return err
+}
@@ -0,0 +1,49 @@
+package DB
+
+import (
+	"time"
+
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// AcquireLock : takes the lock for the owner if it is free or expired. Returns false if someone else holds it.
+func AcquireLock(Key string, Owner string, ttl time.Duration) (bool, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Lock")
+	now := time.Now()
+	err = c.Insert(&Lock{Key: Key, Owner: Owner, ExpiresAt: now.Add(ttl)})
+	if err == nil {
+		return true, nil
+	}
+	if !mgo.IsDup(err) {
+		return false, err
+	}
+	// Someone has the lock, take it over only if it expired.
+	err = c.Update(bson.M{"_id": Key, "expiresat": bson.M{"$lt": now}}, bson.M{"$set": bson.M{"owner": Owner, "expiresat": now.Add(ttl)}})
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseLock : frees the lock, if the owner still holds it.
+func ReleaseLock(Key string, Owner string) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Lock")
+	_, err = c.RemoveAll(bson.M{"_id": Key, "owner": Owner})
+	return err
+}
@@ -0,0 +1,202 @@
+package DB
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// RatingWindow : how long after a ride its driver and passengers can still rate each other.
+const RatingWindow = 7 * 24 * time.Hour
+
+// GetPendingRatings : returns the people the student rode with in the last week and did not rate yet. Only the passengers that checked in to a completed ride rode with its driver.
+func GetPendingRatings(GUCID string) ([]PendingRating, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	var carpools []ArchivedCarpool
+	colQuerier := bson.M{
+		"status":      StatusCompleted,
+		"checkedin.0": bson.M{"$exists": true},
+		"archivedat":  bson.M{"$gte": time.Now().Add(-RatingWindow)},
+		"$or":         []bson.M{{"gucid": GUCID}, {"checkedin": GUCID}},
+	}
+	err = session.DB("carpool").C("ArchivedCarpool").Find(colQuerier).All(&carpools)
+	if err != nil {
+		return nil, err
+	}
+	var given []Rating
+	err = session.DB("carpool").C("Rating").Find(bson.M{"rater": GUCID}).All(&given)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]PendingRating, 0)
+	for _, carpool := range carpools {
+		// The driver rates the passengers, and the passengers rate the driver.
+		var others []PendingRating
+		if strings.EqualFold(carpool.GUCID, GUCID) {
+			for _, passenger := range carpool.Passengers {
+				if checkedIn(carpool.CarpoolRequest, passenger.GUCID) {
+					others = append(others, PendingRating{PostID: carpool.PostID, GUCID: passenger.GUCID, Name: passenger.Name})
+				}
+			}
+		} else {
+			others = append(others, PendingRating{PostID: carpool.PostID, GUCID: carpool.GUCID, Name: carpool.Name})
+		}
+		for _, other := range others {
+			rated := false
+			for _, rating := range given {
+				if rating.PostID == other.PostID && strings.EqualFold(rating.Ratee, other.GUCID) {
+					rated = true
+				}
+			}
+			if !rated {
+				pending = append(pending, other)
+			}
+		}
+	}
+	// Show the names from the profiles, the archive only has the names the students had back then.
+	GUCIDs := make([]string, 0)
+	for _, other := range pending {
+		GUCIDs = append(GUCIDs, other.GUCID)
+	}
+	names, _ := GetUserNames(GUCIDs)
+	for i := range pending {
+		pending[i].Name = nameOr(names, pending[i].GUCID, pending[i].Name)
+	}
+	return pending, nil
+}
+
+// RateUser : saves the rating one student gives another they rode with. Returns the ride the rating was for.
+func RateUser(Rater string, Ratee string, Stars int, Comment string) (uint64, error) {
+	if Stars < 1 || Stars > 5 {
+		return 0, Problem.New("problem.stars", nil, "ratings are from 1 to 5 stars")
+	}
+	pending, err := GetPendingRatings(Rater)
+	if err != nil {
+		return 0, err
+	}
+	var ride *PendingRating
+	for i := range pending {
+		if strings.EqualFold(pending[i].GUCID, Ratee) {
+			ride = &pending[i]
+			break
+		}
+	}
+	if ride == nil {
+		return 0, errOnceAfterRide
+	}
+
+	session, err := initDBSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.Close()
+	rating := Rating{
+		PostID:  ride.PostID,
+		Rater:   Rater,
+		Ratee:   ride.GUCID,
+		Stars:   Stars,
+		Comment: Comment,
+		Time:    time.Now(),
+	}
+	// A student rates the same person once per ride, even if they send the rating twice at the same time, thanks to the index made by EnsureIndexes.
+	err = session.DB("carpool").C("Rating").Insert(&rating)
+	if mgo.IsDup(err) {
+		return 0, errOnceAfterRide
+	}
+	if err != nil {
+		return 0, err
+	}
+	return ride.PostID, nil
+}
+
+// EnsureIndexes : creates the indexes the collections need, once when the server starts.
+func EnsureIndexes() error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	// A student rates the same person once per ride.
+	return session.DB("carpool").C("Rating").EnsureIndex(mgo.Index{Key: []string{"postid", "rater", "ratee"}, Unique: true})
+}
+
+var errOnceAfterRide = Problem.New("problem.rateOnce", nil, "you can only rate someone you rode with in the last week, and only once per ride")
+
+// GetAverageRating : returns the average stars a student got, and from how many ratings.
+func GetAverageRating(GUCID string) (float64, int, error) {
+	averages, err := GetAverageRatings([]string{GUCID})
+	if err != nil {
+		return 0, 0, err
+	}
+	return averages[GUCID].Stars, averages[GUCID].Ratings, nil
+}
+
+// GetAverageRatings : returns the average rating of each of the students, looked up with one query. The students without ratings are left out.
+func GetAverageRatings(GUCIDs []string) (map[string]AverageRating, error) {
+	averages := map[string]AverageRating{}
+	if len(GUCIDs) == 0 {
+		return averages, nil
+	}
+	session, err := initDBSession()
+	if err != nil {
+		return averages, err
+	}
+	defer session.Close()
+
+	var ratings []Rating
+	err = session.DB("carpool").C("Rating").Find(bson.M{"ratee": bson.M{"$in": GUCIDs}}).All(&ratings)
+	if err != nil {
+		return averages, err
+	}
+	byRatee := map[string][]Rating{}
+	for _, rating := range ratings {
+		byRatee[rating.Ratee] = append(byRatee[rating.Ratee], rating)
+	}
+	for GUCID, given := range byRatee {
+		averages[GUCID] = AverageRating{Stars: averageStars(given), Ratings: len(given)}
+	}
+	return averages, nil
+}
+
+// ratingToString : returns the average rating of a student to show next to them (eg. " (4.5/5 from 2 ratings)"), or nothing if they have no ratings.
+func ratingToString(average AverageRating) string {
+	if average.Ratings == 0 {
+		return ""
+	}
+	str := " (" + strconv.FormatFloat(average.Stars, 'f', 1, 64) + "/5 from " + strconv.Itoa(average.Ratings) + " rating"
+	if average.Ratings > 1 {
+		str += "s"
+	}
+	return str + ")"
+}
+
+// checkedIn : returns true if the passenger checked in to the carpool.
+func checkedIn(carpool CarpoolRequest, GUCID string) bool {
+	for _, passenger := range carpool.CheckedIn {
+		if strings.EqualFold(passenger, GUCID) {
+			return true
+		}
+	}
+	return false
+}
+
+func averageStars(ratings []Rating) float64 {
+	if len(ratings) == 0 {
+		return 0
+	}
+	total := 0
+	for _, rating := range ratings {
+		total += rating.Stars
+	}
+	return float64(total) / float64(len(ratings))
+}
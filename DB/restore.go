@@ -0,0 +1,117 @@
+package DB
+
+import "github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+
+// RestoreCarpool : brings back a carpool the driver deleted from the archive. The passengers that didn't ask to join, or get accepted in, another carpool since are put back in it.
+func RestoreCarpool(PostID uint64) (CarpoolRequest, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return CarpoolRequest{}, err
+	}
+	defer session.Close()
+
+	var archived ArchivedCarpool
+	err = session.DB("carpool").C("ArchivedCarpool").FindId(PostID).One(&archived)
+	if err != nil {
+		return CarpoolRequest{}, Problem.New("problem.noDeleted", nil, "no deleted carpool with this id")
+	}
+	if archived.Status != StatusCancelled {
+		return CarpoolRequest{}, Problem.New("problem.notDeleted", nil, "only a deleted carpool can be brought back")
+	}
+	carpoolRequest := archived.CarpoolRequest
+	currentPassengers, err := restorablePassengers(carpoolRequest.CurrentPassengers, carpoolRequest, 2)
+	if err != nil {
+		return CarpoolRequest{}, err
+	}
+	possiblePassengers, err := restorablePassengers(carpoolRequest.PossiblePassengers, carpoolRequest, 1)
+	if err != nil {
+		return CarpoolRequest{}, err
+	}
+	carpoolRequest.AvailableSeats += len(carpoolRequest.CurrentPassengers) - len(currentPassengers)
+	carpoolRequest.CurrentPassengers = currentPassengers
+	carpoolRequest.PossiblePassengers = possiblePassengers
+	carpoolRequest.Status = seatStatus(carpoolRequest.AvailableSeats)
+
+	err = session.DB("carpool").C("CarpoolRequest").Insert(&carpoolRequest)
+	if err != nil {
+		return CarpoolRequest{}, err
+	}
+	err = session.DB("carpool").C("ArchivedCarpool").RemoveId(PostID)
+	if err != nil {
+		return CarpoolRequest{}, err
+	}
+	return carpoolRequest, nil
+}
+
+// restorablePassengers : returns the passengers whose request to join the carpool was turned down when it was deleted, and sets their requests back to the given notification. Passengers waiting for, or accepted in, another carpool since are left out, so no one is booked twice. The other way of a round trip doesn't count.
+func restorablePassengers(GUCIDs []string, carpoolRequest CarpoolRequest, Notify uint8) ([]string, error) {
+	PostID := carpoolRequest.PostID
+	restored := []string{}
+	for _, GUCID := range GUCIDs {
+		passengerRequests, err := GetPassengerRequestsByGUCID(GUCID)
+		if err != nil {
+			return nil, err
+		}
+		var request *PassengerRequest
+		bookedElsewhere := false
+		for i, passengerRequest := range passengerRequests {
+			if passengerRequest.PostID == PostID {
+				request = &passengerRequests[i]
+			} else if passengerRequest.PostID != carpoolRequest.LinkedPostID && (passengerRequest.Notify == 1 || passengerRequest.Notify == 2) {
+				bookedElsewhere = true
+			}
+		}
+		if request == nil || request.Notify != 0 || bookedElsewhere {
+			continue
+		}
+		err = UpdatePassengerRequest(GUCID, request.Passenger.Name, PostID, Notify)
+		if err != nil {
+			return nil, err
+		}
+		restored = append(restored, GUCID)
+	}
+	return restored, nil
+}
+
+// RestorePassenger : puts a passenger that was rejected, or that cancelled their request, back in the carpool, as accepted or as waiting to be.
+func RestorePassenger(GUCID string, PostID uint64, Accepted bool) error {
+	posts, err := GetPostByID(PostID)
+	if err != nil {
+		return err
+	}
+	if len(posts) == 0 {
+		return errNoPost
+	}
+	passengerRequests, err := GetPassengerRequestByGUCIDAndPostID(GUCID, PostID)
+	if err != nil {
+		return err
+	}
+	if len(passengerRequests) == 0 || (passengerRequests[0].Notify != 0 && passengerRequests[0].Notify != 3) {
+		return Problem.New("problem.notOut", nil, "the passenger is not out of the carpool anymore")
+	}
+	carpoolRequest := posts[0]
+	for _, passenger := range append(append([]string{}, carpoolRequest.CurrentPassengers...), carpoolRequest.PossiblePassengers...) {
+		if passenger == GUCID {
+			return Problem.New("problem.alreadyIn", nil, "the passenger is already in the carpool")
+		}
+	}
+	availableSeats := carpoolRequest.AvailableSeats
+	currentPassengers := carpoolRequest.CurrentPassengers
+	possiblePassengers := carpoolRequest.PossiblePassengers
+	notify := uint8(1)
+	if Accepted {
+		if availableSeats == 0 {
+			return errNoSeat
+		}
+		availableSeats--
+		currentPassengers = append(currentPassengers, GUCID)
+		notify = 2
+	} else {
+		possiblePassengers = append(possiblePassengers, GUCID)
+	}
+	err = UpdateDB(PostID, carpoolRequest.Longitude, carpoolRequest.Latitude, carpoolRequest.FromGUC, availableSeats, currentPassengers, possiblePassengers, carpoolRequest.StartTime)
+	if err != nil {
+		return err
+	}
+	return UpdatePassengerRequest(GUCID, passengerRequests[0].Passenger.Name, PostID, notify)
+}
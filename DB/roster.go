@@ -0,0 +1,60 @@
+package DB
+
+import (
+	"time"
+
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// GetStudent : returns the student with the given GUC ID from the roster.
+func GetStudent(GUCID string) (Student, bool, error) {
+	var student Student
+	session, err := initDBSession()
+	if err != nil {
+		return student, false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Student")
+	err = c.FindId(GUCID).One(&student)
+	if err == mgo.ErrNotFound {
+		return student, false, nil
+	}
+	if err != nil {
+		return student, false, err
+	}
+	return student, true, nil
+}
+
+// UpsertStudent : inserts the student in the roster, or replaces them if they are already there. Returns true if the student is new.
+func UpsertStudent(student *Student) (bool, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Student")
+	info, err := c.UpsertId(student.GUCID, student)
+	if err != nil {
+		return false, err
+	}
+	return info.UpsertedId != nil, nil
+}
+
+// DeactivateStudentsNotImportedSince : deactivates the active students that were not on any import since the given time, and returns how many were deactivated.
+func DeactivateStudentsNotImportedSince(since time.Time) (int, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Student")
+	info, err := c.UpdateAll(bson.M{"active": true, "importedat": bson.M{"$lt": since}}, bson.M{"$set": bson.M{"active": false}})
+	if err != nil {
+		return 0, err
+	}
+	return info.Updated, nil
+}
@@ -0,0 +1,15 @@
+package DB
+
+import "gopkg.in/mgo.v2/bson"
+
+// SetLinkedCarpool : sets the other leg of the round trip of a carpool, or 0 to make it one way.
+func SetLinkedCarpool(PostID uint64, LinkedPostID uint64) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("CarpoolRequest")
+	return c.UpdateId(PostID, bson.M{"$set": bson.M{"linkedpostid": LinkedPostID}})
+}
@@ -0,0 +1,159 @@
+package DB
+
+import (
+	"time"
+
+	"github.com/night-codes/mgo-ai"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// InsertSchedule : inserts a new weekly schedule, giving it the next ID.
+func InsertSchedule(schedule *Schedule) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Schedule")
+	ai.Connect(c)
+	schedule.ID = ai.Next("Schedule")
+	return c.Insert(schedule)
+}
+
+// SaveSchedule : saves the changes to a weekly schedule.
+func SaveSchedule(schedule *Schedule) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Schedule")
+	return c.UpdateId(schedule.ID, schedule)
+}
+
+// GetSchedule : returns the weekly schedule with the ID.
+func GetSchedule(ID uint64) (Schedule, bool, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return Schedule{}, false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Schedule")
+	var schedule Schedule
+	err = c.FindId(ID).One(&schedule)
+	if err == mgo.ErrNotFound {
+		return Schedule{}, false, nil
+	}
+	if err != nil {
+		return Schedule{}, false, err
+	}
+	return schedule, true, nil
+}
+
+// GetSchedulesByGUCID : returns the weekly schedules of a driver.
+func GetSchedulesByGUCID(GUCID string) ([]Schedule, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Schedule")
+	var results []Schedule
+	err = c.Find(bson.M{"gucid": GUCID}).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetActiveSchedules : returns the weekly schedules that are not paused and did not end before the time.
+func GetActiveSchedules(now time.Time) ([]Schedule, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Schedule")
+	var results []Schedule
+	err = c.Find(bson.M{"paused": false, "enddate": bson.M{"$gte": now.AddDate(0, 0, -1)}}).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetScheduleOccurrences : returns the carpools created from a weekly schedule that start between the two times.
+func GetScheduleOccurrences(ScheduleID uint64, from time.Time, to time.Time) ([]CarpoolRequest, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("CarpoolRequest")
+	var results []CarpoolRequest
+	err = c.Find(bson.M{"scheduleid": ScheduleID, "starttime": bson.M{"$gte": from, "$lt": to}}).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// InsertOccurrence : inserts a carpool created from a weekly schedule, with its standing passengers asking to join it.
+func InsertOccurrence(req *CarpoolRequest) error {
+	err := InsertDB(req)
+	if err != nil {
+		return err
+	}
+	for _, GUCID := range req.PossiblePassengers {
+		passengerRequest, err := NewPassengerRequest(GUCID, DisplayName(GUCID, ""), req.PostID, 1)
+		if err != nil {
+			return err
+		}
+		err = InsertPassengerRequest(&passengerRequest)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithoutPassenger : returns the carpool without the passenger, giving back their seat if they were accepted.
+func WithoutPassenger(carpool CarpoolRequest, GUCID string) CarpoolRequest {
+	possiblePassengers := []string{}
+	for _, passenger := range carpool.PossiblePassengers {
+		if passenger != GUCID {
+			possiblePassengers = append(possiblePassengers, passenger)
+		}
+	}
+	currentPassengers := []string{}
+	for _, passenger := range carpool.CurrentPassengers {
+		if passenger != GUCID {
+			currentPassengers = append(currentPassengers, passenger)
+		}
+	}
+	carpool.AvailableSeats += len(carpool.CurrentPassengers) - len(currentPassengers)
+	carpool.PossiblePassengers = possiblePassengers
+	carpool.CurrentPassengers = currentPassengers
+	return carpool
+}
+
+// RemoveOccurrencePassenger : takes a passenger who stopped being a standing passenger out of a carpool created from their schedule.
+func RemoveOccurrencePassenger(PostID uint64, GUCID string) error {
+	posts, err := GetPostByID(PostID)
+	if err != nil || len(posts) == 0 {
+		return err
+	}
+	carpool := WithoutPassenger(posts[0], GUCID)
+	err = DeletePassengerRequest(PostID, GUCID)
+	if err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+	return UpdateDB(PostID, carpool.Longitude, carpool.Latitude, carpool.FromGUC, carpool.AvailableSeats, carpool.CurrentPassengers, carpool.PossiblePassengers, carpool.StartTime)
+}
@@ -0,0 +1,90 @@
+package DB
+
+import (
+	"time"
+
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// SaveSession : inserts the session, or replaces it if it already exists.
+func SaveSession(stored *StoredSession) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Session")
+	_, err = c.UpsertId(stored.UUID, stored)
+	return err
+}
+
+// GetSession : returns the session with the given uuid.
+func GetSession(UUID string) (StoredSession, bool, error) {
+	var stored StoredSession
+	session, err := initDBSession()
+	if err != nil {
+		return stored, false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Session")
+	err = c.FindId(UUID).One(&stored)
+	if err == mgo.ErrNotFound {
+		return stored, false, nil
+	}
+	if err != nil {
+		return stored, false, err
+	}
+	return stored, true, nil
+}
+
+// DeleteSession : removes the session with the given uuid.
+func DeleteSession(UUID string) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Session")
+	_, err = c.RemoveAll(bson.M{"_id": UUID})
+	return err
+}
+
+// FindSessionByGUCID : returns a session of the student other than the one with the given uuid.
+func FindSessionByGUCID(GUCID string, except string) (StoredSession, bool, error) {
+	var stored StoredSession
+	session, err := initDBSession()
+	if err != nil {
+		return stored, false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Session")
+	err = c.Find(bson.M{"gucid": GUCID, "_id": bson.M{"$ne": except}}).One(&stored)
+	if err == mgo.ErrNotFound {
+		return stored, false, nil
+	}
+	if err != nil {
+		return stored, false, err
+	}
+	return stored, true, nil
+}
+
+// DeleteExpiredSessions : removes the sessions that expired before the given time, and returns how many were removed.
+func DeleteExpiredSessions(now time.Time) (int, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("Session")
+	info, err := c.RemoveAll(bson.M{"expiresat": bson.M{"$lt": now}})
+	if err != nil {
+		return 0, err
+	}
+	return info.Removed, nil
+}
@@ -0,0 +1,54 @@
+package DB
+
+import (
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// SetEmailOptOut : turns the email notifications of a student off, or back on. Students without a profile keep it in EmailSettings.
+func SetEmailOptOut(GUCID string, OptOut bool) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	err = session.DB("carpool").C("User").UpdateId(GUCID, bson.M{"$set": bson.M{"notifications.emailoptout": OptOut}})
+	if err != mgo.ErrNotFound {
+		return err
+	}
+	_, err = session.DB("carpool").C("EmailSettings").UpsertId(GUCID, EmailSettings{GUCID: GUCID, OptOut: OptOut})
+	return err
+}
+
+// IsEmailOptedOut : returns true if the student does not want to receive emails.
+func IsEmailOptedOut(GUCID string) (bool, error) {
+	user, found, err := GetUser(GUCID)
+	if err != nil || found {
+		return user.Notifications.EmailOptOut, err
+	}
+
+	// Students that did not log in since there are profiles still have their preference in EmailSettings.
+	session, err := initDBSession()
+	if err != nil {
+		return false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("EmailSettings")
+	var settings EmailSettings
+	err = c.FindId(GUCID).One(&settings)
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return settings.OptOut, nil
+}
+
+// IsReminderOptedOut : returns true if the student does not want departure reminders.
+func IsReminderOptedOut(GUCID string) (bool, error) {
+	user, _, err := GetUser(GUCID)
+	return user.Notifications.RemindersOptOut, err
+}
@@ -0,0 +1,150 @@
+package DB
+
+import (
+	"strings"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// The states a carpool goes through.
+const (
+	StatusOpen      = "open"      // taking passengers
+	StatusFull      = "full"      // no seats left
+	StatusDeparted  = "departed"  // the driver started the ride
+	StatusCompleted = "completed" // the ride is over
+	StatusCancelled = "cancelled" // the driver deleted the carpool
+	StatusExpired   = "expired"   // its time passed without the driver starting it or a passenger checking in, only in the archive
+)
+
+// transitions : the states a carpool can move to from each state.
+var transitions = map[string][]string{
+	StatusOpen:      {StatusFull, StatusDeparted, StatusCompleted, StatusCancelled},
+	StatusFull:      {StatusOpen, StatusDeparted, StatusCompleted, StatusCancelled},
+	StatusDeparted:  {StatusCompleted},
+	StatusCompleted: {},
+	StatusCancelled: {},
+}
+
+// CurrentStatus : returns the status of the carpool. Carpools made before there were states are open.
+func (c *CarpoolRequest) CurrentStatus() string {
+	if c.Status == "" {
+		return StatusOpen
+	}
+	return c.Status
+}
+
+// CanTransition : returns true if a carpool is allowed to move from one state to the other.
+func CanTransition(from string, to string) bool {
+	if from == "" {
+		from = StatusOpen
+	}
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCarpoolStatus : moves a carpool to a new state, if the move is allowed from the state it is in.
+func SetCarpoolStatus(PostID uint64, Status string) error {
+	posts, err := GetPostByID(PostID)
+	if err != nil {
+		return err
+	}
+	if len(posts) == 0 {
+		return errNoPost
+	}
+	current := posts[0].CurrentStatus()
+	if !CanTransition(current, Status) {
+		return Problem.New("problem.transition", map[string]interface{}{"From": statusWord(current), "To": statusWord(Status)}, "a carpool that is "+current+" can not become "+Status)
+	}
+
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	c := session.DB("carpool").C("CarpoolRequest")
+	// Only update if nobody changed the status in the meantime.
+	colQuerier := bson.M{"_id": PostID, "status": posts[0].Status}
+	if posts[0].Status == "" {
+		colQuerier["status"] = bson.M{"$in": []interface{}{nil, ""}}
+	}
+	err = c.Update(colQuerier, bson.M{"$set": bson.M{"status": Status}})
+	if err == mgo.ErrNotFound {
+		return Problem.New("problem.changed", nil, "the carpool changed while updating it, please try again")
+	}
+	return err
+}
+
+// StartRide : marks the carpool as departed.
+func StartRide(PostID uint64) error {
+	return SetCarpoolStatus(PostID, StatusDeparted)
+}
+
+// EndRide : marks the carpool as completed and moves it to the archive.
+func EndRide(PostID uint64) error {
+	posts, err := GetPostByID(PostID)
+	if err != nil {
+		return err
+	}
+	if len(posts) == 0 {
+		return errNoPost
+	}
+	if posts[0].CurrentStatus() != StatusDeparted {
+		return Problem.New("problem.endBeforeStartRide", nil, "you can only end a ride after starting it")
+	}
+	err = SetCarpoolStatus(PostID, StatusCompleted)
+	if err != nil {
+		return err
+	}
+	return ArchiveCarpool(PostID, StatusCompleted)
+}
+
+// CheckIn : marks an accepted passenger as being in the car.
+func CheckIn(GUCID string, PostID uint64) error {
+	posts, err := GetPostByID(PostID)
+	if err != nil {
+		return err
+	}
+	if len(posts) == 0 {
+		return errNoPost
+	}
+	status := posts[0].CurrentStatus()
+	if status == StatusCompleted || status == StatusCancelled {
+		return Problem.New("problem.already", map[string]interface{}{"Status": statusWord(status)}, "this carpool is already "+status)
+	}
+	accepted := false
+	for _, val := range posts[0].CurrentPassengers {
+		if strings.EqualFold(val, GUCID) {
+			accepted = true
+		}
+	}
+	if !accepted {
+		return Problem.New("problem.checkInNotAccepted", nil, "you can only check in to a carpool that accepted you")
+	}
+	for _, val := range posts[0].CheckedIn {
+		if strings.EqualFold(val, GUCID) {
+			return Problem.New("problem.checkedIn", nil, "you already checked in")
+		}
+	}
+
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	c := session.DB("carpool").C("CarpoolRequest")
+	return c.UpdateId(PostID, bson.M{"$addToSet": bson.M{"checkedin": GUCID}})
+}
+
+// seatStatus : the status of a carpool that is taking passengers, given how many seats are left.
+func seatStatus(AvailableSeats int) string {
+	if AvailableSeats == 0 {
+		return StatusFull
+	}
+	return StatusOpen
+}
@@ -2,9 +2,12 @@ package DB
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DirectionsAPI"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
 )
 
 //CarpoolRequest : request made by students to,from guc
@@ -14,18 +17,45 @@ type CarpoolRequest struct {
 	Latitude           float64
 	PostID             uint64 `bson:"_id,omitempty"`
 	Time               time.Time
-	StartTime          time.Time // time parsing done outside database for multiple format
+	StartTime          time.Time // in UTC, time parsing done outside database for multiple format
 	CurrentPassengers  []string
 	PossiblePassengers []string
 	Name               string
 	FromGUC            bool
 	AvailableSeats     int
+	Status             string
+	CheckedIn          []string // passengers who said they're in the car
+	Vehicle            Vehicle  // the car of the ride, empty for carpools made before there were vehicles
+	LinkedPostID       uint64   // the other leg of a round trip, 0 if the carpool is one way
+	ScheduleID         uint64   // the weekly schedule the carpool is an occurrence of, 0 if it was posted on its own
+	Shift              int      // how many minutes the driver can leave before or after the start time
+}
+
+// MaxShift : the most minutes a driver can leave before or after the start time of their carpool.
+const MaxShift = 60
+
+// Earliest : the earliest time the driver can leave at.
+func (c *CarpoolRequest) Earliest() time.Time {
+	return c.StartTime.Add(-time.Duration(c.Shift) * time.Minute)
+}
+
+// Latest : the latest time the driver can leave at.
+func (c *CarpoolRequest) Latest() time.Time {
+	return c.StartTime.Add(time.Duration(c.Shift) * time.Minute)
+}
+
+// Overlaps : checks the driver can leave at a time between earliest and latest, the times a passenger can leave at.
+func (c *CarpoolRequest) Overlaps(earliest time.Time, latest time.Time) bool {
+	return !c.Earliest().After(latest) && !earliest.After(c.Latest())
 }
 
 // CarpoolToString : Take a Carpool Request as a subject and returns a string describing it.
 func (c *CarpoolRequest) CarpoolToString() string {
+	GUCIDs := append(append([]string{c.GUCID}, c.CurrentPassengers...), c.PossiblePassengers...)
+	names, _ := GetUserNames(GUCIDs)
+	averages, _ := GetAverageRatings(GUCIDs)
 	str := "->\n\tPostID: " + strconv.FormatUint(c.PostID, 10)
-	str += ",\tDriver Name: " + c.Name
+	str += ",\tDriver Name: " + nameOr(names, c.GUCID, c.Name) + ratingToString(averages[c.GUCID])
 	//str += ",\n\tGUCID: " + c.GUCID
 	if c.FromGUC {
 		str += "\n\tLeaving the GUC"
@@ -39,19 +69,29 @@ func (c *CarpoolRequest) CarpoolToString() string {
 	} else {
 		str += "\n\tAddress: " + address
 	}
-	str += ",\n\tStart Time: " + c.StartTime.Format("Jan 2, 2006 at 3:04pm (EET)")
+	str += ",\n\tStart Time: " + Timezone.Format(c.StartTime)
+	if c.Shift > 0 {
+		str += ", give or take " + strconv.Itoa(c.Shift) + " minutes"
+	}
+	if c.LinkedPostID != 0 {
+		str += ",\n\tRound trip with carpool " + strconv.FormatUint(c.LinkedPostID, 10)
+	}
+	if c.ScheduleID != 0 {
+		str += ",\n\tEvery week, schedule " + strconv.FormatUint(c.ScheduleID, 10)
+	}
 	str += ",\n\tAvailable Seats: " + strconv.FormatInt(int64(c.AvailableSeats), 10)
+	str += ",\n\tStatus: " + c.CurrentStatus()
 	if len(c.CurrentPassengers) == 0 {
 		str += ",\n\tNo Current Passengers"
 	} else {
 		str += ",\n\tCurrent Passengers: ("
 		for i := 0; i < len(c.CurrentPassengers); i++ {
-			str += c.CurrentPassengers[i]
+			str += describePassenger(names, c.CurrentPassengers[i]) + ratingToString(averages[c.CurrentPassengers[i]])
 			if i != (len(c.CurrentPassengers) - 1) {
 				str += ", "
 			}
-			str += ")"
 		}
+		str += ")"
 	}
 
 	if len(c.PossiblePassengers) == 0 {
@@ -59,7 +99,7 @@ func (c *CarpoolRequest) CarpoolToString() string {
 	} else {
 		str += ",\nrequesting Passengers: ("
 		for i := 0; i < len(c.PossiblePassengers); i++ {
-			str += c.PossiblePassengers[i]
+			str += describePassenger(names, c.PossiblePassengers[i]) + ratingToString(averages[c.PossiblePassengers[i]])
 			if i != (len(c.PossiblePassengers) - 1) {
 				str += ", "
 			}
@@ -70,24 +110,52 @@ func (c *CarpoolRequest) CarpoolToString() string {
 	return str
 }
 
-//NewCarpool create new carpool request return the newly created request
-func NewCarpool(GUCID string, Longitude float64, Latitude float64, Name string, FromGUC bool, AvailableSeats int, StartTime string) (req CarpoolRequest, err error) {
+// CarpoolPerson : the driver or a passenger of a carpool, with their name and rating looked up.
+type CarpoolPerson struct {
+	GUCID   string
+	Name    string // empty for a passenger without a profile
+	Stars   float64
+	Ratings int
+}
+
+// People : looks up the driver, the accepted passengers and the requesting passengers of the carpool.
+func (c *CarpoolRequest) People() (CarpoolPerson, []CarpoolPerson, []CarpoolPerson) {
+	GUCIDs := append(append([]string{c.GUCID}, c.CurrentPassengers...), c.PossiblePassengers...)
+	names, _ := GetUserNames(GUCIDs)
+	averages, _ := GetAverageRatings(GUCIDs)
+	person := func(GUCID string, fallback string) CarpoolPerson {
+		return CarpoolPerson{GUCID: GUCID, Name: nameOr(names, GUCID, fallback), Stars: averages[GUCID].Stars, Ratings: averages[GUCID].Ratings}
+	}
+	current := []CarpoolPerson{}
+	for _, GUCID := range c.CurrentPassengers {
+		current = append(current, person(GUCID, ""))
+	}
+	possible := []CarpoolPerson{}
+	for _, GUCID := range c.PossiblePassengers {
+		possible = append(possible, person(GUCID, ""))
+	}
+	return person(c.GUCID, c.Name), current, possible
+}
+
+//NewCarpool create new carpool request return the newly created request, the start time is kept in UTC
+func NewCarpool(GUCID string, Longitude float64, Latitude float64, Name string, FromGUC bool, AvailableSeats int, StartTime time.Time) (req CarpoolRequest, err error) {
 	mySlice1 := make([]string, 0)
-	stTime, err := time.Parse("Jan 2, 2006 at 3:04pm (EET)", StartTime)
-	if err != nil {
-		return req, err
+	if StartTime.IsZero() {
+		return req, Problem.New("problem.startTime", nil, "the carpool needs a start time")
 	}
 	req = CarpoolRequest{
 		GUCID:              GUCID,
 		Longitude:          Longitude,
 		Latitude:           Latitude,
 		Time:               time.Now(),
-		StartTime:          stTime,
+		StartTime:          StartTime.UTC(),
 		CurrentPassengers:  mySlice1,
 		PossiblePassengers: mySlice1,
 		Name:               Name,
 		FromGUC:            FromGUC,
 		AvailableSeats:     AvailableSeats,
+		Status:             StatusOpen,
+		CheckedIn:          mySlice1,
 	}
 	return req, nil
 }
@@ -125,3 +193,165 @@ func NewPassengerRequest(GUCID string, Name string, PostID uint64, Notify uint8)
 	}
 	return req, nil
 }
+
+// EmailSettings : the email notification preferences of a student.
+type EmailSettings struct {
+	GUCID  string `bson:"_id"`
+	OptOut bool
+}
+
+// ScheduledJob : a reminder about a carpool that has to be sent at a certain time.
+type ScheduledJob struct {
+	ID        string `bson:"_id"`
+	PostID    uint64
+	Minutes   int // how many minutes before the start time of the carpool
+	RunAt     time.Time
+	Sent      bool
+	ClaimedBy string // the server instance that is sending it
+	ClaimedAt time.Time
+}
+
+// Schedule : a carpool a driver drives every week, on some days of the week. The carpools of the next days are created from it ahead of time.
+type Schedule struct {
+	ID                 uint64 `bson:"_id,omitempty"`
+	GUCID              string
+	Name               string
+	Days               []time.Weekday
+	Hour               int
+	Minute             int
+	Shift              int // how many minutes the driver can leave before or after the time
+	FromGUC            bool
+	Latitude           float64
+	Longitude          float64
+	Seats              int // seats for passengers, including the ones taken by the standing passengers
+	Vehicle            Vehicle
+	StartDate          time.Time
+	EndDate            time.Time // the last day a carpool is created on
+	Paused             bool
+	Skipped            []string // the days (eg. "2026-11-03") with no carpool
+	StandingPassengers []string // passengers that are added to every carpool
+}
+
+// CalendarEntry : days of the academic calendar that are not like the others, from Start to End (eg. "2026-10-06", both included).
+type CalendarEntry struct {
+	ID      uint64 `bson:"_id,omitempty"`
+	Kind    string // semester, holiday, exams or closure
+	Name    string
+	Start   string
+	End     string
+	AddedBy string `bson:",omitempty"` // the admin that closed the campus, closures are not imported
+}
+
+// ArchivedCarpool : a carpool that was completed, expired or cancelled, kept together with the passengers that were accepted in it.
+type ArchivedCarpool struct {
+	CarpoolRequest `bson:",inline"`
+	Passengers     []Passenger
+	ArchivedAt     time.Time
+}
+
+// Rating : the stars and comment one side of a completed ride gave the other.
+type Rating struct {
+	PostID  uint64
+	Rater   string // GUCID of the one who rated
+	Ratee   string // GUCID of the one who was rated
+	Stars   int
+	Comment string
+	Time    time.Time
+}
+
+// AverageRating : the average stars a student got, and from how many ratings.
+type AverageRating struct {
+	Stars   float64
+	Ratings int
+}
+
+// PendingRating : someone a student rode with and did not rate yet.
+type PendingRating struct {
+	PostID uint64
+	GUCID  string
+	Name   string
+}
+
+// StoredSession : the session of a chat user, saved so it survives restarts.
+type StoredSession struct {
+	UUID      string `bson:"_id"`
+	GUCID     string
+	ExpiresAt time.Time
+	Data      []byte // the encoded session values
+}
+
+// Lock : a lock shared by all server instances, held by one of them until it expires.
+type Lock struct {
+	Key       string `bson:"_id"`
+	Owner     string
+	ExpiresAt time.Time
+}
+
+// VerificationCode : the one-time code emailed to a student to prove they own a GUC ID.
+type VerificationCode struct {
+	GUCID     string `bson:"_id"`
+	Hash      string // the code itself is never stored
+	ExpiresAt time.Time
+	Attempts  int         // wrong guesses of the current code
+	Sends     []time.Time // when codes were sent, to limit how often
+}
+
+// Student : a student on the roster of the university. Only active students can log in.
+type Student struct {
+	GUCID      string `bson:"_id"`
+	Name       string // the official full name
+	Email      string
+	Faculty    string
+	Gender     string
+	Active     bool      // false once the student is no longer on the roster (eg. graduated)
+	ImportedAt time.Time // the last import the student was on the roster of
+}
+
+// User : the profile of a student. It is the one place their name and contact details are kept, other documents only keep their GUC ID.
+type User struct {
+	GUCID         string `bson:"_id"`
+	Name          string
+	Phone         string
+	Language      string // "en" or "ar"
+	Notifications NotificationSettings
+	HomeArea      string
+	Vehicles      []Vehicle
+	Timetable     []Class
+	Subscriptions []RideSubscription
+	CalendarToken string `bson:",omitempty"` // the secret in the URL of their calendar feed
+}
+
+// Class : a class on the weekly timetable of a student. The times are in minutes after midnight.
+type Class struct {
+	Day   time.Weekday
+	Start int
+	End   int
+	Name  string
+}
+
+// RideSubscription : rides a student wants to hear about, on a day of the week, leaving between two times (in minutes after midnight).
+type RideSubscription struct {
+	Day      time.Weekday
+	FromGUC  bool
+	Earliest int
+	Latest   int
+}
+
+// Vehicle : a car a student drives.
+type Vehicle struct {
+	Make     string // eg. Hyundai Elantra
+	Colour   string
+	Plate    string
+	Capacity int // seats for passengers, not counting the driver
+}
+
+// VehicleToString : describes the car so passengers can find it (eg. "white Hyundai Elantra, plate ABC 123").
+func (v *Vehicle) VehicleToString() string {
+	return strings.TrimSpace(v.Colour+" "+v.Make) + ", plate " + v.Plate
+}
+
+// NotificationSettings : which notifications a student does not want.
+type NotificationSettings struct {
+	EmailOptOut     bool // no emails at all
+	RemindersOptOut bool // no departure reminders
+}
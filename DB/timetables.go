@@ -0,0 +1,29 @@
+package DB
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// GetSubscribers : returns the students that want to hear about rides on the day of the week, in the direction, leaving between the Earliest and Latest minutes after midnight.
+func GetSubscribers(Day time.Weekday, FromGUC bool, Earliest int, Latest int) ([]User, error) {
+	session, err := initDBSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("User")
+	var results []User
+	err = c.Find(bson.M{"subscriptions": bson.M{"$elemMatch": bson.M{
+		"day":      Day,
+		"fromguc":  FromGUC,
+		"earliest": bson.M{"$lte": Latest},
+		"latest":   bson.M{"$gte": Earliest},
+	}}}).All(&results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
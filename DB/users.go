@@ -0,0 +1,261 @@
+package DB
+
+import (
+	"strconv"
+	"strings"
+
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// The languages a student can choose.
+const (
+	LanguageEnglish = "en"
+	LanguageArabic  = "ar"
+)
+
+// EnsureUser : creates the profile of a student the first time they log in, and keeps their name up to date after that.
+func EnsureUser(GUCID string, Name string) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("User")
+	info, err := c.UpsertId(GUCID, bson.M{
+		"$set":         bson.M{"name": Name},
+		"$setOnInsert": bson.M{"language": LanguageEnglish},
+	})
+	if err != nil || info.UpsertedId == nil {
+		return err
+	}
+	// Bring over the email preference saved before there were profiles.
+	var settings EmailSettings
+	err = session.DB("carpool").C("EmailSettings").FindId(GUCID).One(&settings)
+	if err == mgo.ErrNotFound || (err == nil && !settings.OptOut) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return c.UpdateId(GUCID, bson.M{"$set": bson.M{"notifications.emailoptout": true}})
+}
+
+// GetUser : returns the profile of a student.
+func GetUser(GUCID string) (User, bool, error) {
+	var user User
+	session, err := initDBSession()
+	if err != nil {
+		return user, false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("User")
+	err = c.FindId(GUCID).One(&user)
+	if err == mgo.ErrNotFound {
+		return user, false, nil
+	}
+	if err != nil {
+		return user, false, err
+	}
+	return user, true, nil
+}
+
+// GetUserByCalendarToken : returns the profile of the student whose calendar feed has the token.
+func GetUserByCalendarToken(Token string) (User, bool, error) {
+	var user User
+	session, err := initDBSession()
+	if err != nil {
+		return user, false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("User")
+	err = c.Find(bson.M{"calendartoken": Token}).One(&user)
+	if err == mgo.ErrNotFound {
+		return user, false, nil
+	}
+	if err != nil {
+		return user, false, err
+	}
+	return user, true, nil
+}
+
+// The fields of a profile that are saved on their own, as they are kept in the database.
+const (
+	UserPhone           = "phone"
+	UserLanguage        = "language"
+	UserHomeArea        = "homearea"
+	UserVehicles        = "vehicles"
+	UserEmailOptOut     = "notifications.emailoptout"
+	UserRemindersOptOut = "notifications.remindersoptout"
+	UserTimetable       = "timetable"
+	UserSubscriptions   = "subscriptions"
+	UserCalendarToken   = "calendartoken"
+)
+
+// SaveUser : saves the fields of the profile (eg. UserPhone), leaving the others as they are in the database, so changes made to them at the same time are kept. A profile that doesn't exist yet is created with its name and language. Without fields, the whole profile is replaced.
+func SaveUser(user *User, fields ...string) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("User")
+	if len(fields) == 0 {
+		_, err = c.UpsertId(user.GUCID, user)
+		return err
+	}
+	values, err := userFields(user, fields)
+	if err != nil {
+		return err
+	}
+	onInsert := bson.M{"name": user.Name}
+	if _, changed := values[UserLanguage]; !changed {
+		onInsert[UserLanguage] = user.Language
+	}
+	_, err = c.UpsertId(user.GUCID, bson.M{"$set": values, "$setOnInsert": onInsert})
+	return err
+}
+
+// SetUserFields : copies the fields (eg. UserPhone) of one profile to another, as SaveUser saves them.
+func SetUserFields(to *User, from *User, fields []string) error {
+	values, err := userFields(from, fields)
+	if err != nil {
+		return err
+	}
+	doc, err := userDocument(to)
+	if err != nil {
+		return err
+	}
+	for field, value := range values {
+		parts := strings.Split(field, ".")
+		parent := doc
+		for _, part := range parts[:len(parts)-1] {
+			child, isDoc := parent[part].(bson.M)
+			if !isDoc {
+				child = bson.M{}
+				parent[part] = child
+			}
+			parent = child
+		}
+		parent[parts[len(parts)-1]] = value
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	*to = User{}
+	return bson.Unmarshal(raw, to)
+}
+
+// userFields : the values of the fields of the profile, as they are saved in the database.
+func userFields(user *User, fields []string) (bson.M, error) {
+	doc, err := userDocument(user)
+	if err != nil {
+		return nil, err
+	}
+	values := bson.M{}
+	for _, field := range fields {
+		var value interface{} = doc
+		for _, part := range strings.Split(field, ".") {
+			parent, _ := value.(bson.M)
+			value = parent[part]
+		}
+		values[field] = value
+	}
+	return values, nil
+}
+
+// userDocument : the profile as it is saved in the database.
+func userDocument(user *User) (bson.M, error) {
+	raw, err := bson.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	doc := bson.M{}
+	err = bson.Unmarshal(raw, &doc)
+	return doc, err
+}
+
+// GetUserNames : returns the names of the students with the given GUC IDs, for the ones that have a profile.
+func GetUserNames(GUCIDs []string) (map[string]string, error) {
+	names := map[string]string{}
+	if len(GUCIDs) == 0 {
+		return names, nil
+	}
+	session, err := initDBSession()
+	if err != nil {
+		return names, err
+	}
+	defer session.Close()
+
+	var users []User
+	c := session.DB("carpool").C("User")
+	err = c.Find(bson.M{"_id": bson.M{"$in": GUCIDs}}).Select(bson.M{"name": 1}).All(&users)
+	if err != nil {
+		return names, err
+	}
+	for _, user := range users {
+		names[user.GUCID] = user.Name
+	}
+	return names, nil
+}
+
+// DisplayName : returns the name of a student from their profile, or the given name if they don't have one.
+func DisplayName(GUCID string, fallback string) string {
+	names, _ := GetUserNames([]string{GUCID})
+	return nameOr(names, GUCID, fallback)
+}
+
+// nameOr : returns the name of the student in names, or the fallback if it is not there.
+func nameOr(names map[string]string, GUCID string, fallback string) string {
+	if name := names[GUCID]; name != "" {
+		return name
+	}
+	return fallback
+}
+
+// describePassenger : shows a passenger by name and GUC ID, or only the GUC ID if they have no profile.
+func describePassenger(names map[string]string, GUCID string) string {
+	name := nameOr(names, GUCID, "")
+	if name == "" {
+		return GUCID
+	}
+	return name + " (" + GUCID + ")"
+}
+
+// UserToString : describes the profile of a student.
+func (u *User) UserToString() string {
+	notSet := func(value string) string {
+		if strings.TrimSpace(value) == "" {
+			return "not set"
+		}
+		return value
+	}
+	onOff := func(optOut bool) string {
+		if optOut {
+			return "off"
+		}
+		return "on"
+	}
+	language := "English"
+	if u.Language == LanguageArabic {
+		language = "Arabic"
+	}
+	str := "->\n\tName: " + u.Name + ",\tGUC ID: " + u.GUCID
+	str += ",\n\tPhone: " + notSet(u.Phone)
+	str += ",\n\tLanguage: " + language
+	str += ",\n\tHome Area: " + notSet(u.HomeArea)
+	if len(u.Vehicles) == 0 {
+		str += ",\n\tVehicles: none"
+	}
+	for i, vehicle := range u.Vehicles {
+		str += ",\n\tVehicle " + strconv.Itoa(i+1) + ": " + vehicle.VehicleToString() + " (" + strconv.Itoa(vehicle.Capacity) + " seats)"
+	}
+	str += ",\n\tEmails: " + onOff(u.Notifications.EmailOptOut)
+	str += ",\tDeparture Reminders: " + onOff(u.Notifications.RemindersOptOut)
+	return str + "\n\n"
+}
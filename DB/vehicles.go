@@ -0,0 +1,15 @@
+package DB
+
+import "gopkg.in/mgo.v2/bson"
+
+// SetCarpoolVehicle : sets the car the driver of the carpool is driving.
+func SetCarpoolVehicle(PostID uint64, Vehicle Vehicle) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("CarpoolRequest")
+	return c.UpdateId(PostID, bson.M{"$set": bson.M{"vehicle": Vehicle}})
+}
@@ -0,0 +1,38 @@
+package DB
+
+import (
+	mgo "gopkg.in/mgo.v2"
+)
+
+// GetVerificationCode : returns the verification code of a student.
+func GetVerificationCode(GUCID string) (VerificationCode, bool, error) {
+	var code VerificationCode
+	session, err := initDBSession()
+	if err != nil {
+		return code, false, err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("VerificationCode")
+	err = c.FindId(GUCID).One(&code)
+	if err == mgo.ErrNotFound {
+		return code, false, nil
+	}
+	if err != nil {
+		return code, false, err
+	}
+	return code, true, nil
+}
+
+// SaveVerificationCode : inserts the verification code, or replaces the one the student had.
+func SaveVerificationCode(code *VerificationCode) error {
+	session, err := initDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	c := session.DB("carpool").C("VerificationCode")
+	_, err = c.UpsertId(code.GUCID, code)
+	return err
+}
@@ -2,7 +2,9 @@ package DirectionsAPI
 
 import (
 	"context"
+	"errors"
 	"log"
+	"strings"
 
 	strip "github.com/grokify/html-strip-tags-go"
 	"github.com/jasonwinn/geocoder"
@@ -19,6 +21,51 @@ func GetAddress(lat float64, lon float64) (string, error) {
 	return address.Street + " " + address.City, nil
 }
 
+// The areas students ride from and to the most, at about their middle, so they don't have to be looked up.
+var places = map[string][2]float64{
+	"rehab":               {30.0586, 31.4914},
+	"madinaty":            {30.1070, 31.6385},
+	"shorouk":             {30.1520, 31.6070},
+	"tagamoa":             {30.0084, 31.4285},
+	"fifth settlement":    {30.0084, 31.4285},
+	"new cairo":           {30.0300, 31.4700},
+	"maadi":               {29.9602, 31.2569},
+	"nasr city":           {30.0561, 31.3300},
+	"madinet nasr":        {30.0561, 31.3300},
+	"heliopolis":          {30.0911, 31.3225},
+	"masr el gedida":      {30.0911, 31.3225},
+	"mokattam":            {30.0110, 31.2960},
+	"downtown":            {30.0444, 31.2357},
+	"zamalek":             {30.0609, 31.2197},
+	"dokki":               {30.0380, 31.2118},
+	"mohandessin":         {30.0566, 31.2013},
+	"sheikh zayed":        {30.0200, 30.9800},
+	"6th of october":      {29.9384, 30.9135},
+	"october":             {29.9384, 30.9135},
+	"obour":               {30.2280, 31.4700},
+	"cairo festival city": {30.0290, 31.4080},
+}
+
+// GetLocation : A function that returns the latitude and longitude of a place in Cairo given its name (eg. "Rehab" or "El Maadi")
+func GetLocation(place string) (float64, float64, error) {
+	name := strings.Join(strings.Fields(strings.ToLower(place)), " ")
+	for _, prefix := range []string{"el ", "al "} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	if location, found := places[name]; found {
+		return location[0], location[1], nil
+	}
+	geocoder.SetAPIKey("X3XD20Z6CoOItFBqhZHp8Moxo1st3YAz")
+	lat, lon, err := geocoder.Geocode(place + ", Cairo, Egypt")
+	if err != nil {
+		return 0, 0, err
+	}
+	if lat == 0 && lon == 0 {
+		return 0, 0, errors.New("there is no place called " + place)
+	}
+	return lat, lon, nil
+}
+
 //GetRoute : return a string with the instructions to follow to reach the destination
 func GetRoute(from string, to string) (string, error) {
 
@@ -0,0 +1,338 @@
+package Messages
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DirectionsAPI"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Recurring"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timetable"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/When"
+)
+
+// Params : the values that get filled into a message.
+type Params map[string]interface{}
+
+// Bidirectional text marks, so that the IDs, times and names written in Latin letters don't break the order of an Arabic line.
+const (
+	rightToLeftMark = "\u200f"
+	isolateStart    = "\u2068"
+	isolateEnd      = "\u2069"
+)
+
+// messages : the parsed templates of the catalog, by key and language.
+var messages = map[string]map[string]*template.Template{}
+
+func init() {
+	for key, translations := range catalog {
+		messages[key] = map[string]*template.Template{}
+		for language, text := range translations {
+			messages[key][language] = template.Must(template.New(key + "." + language).Option("missingkey=zero").Parse(text))
+		}
+	}
+}
+
+// Get : writes the message with the key in the language of the student, filled with the parameters. Messages that are not translated yet are written in English.
+func Get(language string, key string, params Params) string {
+	translations, found := messages[key]
+	if !found {
+		log.Printf("no message called %s\n", key)
+		return key
+	}
+	tmpl, found := translations[language]
+	if !found {
+		language = DB.LanguageEnglish
+		tmpl = translations[language]
+	}
+	values := Params{}
+	for name, value := range params {
+		values[name] = value
+		// The values are kept in their own direction inside Arabic text, unless they are paragraphs of their own or already are.
+		if str, isString := value.(string); isString && language == DB.LanguageArabic && str != "" && !strings.Contains(str, "\n") && !strings.Contains(str, isolateStart) {
+			values[name] = isolateStart + str + isolateEnd
+		}
+	}
+	body := &bytes.Buffer{}
+	err := tmpl.Execute(body, values)
+	if err != nil {
+		log.Printf("could not write message %s: %s\n", key, err.Error())
+		return key
+	}
+	return body.String()
+}
+
+// Error : explains the error in the language of the student, if it is a problem they can fix. Other errors are written as they are.
+func Error(language string, err error) string {
+	var problem *Problem.Error
+	if !errors.As(err, &problem) {
+		return err.Error()
+	}
+	params := Params{}
+	for name, value := range problem.Params {
+		params[name] = value
+		// The words and reasons inside the problem are explained too (eg. the status of a carpool).
+		if inner, isError := value.(error); isError {
+			params[name] = Error(language, inner)
+		}
+	}
+	return Get(language, problem.Key, params)
+}
+
+// arabicMonths : the names of the months as they are said in Egypt.
+var arabicMonths = []string{"يناير", "فبراير", "مارس", "أبريل", "مايو", "يونيو", "يوليو", "أغسطس", "سبتمبر", "أكتوبر", "نوفمبر", "ديسمبر"}
+
+// arabicWeekdays : the names of the days of the week, from Sunday.
+var arabicWeekdays = []string{"الأحد", "الاثنين", "الثلاثاء", "الأربعاء", "الخميس", "الجمعة", "السبت"}
+
+// Time : writes the time at the GUC in the language of the student (eg. "Oct 30, 2026 at 8:00am (EET)" or "30 أكتوبر 2026 الساعة 8:00 ص (EET)").
+func Time(language string, t time.Time) string {
+	if language != DB.LanguageArabic {
+		return Timezone.Format(t)
+	}
+	t = t.In(Timezone.Location)
+	return arabicDay(t) + " الساعة " + arabicClock(t) + " (" + t.Format("MST") + ")"
+}
+
+// Expression : writes the time or the window of time the student wrote in their language (eg. "Sunday, Oct 25, 2026 between 7:30am and 8:15am (EET)").
+func Expression(language string, e When.Expression) string {
+	if language != DB.LanguageArabic {
+		return When.ExpressionToString(e)
+	}
+	start := e.Start.In(Timezone.Location)
+	if !e.Window() {
+		return Weekday(language, start.Weekday()) + "، " + Time(language, start)
+	}
+	end := e.End.In(Timezone.Location)
+	return Weekday(language, start.Weekday()) + "، " + arabicDay(start) + " بين " + arabicClock(start) + " و" + arabicClock(end) + " (" + end.Format("MST") + ")"
+}
+
+// arabicDay : writes the day of the time (eg. "30 أكتوبر 2026").
+func arabicDay(t time.Time) string {
+	return strconv.Itoa(t.Day()) + " " + arabicMonths[t.Month()-1] + " " + strconv.Itoa(t.Year())
+}
+
+// arabicClock : writes the hour of the time, morning or evening (eg. "8:00 ص").
+func arabicClock(t time.Time) string {
+	if t.Hour() >= 12 {
+		return t.Format("3:04") + " م"
+	}
+	return t.Format("3:04") + " ص"
+}
+
+// Day : writes the day of the time at the GUC in the language of the student (eg. "Monday, Nov 2, 2026" or "الاثنين، 2 نوفمبر 2026").
+func Day(language string, t time.Time) string {
+	if language != DB.LanguageArabic {
+		return Timezone.FormatDay(t)
+	}
+	t = t.In(Timezone.Location)
+	return Weekday(language, t.Weekday()) + "، " + arabicDay(t)
+}
+
+// Clock : writes the minutes after midnight as a time of the day in the language of the student (eg. "8:30am" or "8:30 ص").
+func Clock(language string, minutes int) string {
+	if language != DB.LanguageArabic {
+		return Timetable.Clock(minutes)
+	}
+	return arabicClock(time.Date(2000, 1, 1, minutes/60, minutes%60, 0, 0, time.UTC))
+}
+
+// Weekday : writes the day of the week in the language of the student.
+func Weekday(language string, day time.Weekday) string {
+	if language != DB.LanguageArabic {
+		return day.String()
+	}
+	return arabicWeekdays[day]
+}
+
+// CarpoolToString : describes a carpool in the language of the student. In Arabic, every line is written right to left, with the values in Latin letters kept as they are.
+func CarpoolToString(language string, c *DB.CarpoolRequest) string {
+	if language != DB.LanguageArabic {
+		return c.CarpoolToString()
+	}
+	driver, current, possible := c.People()
+	address, err := DirectionsAPI.GetAddress(c.Latitude, c.Longitude)
+	if err != nil {
+		address = ""
+	}
+	return carpoolLines(language, c, driver, current, possible, address)
+}
+
+// carpoolLines : writes the lines describing a carpool, once the people in it and its address are looked up.
+func carpoolLines(language string, c *DB.CarpoolRequest, driver DB.CarpoolPerson, current []DB.CarpoolPerson, possible []DB.CarpoolPerson, address string) string {
+	lines := []string{
+		Get(language, "carpool.id", Params{"PostID": strconv.FormatUint(c.PostID, 10)}) + "، " + Get(language, "carpool.driver", Params{"Driver": personToString(language, driver)}),
+	}
+	if c.FromGUC {
+		lines = append(lines, Get(language, "carpool.fromGUC", nil))
+	} else {
+		lines = append(lines, Get(language, "carpool.toGUC", nil))
+	}
+	if address != "" {
+		lines = append(lines, Get(language, "carpool.address", Params{"Address": address}))
+	} else {
+		lines = append(lines, Get(language, "carpool.location", Params{"Latitude": strconv.FormatFloat(c.Latitude, 'f', -1, 64), "Longitude": strconv.FormatFloat(c.Longitude, 'f', -1, 64)}))
+	}
+	start := Get(language, "carpool.start", Params{"Time": Time(language, c.StartTime)})
+	if c.Shift > 0 {
+		start += Get(language, "shift", Params{"Minutes": c.Shift})
+	}
+	lines = append(lines, start)
+	if c.LinkedPostID != 0 {
+		lines = append(lines, Get(language, "carpool.roundTrip", Params{"PostID": strconv.FormatUint(c.LinkedPostID, 10)}))
+	}
+	if c.ScheduleID != 0 {
+		lines = append(lines, Get(language, "carpool.schedule", Params{"ScheduleID": strconv.FormatUint(c.ScheduleID, 10)}))
+	}
+	lines = append(lines, Get(language, "carpool.seats", Params{"Seats": c.AvailableSeats}))
+	lines = append(lines, Get(language, "carpool.status", Params{"Status": Get(language, "status."+c.CurrentStatus(), nil)}))
+	lines = append(lines, Get(language, "carpool.passengers", Params{"Passengers": peopleToString(language, current)}))
+	lines = append(lines, Get(language, "carpool.requesting", Params{"Passengers": peopleToString(language, possible)}))
+	str := ""
+	for _, line := range lines {
+		str += rightToLeftMark + line + "\n"
+	}
+	return str + "\n"
+}
+
+// ScheduleToString : describes a weekly schedule in the language of the student.
+func ScheduleToString(language string, schedule DB.Schedule) string {
+	if language != DB.LanguageArabic {
+		return Recurring.ScheduleToString(schedule)
+	}
+	days := []string{}
+	for _, day := range schedule.Days {
+		days = append(days, Weekday(language, day))
+	}
+	params := Params{"Days": strings.Join(days, "، "), "Time": Clock(language, schedule.Hour*60+schedule.Minute)}
+	every := Get(language, "schedule.toGUC", params)
+	if schedule.FromGUC {
+		every = Get(language, "schedule.fromGUC", params)
+	}
+	if schedule.Shift > 0 {
+		every += Get(language, "shift", Params{"Minutes": schedule.Shift})
+	}
+	lines := []string{
+		Get(language, "schedule.id", Params{"ScheduleID": strconv.FormatUint(schedule.ID, 10)}),
+		every,
+		Get(language, "schedule.dates", Params{"From": arabicDay(schedule.StartDate.In(Timezone.Location)), "Until": arabicDay(schedule.EndDate.In(Timezone.Location))}),
+		Get(language, "schedule.seats", Params{"Seats": schedule.Seats}),
+	}
+	if len(schedule.StandingPassengers) > 0 {
+		lines = append(lines, Get(language, "schedule.standing", Params{"Passengers": strings.Join(schedule.StandingPassengers, "، ")}))
+	}
+	if len(schedule.Skipped) > 0 {
+		lines = append(lines, Get(language, "schedule.skippedDays", Params{"Days": strings.Join(schedule.Skipped, "، ")}))
+	}
+	if schedule.Paused {
+		lines = append(lines, Get(language, "schedule.pausedLine", nil))
+	}
+	str := ""
+	for _, line := range lines {
+		str += rightToLeftMark + line + "\n"
+	}
+	return str + "\n"
+}
+
+// SuggestionToString : describes a ride the timetable of the student needs, in their language (eg. "to the GUC before your 8:30am lecture on Sunday").
+func SuggestionToString(language string, suggestion DB.RideSubscription) string {
+	if language != DB.LanguageArabic {
+		return Timetable.SuggestionToString(suggestion)
+	}
+	if suggestion.FromGUC {
+		return Get(language, "suggestion.fromGUC", Params{"Time": Clock(language, suggestion.Earliest), "Day": Weekday(language, suggestion.Day)})
+	}
+	return Get(language, "suggestion.toGUC", Params{"Time": Clock(language, suggestion.Latest+Timetable.ArriveBefore), "Day": Weekday(language, suggestion.Day)})
+}
+
+// ClassesToString : describes the timetable of the student in their language, one class per line.
+func ClassesToString(language string, classes []DB.Class) string {
+	if language != DB.LanguageArabic {
+		return Timetable.ClassesToString(classes)
+	}
+	str := ""
+	for _, class := range classes {
+		str += rightToLeftMark + Get(language, "timetable.class", Params{"Day": Weekday(language, class.Day), "Start": Clock(language, class.Start), "End": Clock(language, class.End), "Name": class.Name}) + "\n"
+	}
+	return str
+}
+
+// UserToString : describes the profile of the student in their language.
+func UserToString(language string, u *DB.User) string {
+	if language != DB.LanguageArabic {
+		return u.UserToString()
+	}
+	notSet := func(value string) string {
+		if strings.TrimSpace(value) == "" {
+			return Get(language, "profile.notSet", nil)
+		}
+		return value
+	}
+	onOff := func(optOut bool) string {
+		if optOut {
+			return Get(language, "profile.off", nil)
+		}
+		return Get(language, "profile.on", nil)
+	}
+	chosen := Get(language, "language.english", nil)
+	if u.Language == DB.LanguageArabic {
+		chosen = Get(language, "language.arabic", nil)
+	}
+	lines := []string{
+		Get(language, "profile.name", Params{"Name": u.Name, "GUCID": u.GUCID}),
+		Get(language, "profile.phoneLine", Params{"Phone": notSet(u.Phone)}),
+		Get(language, "profile.languageLine", Params{"Language": chosen}),
+		Get(language, "profile.homeAreaLine", Params{"Area": notSet(u.HomeArea)}),
+	}
+	if len(u.Vehicles) == 0 {
+		lines = append(lines, Get(language, "profile.noVehicles", nil))
+	}
+	for i, vehicle := range u.Vehicles {
+		lines = append(lines, Get(language, "profile.vehicleLine", Params{"Number": strconv.Itoa(i + 1), "Vehicle": vehicle.VehicleToString(), "Seats": vehicle.Capacity}))
+	}
+	lines = append(lines, Get(language, "profile.notifications", Params{"Emails": onOff(u.Notifications.EmailOptOut), "Reminders": onOff(u.Notifications.RemindersOptOut)}))
+	str := ""
+	for _, line := range lines {
+		str += rightToLeftMark + line + "\n"
+	}
+	return str + "\n"
+}
+
+// RatingToString : returns the average rating of a student to show next to them, or nothing if they have no ratings.
+func RatingToString(language string, average DB.AverageRating) string {
+	if average.Ratings == 0 {
+		return ""
+	}
+	return Get(language, "rating", Params{"Stars": strconv.FormatFloat(average.Stars, 'f', 1, 64), "Ratings": average.Ratings})
+}
+
+// personToString : writes the name, GUC ID and rating of the driver or a passenger, kept in its own direction.
+func personToString(language string, person DB.CarpoolPerson) string {
+	str := person.GUCID
+	if person.Name != "" {
+		str = person.Name + " (" + person.GUCID + ")"
+	}
+	if person.Ratings > 0 {
+		str += Get(language, "rating", Params{"Stars": strconv.FormatFloat(person.Stars, 'f', 1, 64), "Ratings": person.Ratings})
+	}
+	return isolateStart + str + isolateEnd
+}
+
+// peopleToString : writes a list of passengers, or that there are none.
+func peopleToString(language string, people []DB.CarpoolPerson) string {
+	if len(people) == 0 {
+		return Get(language, "none", nil)
+	}
+	strs := []string{}
+	for _, person := range people {
+		strs = append(strs, personToString(language, person))
+	}
+	return strings.Join(strs, "، ")
+}
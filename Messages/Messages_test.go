@@ -0,0 +1,144 @@
+package Messages
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/When"
+)
+
+// Every message is written in English and in Arabic, with the same parameters.
+func TestCatalog(t *testing.T) {
+	parameter := regexp.MustCompile(`\.[A-Z][A-Za-z]*`)
+	for key, translations := range catalog {
+		english, found := translations[DB.LanguageEnglish]
+		if !found {
+			t.Errorf("%s is not written in English", key)
+			continue
+		}
+		arabic, found := translations[DB.LanguageArabic]
+		if !found {
+			t.Errorf("%s is not written in Arabic", key)
+			continue
+		}
+		englishParams := parameter.FindAllString(english, -1)
+		arabicParams := parameter.FindAllString(arabic, -1)
+		sort.Strings(englishParams)
+		sort.Strings(arabicParams)
+		if strings.Join(englishParams, " ") != strings.Join(arabicParams, " ") {
+			t.Errorf("%s has the parameters %v in English and %v in Arabic", key, englishParams, arabicParams)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	if got := Get(DB.LanguageEnglish, "choose.oneWay", Params{"PostID": "12"}); got != "Carpool 12 is one way, so there is nothing to join both ways. Type 'choose 12' to join it." {
+		t.Error("wrong English message: " + got)
+	}
+	// A language that isn't translated yet falls back to English.
+	if Get("fr", "undo.cancelled", nil) != "Your request is back!" || Get("", "undo.cancelled", nil) != "Your request is back!" {
+		t.Error("expected to fall back to English")
+	}
+	if Get(DB.LanguageEnglish, "no.such.message", nil) != "no.such.message" {
+		t.Error("an unknown message should be written as its key")
+	}
+	// The values inside Arabic text keep their own direction.
+	got := Get(DB.LanguageArabic, "undo.inAgain", Params{"PostID": "12"})
+	if !strings.Contains(got, isolateStart+"12"+isolateEnd) || !strings.HasPrefix(got, "إنت") {
+		t.Error("wrong Arabic message: " + got)
+	}
+	if got := Get(DB.LanguageArabic, "rating", Params{"Stars": "4.5", "Ratings": 1}); !strings.Contains(got, "تقييم") || strings.Contains(got, "تقييمات") {
+		t.Error("wrong Arabic rating: " + got)
+	}
+	if got := Get(DB.LanguageEnglish, "rating", Params{"Stars": "4.5", "Ratings": 2}); got != " (4.5/5 from 2 ratings)" {
+		t.Error("wrong English rating: " + got)
+	}
+}
+
+func TestError(t *testing.T) {
+	err := Problem.New("problem.deleteStatus", map[string]interface{}{"Status": Problem.New("status.departed", nil, "departed")}, "you can not delete a carpool that is departed")
+	if got := Error(DB.LanguageEnglish, err); got != "you can not delete a carpool that is departed" {
+		t.Error("wrong English explanation: " + got)
+	}
+	// The status inside the problem is written in Arabic too.
+	if got := Error(DB.LanguageArabic, err); !strings.HasPrefix(got, "مينفعش تمسح") || !strings.Contains(got, "اتحرك") || strings.Contains(got, "departed") {
+		t.Error("wrong Arabic explanation: " + got)
+	}
+	// Other errors are written as they are.
+	if got := Error(DB.LanguageArabic, errors.New("connection refused")); got != "connection refused" {
+		t.Error("wrong explanation of another error: " + got)
+	}
+}
+
+func TestTime(t *testing.T) {
+	at := time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC)
+	if got := Time(DB.LanguageEnglish, at); got != "Nov 1, 2026 at 8:30am (EET)" {
+		t.Error("wrong English time: " + got)
+	}
+	if got := Time(DB.LanguageArabic, at); got != "1 نوفمبر 2026 الساعة 8:30 ص (EET)" {
+		t.Error("wrong Arabic time: " + got)
+	}
+	window := When.Expression{Start: at, End: at.Add(30 * time.Minute)}
+	if got := Expression(DB.LanguageArabic, window); got != "الأحد، 1 نوفمبر 2026 بين 8:30 ص و9:00 ص (EET)" {
+		t.Error("wrong Arabic window: " + got)
+	}
+	if got := Expression(DB.LanguageArabic, When.Expression{Start: at.Add(9 * time.Hour)}); got != "الأحد، 1 نوفمبر 2026 الساعة 5:30 م (EET)" {
+		t.Error("wrong Arabic time of day: " + got)
+	}
+	if got := Day(DB.LanguageArabic, at); got != "الأحد، 1 نوفمبر 2026" {
+		t.Error("wrong Arabic day: " + got)
+	}
+	if got := Clock(DB.LanguageArabic, 17*60+15); got != "5:15 م" {
+		t.Error("wrong Arabic clock: " + got)
+	}
+}
+
+// Every line of an Arabic schedule is written right to left.
+func TestScheduleLines(t *testing.T) {
+	schedule := DB.Schedule{ID: 3, Days: []time.Weekday{time.Sunday, time.Tuesday}, Hour: 8, Minute: 30, Seats: 3, StandingPassengers: []string{"34-1"}, StartDate: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), Paused: true}
+	str := ScheduleToString(DB.LanguageArabic, schedule)
+	lines := strings.Split(strings.TrimRight(str, "\n"), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 lines, got %d:\n%s", len(lines), str)
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, rightToLeftMark) {
+			t.Error("line not written right to left: " + line)
+		}
+	}
+	str = strings.NewReplacer(isolateStart, "", isolateEnd, "").Replace(str)
+	for _, expected := range []string{"جدول: 3", "رايح الجامعة كل الأحد، الثلاثاء الساعة 8:30 ص", "من 1 نوفمبر 2026 لحد 31 ديسمبر 2026", "34-1", "متوقف"} {
+		if !strings.Contains(str, expected) {
+			t.Errorf("expected %q in:\n%s", expected, str)
+		}
+	}
+}
+
+// Every line of an Arabic carpool is written right to left.
+func TestCarpoolLines(t *testing.T) {
+	c := &DB.CarpoolRequest{PostID: 12, FromGUC: true, StartTime: time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC), Shift: 15, AvailableSeats: 2, LinkedPostID: 13}
+	driver := DB.CarpoolPerson{GUCID: "34-1", Name: "Ahmed Ali", Stars: 4.5, Ratings: 2}
+	current := []DB.CarpoolPerson{{GUCID: "34-2", Name: "Sara Omar"}}
+	str := carpoolLines(DB.LanguageArabic, c, driver, current, nil, "Maadi, Cairo")
+	lines := strings.Split(strings.TrimRight(str, "\n"), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("expected 9 lines, got %d:\n%s", len(lines), str)
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, rightToLeftMark) {
+			t.Error("line not written right to left: " + line)
+		}
+	}
+	str = strings.NewReplacer(isolateStart, "", isolateEnd, "").Replace(str)
+	for _, expected := range []string{"Ahmed Ali (34-1) (4.5/5 من 2 تقييمات)", "خارج من الجامعة", "Maadi, Cairo", "8:30 ص", "15 دقيقة", "رايح جاي", "متاح", "Sara Omar (34-2)", "طالبين يركبوا: مفيش"} {
+		if !strings.Contains(str, expected) {
+			t.Errorf("expected %q in:\n%s", expected, str)
+		}
+	}
+}
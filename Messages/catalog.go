@@ -0,0 +1,1434 @@
+package Messages
+
+import "github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+
+// catalog : the messages of the bot by key, in every language they are written in. A message can be filled with parameters (eg. {{.PostID}}), which must be the same in every language.
+var catalog = map[string]map[string]string{
+	// Chat
+	"db.getError": {
+		DB.LanguageEnglish: "There was an error in getting your data from the database. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بجيب بياناتك من قاعدة البيانات. الخطأ: {{.Error}}",
+	},
+	"db.retrieveError": {
+		DB.LanguageEnglish: "There was an error while retrieving the data from our database. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بجيب البيانات من قاعدة البيانات. الخطأ: {{.Error}}",
+	},
+	"delete.kept": {
+		DB.LanguageEnglish: "Okay, I kept your carpool {{.PostID}}. What else would you like to do?",
+		DB.LanguageArabic:  "تمام، سبتلك مشوارك {{.PostID}}. عايز تعمل إيه تاني؟",
+	},
+	"notifications.error": {
+		DB.LanguageEnglish: "I could not retrieve your notifications at the moment, please try again later.",
+		DB.LanguageArabic:  "مش قادر أجيب الإشعارات بتاعتك دلوقتي، جرب تاني بعد شوية.",
+	},
+	"emails.error": {
+		DB.LanguageEnglish: "I could not save your email preference at the moment, please try again later.",
+		DB.LanguageArabic:  "مش قادر أحفظ اختيارك للإيميلات دلوقتي، جرب تاني بعد شوية.",
+	},
+	"emails.stopped": {
+		DB.LanguageEnglish: "You will no longer receive emails from me. You can type 'start emails' if you change your mind.",
+		DB.LanguageArabic:  "مش هبعتلك إيميلات تاني. لو غيرت رأيك اكتب 'start emails'.",
+	},
+	"emails.started": {
+		DB.LanguageEnglish: "I will email you at {{.Address}} whenever something happens to your carpools. You can type 'stop emails' to turn them off.",
+		DB.LanguageArabic:  "هبعتلك إيميل على {{.Address}} كل ما يحصل حاجة في مشاويرك. ممكن توقفها لما تكتب 'stop emails'.",
+	},
+	"help": {
+		DB.LanguageEnglish: " You can view all available carpools by typing 'view all', or 'view carpool' to view the ones you already have, cancel your request by typing 'cancel request', edit your request by typing 'edit request' or choose an available carpool by typing 'choose ID' where ID is the postID of the carpool of your choice, or 'choose both ID' to ride both ways of a round trip. You can also choose to offer other people a ride by creating a carpool by typing 'create', as many times as you drive, and change one by typing 'edit carpool ID' or 'delete carpool ID', repeat one every week by typing 'repeat carpool ID every sun, tue until year-month-day' and see those by typing 'view schedules', or specify the details of a carpool you wish to request by typing 'request'. You can also write it all in one message, like 'create to guc from Rehab tomorrow 8am 3 seats' or 'request from guc to Maadi sunday 3pm', and I'll only ask about what's missing. Type 'back' to go back to the step before while creating or requesting a carpool, and 'undo' to undo deleting a carpool, rejecting a passenger or cancelling your request, for 5 minutes after it. or view notifications for  your carpool or request by typing notify. When it's time to go, drivers can type 'start ride' and 'end ride', and passengers can type 'I'm in' once they're in the car. After the ride, you can rate each other with 'rate ID stars'. You can turn the emails I send you off by typing 'stop emails', see or edit your profile by typing 'profile', upload your class timetable by typing 'timetable' to get rides suggested, and add your rides to your own calendar app with the address you get by typing 'calendar link'. You can also have me answer you in Arabic by typing 'edit profile language', and write your commands in Franco-Arabic, like '3ayez arkab rayeh el gam3a men Maadi bokra 8' or 'hasou2 rage3 men el gam3a'.",
+		DB.LanguageArabic:  "تقدر تشوف كل المشاوير المتاحة لما تكتب 'view all'، أو 'view carpool' عشان تشوف مشاويرك، وتلغي طلبك بـ 'cancel request'، وتعدله بـ 'edit request'، أو تختار مشوار بـ 'choose ID' و ID هو رقم المشوار، أو 'choose both ID' عشان تروح وترجع في نفس الرحلة. تقدر كمان تعرض توصيلة على الناس لما تكتب 'create' (أو 'hasou2')، وتعدل مشوار بـ 'edit carpool ID' أو تمسحه بـ 'delete carpool ID'، وتكرره كل أسبوع بـ 'repeat carpool ID every sun, tue until year-month-day' وتشوف المتكرر بـ 'view schedules'، أو تطلب توصيلة لما تكتب 'request' (أو '3ayez arkab'). وممكن تكتب كل حاجة في رسالة واحدة، زي '3ayez arkab rayeh el gam3a men Maadi bokra 8 el sob7'، وأنا هسألك عن اللي ناقص بس. اكتب 'back' عشان ترجع خطوة وإنت بتعمل أو بتطلب مشوار، و'undo' عشان تلغي مسح مشوار أو رفض راكب أو إلغاء طلبك، خلال 5 دقايق. وتقدر تشوف الإشعارات بـ 'notify'. لما ييجي الميعاد، السواق يكتب 'start ride' و'end ride'، والراكب يكتب 'I'm in' لما يركب. وبعد المشوار تقدروا تقيموا بعض بـ 'rate ID stars'. تقدر توقف الإيميلات بـ 'stop emails'، وتشوف أو تعدل بياناتك بـ 'profile' (ومنها تغير اللغة)، وترفع جدول محاضراتك بـ 'timetable' عشان أقترح عليك مشاوير، وتضيف مشاويرك لتقويمك بالعنوان اللي هيجيلك لما تكتب 'calendar link'. ولو عايزني أرد بالإنجليزي، اكتب 'edit profile language'.",
+	},
+	"create.start": {
+		DB.LanguageEnglish: "You've chosen to create a carpool. Are you going to the GUC, or are you leaving the GUC?",
+		DB.LanguageArabic:  "اخترت تعمل مشوار. إنت رايح الجامعة ولا خارج من الجامعة؟",
+	},
+	"request.start": {
+		DB.LanguageEnglish: "You've chosen to request a carpool. Are you going to the GUC, or are you leaving the GUC?",
+		DB.LanguageArabic:  "اخترت تطلب توصيلة. إنت رايح الجامعة ولا خارج من الجامعة؟",
+	},
+	"start.unanswered": {
+		DB.LanguageEnglish: "I'm sorry, but you didn't answer my question! Are you offering a ride? Or are you requesting One? I am not busy I can do this all day. type 'create' to make a carpool or 'request' to make a request",
+		DB.LanguageArabic:  "معلش، بس إنت مجاوبتش على سؤالي! إنت هتوصل حد ولا عايز توصيلة؟ أنا فاضي وممكن أفضل أسأل طول اليوم. اكتب 'create' (أو 'hasou2') عشان تعمل مشوار أو 'request' (أو '3ayez arkab') عشان تطلب توصيلة",
+	},
+	"message.unclear": {
+		DB.LanguageEnglish: "I don't seem to understand what your message '{{.Message}}' means. Can you please clarify?",
+		DB.LanguageArabic:  "مش فاهم رسالتك '{{.Message}}' قصدك بيها إيه. ممكن توضح؟",
+	},
+	"create.toGUC": {
+		DB.LanguageEnglish: "You've chosen to create a carpool that's going to the GUC. Where can you pick up people?  Please tell me your desired location",
+		DB.LanguageArabic:  "اخترت تعمل مشوار رايح الجامعة. تقدر تاخد الناس منين؟ قولي المكان اللي يناسبك",
+	},
+	"create.fromGUC": {
+		DB.LanguageEnglish: "You've chosen to create a carpool that's leaving the GUC. Where are you going? Please tell me your desired location",
+		DB.LanguageArabic:  "اخترت تعمل مشوار خارج من الجامعة. إنت رايح فين؟ قولي المكان اللي يناسبك",
+	},
+	"direction.unanswered": {
+		DB.LanguageEnglish: "I'm sorry you didn't answer my question. Are you going to the GUC or leaving the GUC? (ex. if you're leaving you can type 'from guc' or if you're going to campus you can type 'to guc'.",
+		DB.LanguageArabic:  "معلش إنت مجاوبتش على سؤالي. إنت رايح الجامعة ولا خارج منها؟ (مثلاً لو خارج اكتب 'from guc' أو 'rage3 men el gam3a'، ولو رايح اكتب 'to guc' أو 'rayeh el gam3a'.)",
+	},
+	"location.coordinates": {
+		DB.LanguageEnglish: "You chose the location with the latitude {{.Latitude}}, and the longitude {{.Longitude}}. What time would you like to your ride to be?",
+		DB.LanguageArabic:  "اخترت المكان اللي خط عرضه {{.Latitude}} وخط طوله {{.Longitude}}. عايز المشوار يكون إمتى؟",
+	},
+	"location.address": {
+		DB.LanguageEnglish: "You chose the location with the address {{.Address}} . What time would you like to your ride to be?",
+		DB.LanguageArabic:  "اخترت المكان اللي عنوانه {{.Address}}. عايز المشوار يكون إمتى؟",
+	},
+	"create.whereGoing": {
+		DB.LanguageEnglish: "Where are you going?",
+		DB.LanguageArabic:  "إنت رايح فين؟",
+	},
+	"create.wherePickUp": {
+		DB.LanguageEnglish: "Where can you pick up people?",
+		DB.LanguageArabic:  "تقدر تاخد الناس منين؟",
+	},
+	"location.unanswered": {
+		DB.LanguageEnglish: "I'm sorry, but you didn't answer my question! {{.Question}} Please tell me your desired location",
+		DB.LanguageArabic:  "معلش، بس إنت مجاوبتش على سؤالي! {{.Question}} قولي المكان اللي يناسبك",
+	},
+	"time.invalid": {
+		DB.LanguageEnglish: "This is not a valid time: {{.Error}}. Can you please tell me again when you want your ride to be?",
+		DB.LanguageArabic:  "ده مش ميعاد صحيح: {{.Error}}. ممكن تقولي تاني عايز المشوار يكون إمتى؟",
+	},
+	"create.time": {
+		DB.LanguageEnglish: "You want your ride to take place around {{.Time}}. {{.Question}}",
+		DB.LanguageArabic:  "عايز المشوار يكون حوالي {{.Time}}. {{.Question}}",
+	},
+	"seats.full": {
+		DB.LanguageEnglish: "Your car is already full with the passengers you accepted. Please type 'edit carpool' and choose a bigger car",
+		DB.LanguageArabic:  "عربيتك مليانة بالركاب اللي قبلتهم. اكتب 'edit carpool' واختار عربية أكبر",
+	},
+	"seats.invalid": {
+		DB.LanguageEnglish: "your car can take 1 to {{.Seats}} more passengers, not including yourself. Please enter a valid number!",
+		DB.LanguageArabic:  "عربيتك تاخد من 1 لـ {{.Seats}} ركاب كمان، من غيرك. اكتب رقم صحيح!",
+	},
+	"create.unclear": {
+		DB.LanguageEnglish: "I did not understand what you said. I am only a computer after all.",
+		DB.LanguageArabic:  "مفهمتش إنت قلت إيه. أنا في الآخر مجرد كمبيوتر.",
+	},
+	"create.error": {
+		DB.LanguageEnglish: "An error occured when creating your carpool. Please try again later",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بعمل مشوارك. جرب تاني بعد شوية",
+	},
+	"create.insertError": {
+		DB.LanguageEnglish: "An error occured while inserting into the database. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بحفظ في قاعدة البيانات. الخطأ: {{.Error}}",
+	},
+	"create.complete": {
+		DB.LanguageEnglish: "You've chosen to take up to {{.Seats}} more passengers. Your carpool {{.PostID}} is now complete! You can see it by typing 'view carpool {{.PostID}}'.",
+		DB.LanguageArabic:  "اخترت تاخد لحد {{.Seats}} ركاب كمان. مشوارك رقم {{.PostID}} خلص! تقدر تشوفه لما تكتب 'view carpool {{.PostID}}'.",
+	},
+	"create.updateError": {
+		DB.LanguageEnglish: "An error occured when creating your carpool. Please try again later Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بعمل مشوارك. جرب تاني بعد شوية. الخطأ: {{.Error}}",
+	},
+	"carpool.gone": {
+		DB.LanguageEnglish: "This carpool does not exist anymore. You can create a new one by typing 'create'",
+		DB.LanguageArabic:  "المشوار ده مبقاش موجود. تقدر تعمل واحد جديد لما تكتب 'create'",
+	},
+	"request.toGUC": {
+		DB.LanguageEnglish: "You've chosen to find a carpool going to the GUC! Where would you like to be picked up from? Please tell me your desired location.",
+		DB.LanguageArabic:  "اخترت تدور على مشوار رايح الجامعة! عايز حد ياخدك منين؟ قولي المكان اللي يناسبك.",
+	},
+	"request.fromGUC": {
+		DB.LanguageEnglish: "You chose to leave the campus. Where would you like to go? Please tell me your desired location",
+		DB.LanguageArabic:  "اخترت تخرج من الجامعة. عايز تروح فين؟ قولي المكان اللي يناسبك",
+	},
+	"request.whereGo": {
+		DB.LanguageEnglish: "Where would you like to go?",
+		DB.LanguageArabic:  "عايز تروح فين؟",
+	},
+	"request.wherePickUp": {
+		DB.LanguageEnglish: "Where would you like to be picked up from?",
+		DB.LanguageArabic:  "عايز حد ياخدك منين؟",
+	},
+	"request.unclear": {
+		DB.LanguageEnglish: "Looks like you have a carpool already. You can edit it if you want.",
+		DB.LanguageArabic:  "شكلك عندك مشوار خلاص. تقدر تعدله لو حابب.",
+	},
+	"request.complete": {
+		DB.LanguageEnglish: "Your request is complete! Here are the details: {{.Details}} You can now view all carpools, cancel your request, edit your request or choose one of the available carpools. So, what do you want to do?",
+		DB.LanguageArabic:  "طلبك خلص! دي التفاصيل: {{.Details}} دلوقتي تقدر تشوف كل المشاوير، أو تلغي طلبك، أو تعدله، أو تختار مشوار من المتاحين. عايز تعمل إيه؟",
+	},
+	"viewAll.none": {
+		DB.LanguageEnglish: "Oops no current carpool offers available looks like you'll be walking. :P",
+		DB.LanguageArabic:  "مفيش مشاوير متاحة دلوقتي، شكلك هتمشي. :P",
+	},
+	"viewAll.all": {
+		DB.LanguageEnglish: "Here are all the available carpools!",
+		DB.LanguageArabic:  "دي كل المشاوير المتاحة!",
+	},
+	"viewAll.noneFit": {
+		DB.LanguageEnglish: "None of the carpools fit your request yet, but here are all the available ones!",
+		DB.LanguageArabic:  "مفيش مشوار مناسب لطلبك لسه، بس دي كل المشاوير المتاحة!",
+	},
+	"viewAll.fitting": {
+		DB.LanguageEnglish: "Here are the carpools that fit your request!",
+		DB.LanguageArabic:  "دي المشاوير المناسبة لطلبك!",
+	},
+	"viewAll.others": {
+		DB.LanguageEnglish: "And the other available carpools:",
+		DB.LanguageArabic:  "وباقي المشاوير المتاحة:",
+	},
+	"request.editNone": {
+		DB.LanguageEnglish: "You can't edit a request if you don't have one. I am starting to doubt your inteligence.",
+		DB.LanguageArabic:  "مينفعش تعدل طلب إنت معندكش. بدأت أشك في ذكائك.",
+	},
+	"request.edit": {
+		DB.LanguageEnglish: "You chose to edit your carpool request. Let's do this piece by piece. Firstly, are you going to the GUC, or are you leaving campus?",
+		DB.LanguageArabic:  "اخترت تعدل طلبك. يلا نمشيها خطوة خطوة. الأول، إنت رايح الجامعة ولا خارج منها؟",
+	},
+	"cancel.gone": {
+		DB.LanguageEnglish: "No posts exist with this ID. It must have been deleted!",
+		DB.LanguageArabic:  "مفيش مشوار بالرقم ده. أكيد اتمسح!",
+	},
+	"cancel.leaveError": {
+		DB.LanguageEnglish: "There was an error removing you from the carpool. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بشيلك من المشوار. الخطأ: {{.Error}}",
+	},
+	"cancel.notFound": {
+		DB.LanguageEnglish: "I can't find your carpool request. Please try again in a moment",
+		DB.LanguageArabic:  "مش لاقي طلبك. جرب تاني بعد شوية",
+	},
+	"cancel.updateError": {
+		DB.LanguageEnglish: "I can't update your information in our database. Error: {{.Error}}",
+		DB.LanguageArabic:  "مش قادر أحدث بياناتك في قاعدة البيانات. الخطأ: {{.Error}}",
+	},
+	"cancel.leftOneWay": {
+		DB.LanguageEnglish: "You left carpool {{.PostID}}. You're still in carpool {{.LinkedPostID}}, the other way of the round trip. Type 'cancel request' again if you want to leave it too.",
+		DB.LanguageArabic:  "خرجت من المشوار {{.PostID}}. إنت لسه في المشوار {{.LinkedPostID}}، الاتجاه التاني من الرحلة. اكتب 'cancel request' تاني لو عايز تخرج منه كمان.",
+	},
+	"cancel.done": {
+		DB.LanguageEnglish: "Your carpool request has been cancelled successfully.",
+		DB.LanguageArabic:  "طلبك اتلغى.",
+	},
+	"cancel.startOver": {
+		DB.LanguageEnglish: "Or you can start over. Do you want to request a carpool, or are you offering one?",
+		DB.LanguageArabic:  "أو تقدر تبدأ من الأول. عايز تطلب توصيلة ولا تعرض واحدة؟",
+	},
+	"choose.already": {
+		DB.LanguageEnglish: "You already chose a carpool. Please cancel before choosing a new one. You can only be in one carpool at a time.",
+		DB.LanguageArabic:  "إنت اخترت مشوار قبل كده. ألغيه الأول قبل ما تختار واحد جديد. مينفعش تكون في أكتر من مشوار في نفس الوقت.",
+	},
+	"choose.badID": {
+		DB.LanguageEnglish: "There was an error when converting the postID from string to int. Please try again.",
+		DB.LanguageArabic:  "مقدرتش أقرا رقم المشوار. جرب تاني.",
+	},
+	"choose.invalid": {
+		DB.LanguageEnglish: "not a valid post ID. I will say it was a typo! Try again like this 'choose ID' but replace ID with the post number!",
+		DB.LanguageArabic:  "ده مش رقم مشوار صحيح. هعتبرها غلطة كتابة! جرب تاني كده 'choose ID' وحط رقم المشوار مكان ID!",
+	},
+	"choose.oneWay": {
+		DB.LanguageEnglish: "Carpool {{.PostID}} is one way, so there is nothing to join both ways. Type 'choose {{.PostID}}' to join it.",
+		DB.LanguageArabic:  "المشوار {{.PostID}} اتجاه واحد، فمفيش رايح جاي تنضم له. اكتب 'choose {{.PostID}}' عشان تنضم له.",
+	},
+	"choose.both": {
+		DB.LanguageEnglish: "You've successfully chosen both ways of the round trip, carpools {{.PostID}} and {{.LinkedPostID}}! Now you have to wait for the original poster to accept your requests. You can view your notifications by typing 'notify' or 'notifications'.",
+		DB.LanguageArabic:  "اخترت الرحلة رايح جاي، المشوارين {{.PostID}} و{{.LinkedPostID}}! دلوقتي استنى السواق يقبل طلباتك. تقدر تشوف الإشعارات لما تكتب 'notify' أو 'notifications'.",
+	},
+	"choose.done": {
+		DB.LanguageEnglish: "You've successfully chosen a carpool! Now you have to wait for the original poster to accept your request. He may cancel the carpool, or choose to accept others, so plesae stay alert to your notifications. You can view them by typing 'notify' or 'notifications'.",
+		DB.LanguageArabic:  "اخترت مشوار! دلوقتي استنى السواق يقبل طلبك. ممكن يلغي المشوار أو يقبل ناس تانية، فخليك متابع الإشعارات. تقدر تشوفها لما تكتب 'notify' أو 'notifications'.",
+	},
+	"delete.confirm": {
+		DB.LanguageEnglish: "You accepted {{.Count}} passengers in your carpool {{.PostID}} ({{.Passengers}}), and I'll email them that it's cancelled. Are you sure you want to delete it? Type 'yes' to delete it or 'no' to keep it.",
+		DB.LanguageArabic:  "إنت قبلت {{.Count}} ركاب في مشوارك {{.PostID}} ({{.Passengers}})، وهبعتلهم إيميل إنه اتلغى. متأكد إنك عايز تمسحه؟ اكتب 'aywa' عشان تمسحه أو 'la' عشان تسيبه.",
+	},
+	"edit.start": {
+		DB.LanguageEnglish: "You chose to edit your carpool {{.PostID}}. Let's do this piece by piece. Firstly, are you going to the GUC, or are you leaving campus?",
+		DB.LanguageArabic:  "اخترت تعدل مشوارك {{.PostID}}. يلا نمشيها خطوة خطوة. الأول، إنت رايح الجامعة ولا خارج منها؟",
+	},
+	"view.error": {
+		DB.LanguageEnglish: "Could not get the carpool request. Error: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أجيب المشوار. الخطأ: {{.Error}}",
+	},
+	"view.none": {
+		DB.LanguageEnglish: "You can't view your carpool because you didn't make one yet. Go make one if you really want to do that. Just type something like 'create'.",
+		DB.LanguageArabic:  "مينفعش تشوف مشوارك عشان إنت لسه معملتش واحد. اعمل واحد لو عايز فعلاً، اكتب حاجة زي 'create' أو 'hasou2'.",
+	},
+	"view.details": {
+		DB.LanguageEnglish: "Here are your carpool details!",
+		DB.LanguageArabic:  "دي تفاصيل مشوارك!",
+	},
+	"accept.who": {
+		DB.LanguageEnglish: "Who do you want to accept or reject? Type 'accept' or 'reject' and the GUC ID of the passenger (ex. 'accept 34-1234').",
+		DB.LanguageArabic:  "عايز تقبل أو ترفض مين؟ اكتب 'accept' أو 'reject' ورقم الراكب في الجامعة (مثلاً 'accept 34-1234').",
+	},
+	"reject.error": {
+		DB.LanguageEnglish: "There was an error in rejecting this passenger. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا برفض الراكب ده. الخطأ: {{.Error}}",
+	},
+	"accept.error": {
+		DB.LanguageEnglish: "There was an error in accepting this passenger. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بقبل الراكب ده. الخطأ: {{.Error}}",
+	},
+	"reject.done": {
+		DB.LanguageEnglish: "You successfully rejected the passenger with ID {{.Passenger}} from your carpool {{.PostIDs}}.",
+		DB.LanguageArabic:  "رفضت الراكب {{.Passenger}} من مشوارك {{.PostIDs}}.",
+	},
+	"whatElse": {
+		DB.LanguageEnglish: "What else would you like to do?",
+		DB.LanguageArabic:  "عايز تعمل إيه تاني؟",
+	},
+	"accept.done": {
+		DB.LanguageEnglish: "You successfully accepted the passenger with ID {{.Passenger}} in your carpool {{.PostIDs}}. What else would you like to do?",
+		DB.LanguageArabic:  "قبلت الراكب {{.Passenger}} في مشوارك {{.PostIDs}}. عايز تعمل إيه تاني؟",
+	},
+	"and": {
+		DB.LanguageEnglish: " and ",
+		DB.LanguageArabic:  " و",
+	},
+	"directions.error": {
+		DB.LanguageEnglish: "An error occured while recieving the directions. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بجيب الطريق. الخطأ: {{.Error}}",
+	},
+	"directions.notFound": {
+		DB.LanguageEnglish: "An error occured while recieving the directions. the location you entered can not be found",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بجيب الطريق. مش لاقي المكان اللي كتبته",
+	},
+	"postRequest.unclear": {
+		DB.LanguageEnglish: "I did not understand what you said. Would you like to view all the available carpools,  cancel your request, edit your request or choose an available carpool?",
+		DB.LanguageArabic:  "مفهمتش إنت قلت إيه. عايز تشوف كل المشاوير المتاحة، ولا تلغي طلبك، ولا تعدله، ولا تختار مشوار متاح؟",
+	},
+	"rate.usage": {
+		DB.LanguageEnglish: "To rate someone you rode with, type 'rate' followed by their GUC ID, the stars from 1 to 5, and a comment if you want. (ex. 'rate 34-1234 5 great ride')",
+		DB.LanguageArabic:  "عشان تقيم حد ركبت معاه، اكتب 'rate' وبعدها رقمه في الجامعة، والنجوم من 1 لـ 5، وتعليق لو حابب. (مثلاً 'rate 34-1234 5 great ride')",
+	},
+	"rate.error": {
+		DB.LanguageEnglish: "I couldn't save your rating. Error: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أحفظ تقييمك. الخطأ: {{.Error}}",
+	},
+	"rate.done": {
+		DB.LanguageEnglish: "Thank you! You gave {{.GUCID}} {{.Stars}} stars.",
+		DB.LanguageArabic:  "شكراً! إديت {{.GUCID}} {{.Stars}} نجوم.",
+	},
+	"ride.none": {
+		DB.LanguageEnglish: "You don't have a carpool to drive. You can create one by typing 'create'.",
+		DB.LanguageArabic:  "معندكش مشوار تسوقه. تقدر تعمل واحد لما تكتب 'create'.",
+	},
+	"ride.startError": {
+		DB.LanguageEnglish: "I couldn't start your ride. Error: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أبدأ مشوارك. الخطأ: {{.Error}}",
+	},
+	"ride.started": {
+		DB.LanguageEnglish: "Your ride has started! Drive safely, and type 'end ride' when you arrive.",
+		DB.LanguageArabic:  "مشوارك بدأ! سوق بالراحة، واكتب 'end ride' لما توصل.",
+	},
+	"ride.endError": {
+		DB.LanguageEnglish: "I couldn't end your ride. Error: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أنهي مشوارك. الخطأ: {{.Error}}",
+	},
+	"ride.ended": {
+		DB.LanguageEnglish: "Your ride is complete. Thank you for driving! You can now create a new carpool. Don't forget to rate your passengers, type 'notify' to see who you can rate.",
+		DB.LanguageArabic:  "مشوارك خلص. شكراً إنك وصلت الناس! تقدر دلوقتي تعمل مشوار جديد. متنساش تقيم ركابك، اكتب 'notify' عشان تشوف مين تقدر تقيمه.",
+	},
+	"checkIn.none": {
+		DB.LanguageEnglish: "You didn't choose a carpool, so there is nothing to check in to.",
+		DB.LanguageArabic:  "إنت مخترتش مشوار، فمفيش حاجة تسجل فيها ركوبك.",
+	},
+	"checkIn.error": {
+		DB.LanguageEnglish: "I couldn't check you in. Error: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أسجل ركوبك. الخطأ: {{.Error}}",
+	},
+	"checkIn.done": {
+		DB.LanguageEnglish: "You're checked in. Have a nice ride!",
+		DB.LanguageArabic:  "سجلت ركوبك. مشوار سعيد!",
+	},
+	"notify.rejected": {
+		DB.LanguageEnglish: "I'm sorry, but your last carpool request couldn't be made. You can join another one.",
+		DB.LanguageArabic:  "معلش، آخر طلب ليك متقبلش. تقدر تنضم لمشوار تاني.",
+	},
+	"notify.accepted": {
+		DB.LanguageEnglish: "Your request has been accepted! have fun",
+		DB.LanguageArabic:  "طلبك اتقبل! استمتع",
+	},
+	"notify.lookFor": {
+		DB.LanguageEnglish: ". Look for a {{.Vehicle}}",
+		DB.LanguageArabic:  ". دور على {{.Vehicle}}",
+	},
+	"notify.cancelled": {
+		DB.LanguageEnglish: "The passenger with ID {{.Passenger}} and name {{.Name}} has cancelled his request in your carpool {{.PostID}}. You can accept another one in their place.",
+		DB.LanguageArabic:  "الراكب {{.Passenger}} واسمه {{.Name}} لغى طلبه في مشوارك {{.PostID}}. تقدر تقبل حد تاني مكانه.",
+	},
+	"notify.wantsToRide": {
+		DB.LanguageEnglish: "The passenger with ID {{.Passenger}}{{if .Name}} and name {{.Name}}{{end}}{{.Rating}} wants to ride with you in your carpool {{.PostID}}",
+		DB.LanguageArabic:  "الراكب {{.Passenger}}{{if .Name}} واسمه {{.Name}}{{end}}{{.Rating}} عايز يركب معاك في مشوارك {{.PostID}}",
+	},
+	"notify.rate": {
+		DB.LanguageEnglish: "How was your ride with {{.Name}}? Rate them by typing 'rate {{.GUCID}} 1-5' and an optional comment",
+		DB.LanguageArabic:  "المشوار مع {{.Name}} كان عامل إزاي؟ قيمه لما تكتب 'rate {{.GUCID}} 1-5' وتعليق لو حابب",
+	},
+	"notify.none": {
+		DB.LanguageEnglish: "There are no new notifications",
+		DB.LanguageArabic:  "مفيش إشعارات جديدة",
+	},
+
+	// Carpools
+	"choose.notYours": {
+		DB.LanguageEnglish: "You don't have a carpool with the ID {{.PostID}}. You can see yours by typing 'view carpool'.",
+		DB.LanguageArabic:  "معندكش مشوار رقمه {{.PostID}}. تقدر تشوف مشاويرك لما تكتب 'view carpool'.",
+	},
+	"choose.noCarpool": {
+		DB.LanguageEnglish: "You don't have a carpool. You can create one by typing 'create'.",
+		DB.LanguageArabic:  "معندكش مشوار. تقدر تعمل واحد لما تكتب 'create'.",
+	},
+	"choose.which": {
+		DB.LanguageEnglish: "You have {{.Count}} carpools, which one do you mean? Add its ID to what you typed (ex. 'edit carpool {{.PostID}}').",
+		DB.LanguageArabic:  "عندك {{.Count}} مشاوير، تقصد أنهي واحد؟ زود رقمه على اللي كتبته (مثلاً 'edit carpool {{.PostID}}').",
+	},
+	"accept.notAsked": {
+		DB.LanguageEnglish: "The passenger with ID {{.Passenger}} didn't ask to join any of your carpools.",
+		DB.LanguageArabic:  "الراكب {{.Passenger}} مطلبش ينضم لأي مشوار من مشاويرك.",
+	},
+	"time.confirm": {
+		DB.LanguageEnglish: "Did you mean {{.Time}}? Type 'yes' if that's right, or tell me the time again (ex. 'sunday at 7:45pm').",
+		DB.LanguageArabic:  "قصدك {{.Time}}؟ اكتب 'aywa' لو كده صح، أو قولي الميعاد تاني (مثلاً 'el 7ad 7:45 belel').",
+	},
+	"shift.tooLong": {
+		DB.LanguageEnglish: "You can leave at most {{.Minutes}} minutes before or after the time of your carpool, so your passengers know when to be ready. Please tell me a shorter window (ex. '8am give or take 15 minutes')",
+		DB.LanguageArabic:  "تقدر تتحرك بدري أو متأخر {{.Minutes}} دقيقة بالكتير عن ميعاد مشوارك، عشان ركابك يعرفوا يجهزوا إمتى. قولي وقت أقصر (مثلاً '8am give or take 15 minutes')",
+	},
+	"create.pastTime": {
+		DB.LanguageEnglish: "This time doesn't make sense! You need to choose a time in the future. I am not that dumb you know",
+		DB.LanguageArabic:  "الميعاد ده مينفعش! لازم تختار ميعاد لسه مجاش. أنا مش غبي للدرجة دي على فكرة",
+	},
+	"request.pastTime": {
+		DB.LanguageEnglish: "This time doesn't make sense! You need to choose a time in the future! I do not have a time machine",
+		DB.LanguageArabic:  "الميعاد ده مينفعش! لازم تختار ميعاد لسه مجاش! أنا معنديش آلة زمن",
+	},
+	"shift": {
+		DB.LanguageEnglish: ", give or take {{.Minutes}} minutes",
+		DB.LanguageArabic:  "، بزيادة أو نقص {{.Minutes}} دقيقة",
+	},
+	"conflict.error": {
+		DB.LanguageEnglish: "I couldn't check your other carpools right now. Please try again later",
+		DB.LanguageArabic:  "مقدرتش أراجع مشاويرك التانية دلوقتي. جرب تاني بعد شوية",
+	},
+	"conflict.driving": {
+		DB.LanguageEnglish: "You already have a carpool (ID {{.PostID}}) around that time, on {{.Time}}! You can't be in two places at once. Please choose a different time",
+		DB.LanguageArabic:  "عندك مشوار (رقم {{.PostID}}) في الوقت ده، يوم {{.Time}}! مينفعش تكون في مكانين في نفس الوقت. اختار ميعاد تاني",
+	},
+	"conflict.riding": {
+		DB.LanguageEnglish: "You're riding in carpool {{.PostID}} around that time! You can't be in two places at once. Please choose a different time",
+		DB.LanguageArabic:  "إنت راكب في المشوار {{.PostID}} في الوقت ده! مينفعش تكون في مكانين في نفس الوقت. اختار ميعاد تاني",
+	},
+	"delete.error": {
+		DB.LanguageEnglish: "There was an error deleting the carpool from our database. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بمسح المشوار من قاعدة البيانات. الخطأ: {{.Error}}",
+	},
+	"delete.oneWay": {
+		DB.LanguageEnglish: "You chose to delete your carpool {{.PostID}}. It was one way of a round trip, carpool {{.LinkedPostID}} is still on. Do you want to delete carpool {{.LinkedPostID}} too? Type 'yes' to delete it or 'no' to keep it.",
+		DB.LanguageArabic:  "اخترت تمسح مشوارك {{.PostID}}. كان اتجاه واحد من رحلة رايح جاي، والمشوار {{.LinkedPostID}} لسه موجود. عايز تمسح المشوار {{.LinkedPostID}} كمان؟ اكتب 'aywa' عشان تمسحه أو 'la' عشان تسيبه.",
+	},
+	"delete.done": {
+		DB.LanguageEnglish: "You chose to delete your carpool {{.PostID}}. You can create a new one if you wish. You can also request one or view all the available ones.",
+		DB.LanguageArabic:  "اخترت تمسح مشوارك {{.PostID}}. تقدر تعمل واحد جديد لو حابب، أو تطلب توصيلة، أو تشوف كل المشاوير المتاحة.",
+	},
+	"details.location": {
+		DB.LanguageEnglish: "the location with latitude {{.Latitude}} and longitude {{.Longitude}}",
+		DB.LanguageArabic:  "المكان اللي خط عرضه {{.Latitude}} وخط طوله {{.Longitude}}",
+	},
+	"details.fromGUC": {
+		DB.LanguageEnglish: "You're leaving the GUC, and going to {{.Place}}.",
+		DB.LanguageArabic:  "إنت خارج من الجامعة ورايح {{.Place}}.",
+	},
+	"details.toGUC": {
+		DB.LanguageEnglish: "You're coming to the GUC, from {{.Place}}.",
+		DB.LanguageArabic:  "إنت رايح الجامعة من {{.Place}}.",
+	},
+	"details.window": {
+		DB.LanguageEnglish: "You want your ride to take place on {{.Time}}.",
+		DB.LanguageArabic:  "عايز المشوار يكون {{.Time}}.",
+	},
+	"details.time": {
+		DB.LanguageEnglish: "You want your ride to take place around {{.Time}}.",
+		DB.LanguageArabic:  "عايز المشوار يكون حوالي {{.Time}}.",
+	},
+
+	// Round trips
+	"return.comingBack": {
+		DB.LanguageEnglish: "Are you coming back to the GUC later? Tell me when, and I'll make the return trip with the same pickup point, car and seats. Or type 'no' if it's one way.",
+		DB.LanguageArabic:  "هترجع الجامعة بعدين؟ قولي إمتى، وأنا هعمل مشوار الرجوع بنفس المكان والعربية والكراسي. أو اكتب 'la' لو رايح بس.",
+	},
+	"return.goingBack": {
+		DB.LanguageEnglish: "Are you going back from the GUC later? Tell me when, and I'll make the return trip with the same drop off point, car and seats. Or type 'no' if it's one way.",
+		DB.LanguageArabic:  "هتروح من الجامعة بعدين؟ قولي إمتى، وأنا هعمل مشوار الرجوع بنفس المكان والعربية والكراسي. أو اكتب 'la' لو رايح بس.",
+	},
+	"return.oneWay": {
+		DB.LanguageEnglish: "Okay, it's a one way trip. What else would you like to do?",
+		DB.LanguageArabic:  "تمام، مشوار اتجاه واحد. عايز تعمل إيه تاني؟",
+	},
+	"return.invalidTime": {
+		DB.LanguageEnglish: "This is not a valid time: {{.Error}}. {{.Question}}",
+		DB.LanguageArabic:  "ده مش ميعاد صحيح: {{.Error}}. {{.Question}}",
+	},
+	"return.beforeFirst": {
+		DB.LanguageEnglish: "The return trip has to be after the first one, which is on {{.Time}}. {{.Question}}",
+		DB.LanguageArabic:  "مشوار الرجوع لازم يكون بعد الأول، اللي هو يوم {{.Time}}. {{.Question}}",
+	},
+	"return.error": {
+		DB.LanguageEnglish: "An error occured when creating your return trip. Please try again later",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بعمل مشوار الرجوع. جرب تاني بعد شوية",
+	},
+	"return.linkError": {
+		DB.LanguageEnglish: "An error occured while linking your carpools. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بربط مشاويرك. الخطأ: {{.Error}}",
+	},
+	"return.done": {
+		DB.LanguageEnglish: "Your return trip is carpool {{.PostID}}, on {{.Time}}. Passengers can join both ways at once by typing 'choose both {{.PostID}}'.",
+		DB.LanguageArabic:  "مشوار الرجوع رقمه {{.PostID}}، يوم {{.Time}}. الركاب يقدروا ينضموا للاتجاهين مرة واحدة لما يكتبوا 'choose both {{.PostID}}'.",
+	},
+	"join.own": {
+		DB.LanguageEnglish: "You can't join your own carpool! I mean ... why would you even do that?",
+		DB.LanguageArabic:  "مينفعش تنضم لمشوارك إنت! يعني ... ليه أصلاً تعمل كده؟",
+	},
+	"join.createError": {
+		DB.LanguageEnglish: "There was an error while creating your information. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بجهز بياناتك. الخطأ: {{.Error}}",
+	},
+	"join.saveError": {
+		DB.LanguageEnglish: "There was an error while saving your information. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة وأنا بحفظ بياناتك. الخطأ: {{.Error}}",
+	},
+	"join.updateError": {
+		DB.LanguageEnglish: "There was an error updating. Error: {{.Error}}",
+		DB.LanguageArabic:  "حصلت مشكلة في التحديث. الخطأ: {{.Error}}",
+	},
+
+	// One-message commands
+	"command.notCreated": {
+		DB.LanguageEnglish: "Okay, I didn't create it. What else would you like to do?",
+		DB.LanguageArabic:  "تمام، معملتوش. عايز تعمل إيه تاني؟",
+	},
+	"command.notSaved": {
+		DB.LanguageEnglish: "Okay, I didn't save your request. What else would you like to do?",
+		DB.LanguageArabic:  "تمام، محفظتش طلبك. عايز تعمل إيه تاني؟",
+	},
+	"command.unanswered": {
+		DB.LanguageEnglish: "I'm sorry you didn't answer my question.",
+		DB.LanguageArabic:  "معلش إنت مجاوبتش على سؤالي.",
+	},
+	"seats.notNumber": {
+		DB.LanguageEnglish: "Please enter a number!",
+		DB.LanguageArabic:  "اكتب رقم لو سمحت!",
+	},
+	"command.unclear": {
+		DB.LanguageEnglish: "I didn't get that.",
+		DB.LanguageArabic:  "مفهمتش.",
+	},
+	"place.notFound": {
+		DB.LanguageEnglish: "I couldn't find {{.Place}} on the map.",
+		DB.LanguageArabic:  "ملقتش {{.Place}} على الخريطة.",
+	},
+	"time.notValid": {
+		DB.LanguageEnglish: "This is not a valid time: {{.Error}}.",
+		DB.LanguageArabic:  "ده مش ميعاد صحيح: {{.Error}}.",
+	},
+	"command.direction": {
+		DB.LanguageEnglish: "Are you going to the GUC, or are you leaving the GUC? (ex. 'to guc' or 'from guc')",
+		DB.LanguageArabic:  "إنت رايح الجامعة ولا خارج من الجامعة؟ (مثلاً 'rayeh el gam3a' أو 'rage3 men el gam3a')",
+	},
+	"command.place": {
+		DB.LanguageEnglish: "{{.Question}} Tell me the area (ex. 'Rehab'), or its latitude and longitude.",
+		DB.LanguageArabic:  "{{.Question}} قولي المنطقة (مثلاً 'Rehab')، أو خط العرض وخط الطول بتوعها.",
+	},
+	"command.time": {
+		DB.LanguageEnglish: "When would you like your ride to be? (ex. 'tomorrow 8am' or 'between 7:30 and 8:30')",
+		DB.LanguageArabic:  "عايز المشوار يكون إمتى؟ (مثلاً 'bokra 8 el sob7' أو 'between 7:30 and 8:30')",
+	},
+	"command.confirmRequest": {
+		DB.LanguageEnglish: "Here is your request: {{.Details}} Shall I save it? Type 'yes' to save it, 'no' to forget it, or tell me what to change (ex. 'to Maadi' or 'tomorrow 9am').",
+		DB.LanguageArabic:  "ده طلبك: {{.Details}} أحفظه؟ اكتب 'aywa' عشان أحفظه، أو 'la' عشان أنساه، أو قولي تغير إيه (مثلاً 'le Maadi' أو 'bokra 9 el sob7').",
+	},
+	"seats.tooMany": {
+		DB.LanguageEnglish: "Your car can take 1 to {{.Seats}} more passengers, not including yourself.",
+		DB.LanguageArabic:  "عربيتك تاخد من 1 لـ {{.Seats}} ركاب كمان، من غيرك.",
+	},
+	"command.seats": {
+		DB.LanguageEnglish: "How many passengers can you take with you? (up to {{.Seats}})",
+		DB.LanguageArabic:  "تقدر تاخد كام راكب معاك؟ (لحد {{.Seats}})",
+	},
+	"command.confirmCarpool": {
+		DB.LanguageEnglish: "Here is your carpool: {{.Details}} Shall I create it? Type 'yes' to create it, 'no' to forget it, or tell me what to change (ex. 'from Maadi' or '2 seats').",
+		DB.LanguageArabic:  "ده مشوارك: {{.Details}} أعمله؟ اكتب 'aywa' عشان أعمله، أو 'la' عشان أنساه، أو قولي تغير إيه (مثلاً 'men Maadi' أو '2 seats').",
+	},
+	"draft.location": {
+		DB.LanguageEnglish: "the location with the latitude {{.Latitude}} and the longitude {{.Longitude}}",
+		DB.LanguageArabic:  "المكان اللي خط عرضه {{.Latitude}} وخط طوله {{.Longitude}}",
+	},
+	"draft.toGUC": {
+		DB.LanguageEnglish: "going to the GUC from {{.Place}}, on {{.Time}}, in your {{.Vehicle}}, with {{.Seats}} seats for passengers.",
+		DB.LanguageArabic:  "رايح الجامعة من {{.Place}}، يوم {{.Time}}، بعربيتك {{.Vehicle}}، وفيها {{.Seats}} كراسي للركاب.",
+	},
+	"draft.fromGUC": {
+		DB.LanguageEnglish: "leaving the GUC to {{.Place}}, on {{.Time}}, in your {{.Vehicle}}, with {{.Seats}} seats for passengers.",
+		DB.LanguageArabic:  "خارج من الجامعة لـ {{.Place}}، يوم {{.Time}}، بعربيتك {{.Vehicle}}، وفيها {{.Seats}} كراسي للركاب.",
+	},
+
+	// Back and undo
+	"back.nothing": {
+		DB.LanguageEnglish: "There is nothing to go back to, you aren't creating or requesting a carpool right now. You can change your request by typing 'edit request', or a carpool by typing 'edit carpool ID'",
+		DB.LanguageArabic:  "مفيش حاجة نرجع لها، إنت مش بتعمل ولا بتطلب مشوار دلوقتي. تقدر تغير طلبك لما تكتب 'edit request'، أو مشوار لما تكتب 'edit carpool ID'",
+	},
+	"back.created": {
+		DB.LanguageEnglish: "Your carpool {{.PostID}} is already created, you can change it by typing 'edit carpool {{.PostID}}'.",
+		DB.LanguageArabic:  "مشوارك {{.PostID}} اتعمل خلاص، تقدر تغيره لما تكتب 'edit carpool {{.PostID}}'.",
+	},
+	"back.start": {
+		DB.LanguageEnglish: "Okay, we're back at the start. Type 'create' to offer a ride, or 'request' to find one.",
+		DB.LanguageArabic:  "تمام، رجعنا للأول. اكتب 'create' (أو 'hasou2') عشان تعرض توصيلة، أو 'request' (أو '3ayez arkab') عشان تدور على واحدة.",
+	},
+	"back.done": {
+		DB.LanguageEnglish: "Okay, let's go back.",
+		DB.LanguageArabic:  "تمام، يلا نرجع.",
+	},
+	"undo.hint": {
+		DB.LanguageEnglish: "Type 'undo' in the next {{.Minutes}} minutes if that was a mistake.",
+		DB.LanguageArabic:  "اكتب 'undo' خلال {{.Minutes}} دقايق لو دي كانت غلطة.",
+	},
+	"undo.nothing": {
+		DB.LanguageEnglish: "There is nothing I can undo. I can undo deleting a carpool, rejecting a passenger or cancelling your request, for {{.Minutes}} minutes after it.",
+		DB.LanguageArabic:  "مفيش حاجة أقدر أرجعها. أقدر أرجع مسح مشوار أو رفض راكب أو إلغاء طلبك، خلال {{.Minutes}} دقايق بعدها.",
+	},
+	"undo.tooLate": {
+		DB.LanguageEnglish: "I'm sorry, but it's too late to undo that. I can only undo it for {{.Minutes}} minutes after it.",
+		DB.LanguageArabic:  "معلش، فات الأوان عشان أرجعها. أقدر أرجعها بس خلال {{.Minutes}} دقايق بعدها.",
+	},
+	"undo.rejectError": {
+		DB.LanguageEnglish: "I couldn't bring the passenger with ID {{.Passenger}} back in your carpool {{.PostID}}: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أرجع الراكب {{.Passenger}} لمشوارك {{.PostID}}: {{.Error}}",
+	},
+	"undo.rejected": {
+		DB.LanguageEnglish: "I brought the passenger with ID {{.Passenger}} back in your carpool {{.PostIDs}}, and let them know. What else would you like to do?",
+		DB.LanguageArabic:  "رجعت الراكب {{.Passenger}} لمشوارك {{.PostIDs}}، وبلغته. عايز تعمل إيه تاني؟",
+	},
+	"undo.deleteError": {
+		DB.LanguageEnglish: "I couldn't bring back your carpool {{.PostID}}: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أرجع مشوارك {{.PostID}}: {{.Error}}",
+	},
+	"undo.linkError": {
+		DB.LanguageEnglish: "I brought back your carpool {{.PostID}}, but I couldn't make it a round trip again. Error: {{.Error}}",
+		DB.LanguageArabic:  "رجعت مشوارك {{.PostID}}، بس مقدرتش أرجعه رايح جاي. الخطأ: {{.Error}}",
+	},
+	"undo.deleted": {
+		DB.LanguageEnglish: "Your carpool {{.PostIDs}} is back, with the passengers that didn't choose another carpool since. You can see it by typing 'view carpool {{.PostID}}'.",
+		DB.LanguageArabic:  "مشوارك {{.PostIDs}} رجع، مع الركاب اللي مختاروش مشوار تاني من ساعتها. تقدر تشوفه لما تكتب 'view carpool {{.PostID}}'.",
+	},
+	"undo.chosenSince": {
+		DB.LanguageEnglish: "You chose another carpool since you cancelled your request, so I can't bring it back.",
+		DB.LanguageArabic:  "إنت اخترت مشوار تاني من ساعة ما لغيت طلبك، فمقدرش أرجعه.",
+	},
+	"undo.cancelled": {
+		DB.LanguageEnglish: "Your request is back!",
+		DB.LanguageArabic:  "طلبك رجع!",
+	},
+	"undo.details": {
+		DB.LanguageEnglish: "Here are the details: {{.Details}}",
+		DB.LanguageArabic:  "دي التفاصيل: {{.Details}}",
+	},
+	"undo.rejoinError": {
+		DB.LanguageEnglish: "I couldn't put you back in carpool {{.PostID}}: {{.Error}}.",
+		DB.LanguageArabic:  "مقدرتش أرجعك للمشوار {{.PostID}}: {{.Error}}.",
+	},
+	"undo.inAgain": {
+		DB.LanguageEnglish: "You're in carpool {{.PostID}} again.",
+		DB.LanguageArabic:  "إنت في المشوار {{.PostID}} تاني.",
+	},
+	"undo.waitingAgain": {
+		DB.LanguageEnglish: "You're waiting for the driver of carpool {{.PostID}} to accept you again.",
+		DB.LanguageArabic:  "إنت مستني سواق المشوار {{.PostID}} يقبلك تاني.",
+	},
+
+	// Cars
+	"vehicle.which": {
+		DB.LanguageEnglish: "Which car will you be driving?",
+		DB.LanguageArabic:  "هتسوق أنهي عربية؟",
+	},
+	"vehicle.describe": {
+		DB.LanguageEnglish: "Tell me the make, colour, plate and number of seats for passengers of your car. (ex. 'Hyundai Elantra, white, ABC 123, 4')",
+		DB.LanguageArabic:  "قولي نوع عربيتك ولونها ونمرتها وعدد كراسي الركاب فيها. (مثلاً 'Hyundai Elantra, white, ABC 123, 4')",
+	},
+	"vehicle.choose": {
+		DB.LanguageEnglish: "Type its number or its plate.",
+		DB.LanguageArabic:  "اكتب رقمها أو نمرتها.",
+	},
+	"vehicle.invalid": {
+		DB.LanguageEnglish: "I'm sorry, but {{.Error}}",
+		DB.LanguageArabic:  "معلش، بس {{.Error}}",
+	},
+	"vehicle.added": {
+		DB.LanguageEnglish: "I added your car to your profile.",
+		DB.LanguageArabic:  "ضفت عربيتك لبياناتك.",
+	},
+	"vehicle.notYours": {
+		DB.LanguageEnglish: "I'm sorry, but you don't have that car.",
+		DB.LanguageArabic:  "معلش، بس إنت معندكش العربية دي.",
+	},
+	"vehicle.chosen": {
+		DB.LanguageEnglish: "You'll be driving your {{.Vehicle}}.",
+		DB.LanguageArabic:  "هتسوق عربيتك {{.Vehicle}}.",
+	},
+
+	// Carpool details
+	"carpool.id": {
+		DB.LanguageEnglish: "PostID: {{.PostID}}",
+		DB.LanguageArabic:  "رقم المشوار: {{.PostID}}",
+	},
+	"carpool.driver": {
+		DB.LanguageEnglish: "Driver Name: {{.Driver}}",
+		DB.LanguageArabic:  "السواق: {{.Driver}}",
+	},
+	"carpool.fromGUC": {
+		DB.LanguageEnglish: "Leaving the GUC",
+		DB.LanguageArabic:  "خارج من الجامعة",
+	},
+	"carpool.toGUC": {
+		DB.LanguageEnglish: "Going to the GUC",
+		DB.LanguageArabic:  "رايح الجامعة",
+	},
+	"carpool.address": {
+		DB.LanguageEnglish: "Address: {{.Address}}",
+		DB.LanguageArabic:  "العنوان: {{.Address}}",
+	},
+	"carpool.location": {
+		DB.LanguageEnglish: "Latitude: {{.Latitude}}, Longitude: {{.Longitude}}",
+		DB.LanguageArabic:  "خط العرض: {{.Latitude}}، خط الطول: {{.Longitude}}",
+	},
+	"carpool.start": {
+		DB.LanguageEnglish: "Start Time: {{.Time}}",
+		DB.LanguageArabic:  "الميعاد: {{.Time}}",
+	},
+	"carpool.roundTrip": {
+		DB.LanguageEnglish: "Round trip with carpool {{.PostID}}",
+		DB.LanguageArabic:  "رايح جاي مع المشوار {{.PostID}}",
+	},
+	"carpool.schedule": {
+		DB.LanguageEnglish: "Every week, schedule {{.ScheduleID}}",
+		DB.LanguageArabic:  "كل أسبوع، جدول رقم {{.ScheduleID}}",
+	},
+	"carpool.seats": {
+		DB.LanguageEnglish: "Available Seats: {{.Seats}}",
+		DB.LanguageArabic:  "الكراسي الفاضية: {{.Seats}}",
+	},
+	"carpool.status": {
+		DB.LanguageEnglish: "Status: {{.Status}}",
+		DB.LanguageArabic:  "الحالة: {{.Status}}",
+	},
+	"status.open": {
+		DB.LanguageEnglish: "open",
+		DB.LanguageArabic:  "متاح",
+	},
+	"status.full": {
+		DB.LanguageEnglish: "full",
+		DB.LanguageArabic:  "كامل",
+	},
+	"status.departed": {
+		DB.LanguageEnglish: "departed",
+		DB.LanguageArabic:  "اتحرك",
+	},
+	"status.completed": {
+		DB.LanguageEnglish: "completed",
+		DB.LanguageArabic:  "خلص",
+	},
+	"status.cancelled": {
+		DB.LanguageEnglish: "cancelled",
+		DB.LanguageArabic:  "اتلغى",
+	},
+	"status.expired": {
+		DB.LanguageEnglish: "expired",
+		DB.LanguageArabic:  "فات ميعاده",
+	},
+	"carpool.passengers": {
+		DB.LanguageEnglish: "Current Passengers: {{.Passengers}}",
+		DB.LanguageArabic:  "الركاب: {{.Passengers}}",
+	},
+	"carpool.requesting": {
+		DB.LanguageEnglish: "Requesting Passengers: {{.Passengers}}",
+		DB.LanguageArabic:  "طالبين يركبوا: {{.Passengers}}",
+	},
+	"none": {
+		DB.LanguageEnglish: "none",
+		DB.LanguageArabic:  "مفيش",
+	},
+	"rating": {
+		DB.LanguageEnglish: " ({{.Stars}}/5 from {{.Ratings}} rating{{if ne .Ratings 1}}s{{end}})",
+		DB.LanguageArabic:  " ({{.Stars}}/5 من {{.Ratings}} {{if eq .Ratings 1}}تقييم{{else}}تقييمات{{end}})",
+	},
+
+	// Chat
+	"emails.startedNoAddress": {
+		DB.LanguageEnglish: "I will email you whenever something happens to your carpools. You can type 'stop emails' to turn them off.",
+		DB.LanguageArabic:  "هبعتلك إيميل كل ما يحصل حاجة في مشاويرك. ممكن توقفها لما تكتب 'stop emails'.",
+	},
+
+	// Schedules
+	"sorry": {
+		DB.LanguageEnglish: "I'm sorry, but {{.Error}}",
+		DB.LanguageArabic:  "معلش، بس {{.Error}}",
+	},
+	"schedules.none": {
+		DB.LanguageEnglish: "You don't have any weekly schedules. You can repeat one of your carpools every week by typing something like 'repeat carpool 12 every sun, tue until 2026-12-31'.",
+		DB.LanguageArabic:  "معندكش جداول أسبوعية. تقدر تكرر مشوار من مشاويرك كل أسبوع لما تكتب حاجة زي 'repeat carpool 12 every sun, tue until 2026-12-31'.",
+	},
+	"schedules.view": {
+		DB.LanguageEnglish: "Here are your weekly schedules!",
+		DB.LanguageArabic:  "دي جداولك الأسبوعية!",
+	},
+	"schedule.which": {
+		DB.LanguageEnglish: "Which schedule do you mean? You can see yours by typing 'view schedules', then type 'pause schedule ID', 'pause schedule ID on year-month-day', 'resume schedule ID', 'add GUCID to schedule ID' or 'remove GUCID from schedule ID'.",
+		DB.LanguageArabic:  "تقصد أنهي جدول؟ تقدر تشوف جداولك لما تكتب 'view schedules'، وبعدين اكتب 'pause schedule ID' أو 'pause schedule ID on year-month-day' أو 'resume schedule ID' أو 'add GUCID to schedule ID' أو 'remove GUCID from schedule ID'.",
+	},
+	"schedule.notYours": {
+		DB.LanguageEnglish: "You don't have a schedule with the ID {{.ScheduleID}}. You can see yours by typing 'view schedules'.",
+		DB.LanguageArabic:  "معندكش جدول رقمه {{.ScheduleID}}. تقدر تشوف جداولك لما تكتب 'view schedules'.",
+	},
+	"schedule.resumed": {
+		DB.LanguageEnglish: "Schedule {{.ScheduleID}} is back on. I'll create its carpools ahead of time again.",
+		DB.LanguageArabic:  "جدول {{.ScheduleID}} رجع تاني. هعمل مشاويره بدري زي الأول.",
+	},
+	"schedule.usage": {
+		DB.LanguageEnglish: "I can pause, resume, or change the standing passengers of a schedule. (ex. 'pause schedule {{.ScheduleID}}', 'add 34-1234 to schedule {{.ScheduleID}}')",
+		DB.LanguageArabic:  "أقدر أوقف الجدول، أو أرجعه، أو أغير الركاب الثابتين فيه. (مثلاً 'pause schedule {{.ScheduleID}}'، 'add 34-1234 to schedule {{.ScheduleID}}')",
+	},
+	"schedule.saveError": {
+		DB.LanguageEnglish: "I could not save your schedule at the moment, please try again later.",
+		DB.LanguageArabic:  "مقدرتش أحفظ جدولك دلوقتي، جرب تاني بعدين.",
+	},
+	"repeat.usage": {
+		DB.LanguageEnglish: "To repeat a carpool every week, type 'repeat carpool', its ID, the days and the last day (ex. 'repeat carpool 12 every sun, tue until 2026-12-31').",
+		DB.LanguageArabic:  "عشان تكرر مشوار كل أسبوع، اكتب 'repeat carpool' ورقمه والأيام وآخر يوم (مثلاً 'repeat carpool 12 every sun, tue until 2026-12-31').",
+	},
+	"repeat.already": {
+		DB.LanguageEnglish: "Carpool {{.PostID}} is already repeated every week by schedule {{.ScheduleID}}.",
+		DB.LanguageArabic:  "مشوار {{.PostID}} بيتكرر كل أسبوع أصلاً في جدول {{.ScheduleID}}.",
+	},
+	"repeat.done": {
+		DB.LanguageEnglish: "Done! Carpool {{.PostID}} is now repeated every week as schedule {{.ScheduleID}}:\n{{.Schedule}}I create its carpools {{.Days}} days ahead, with the standing passengers already asking to join them.",
+		DB.LanguageArabic:  "تمام! مشوار {{.PostID}} بقى بيتكرر كل أسبوع في جدول {{.ScheduleID}}:\n{{.Schedule}}بعمل مشاويره قبلها بـ{{.Days}} أيام، والركاب الثابتين طالبين ينضموا لها.",
+	},
+	"schedule.paused": {
+		DB.LanguageEnglish: "Schedule {{.ScheduleID}} is paused. Type 'resume schedule {{.ScheduleID}}' when you want it back.",
+		DB.LanguageArabic:  "جدول {{.ScheduleID}} وقف. اكتب 'resume schedule {{.ScheduleID}}' لما تحب ترجعه.",
+	},
+	"schedule.skipped": {
+		DB.LanguageEnglish: "There will be no carpool from schedule {{.ScheduleID}} on {{.Date}}.",
+		DB.LanguageArabic:  "مفيش مشوار من جدول {{.ScheduleID}} يوم {{.Date}}.",
+	},
+	"standing.removed": {
+		DB.LanguageEnglish: "{{.Passenger}} will not be added to the carpools of schedule {{.ScheduleID}} anymore.",
+		DB.LanguageArabic:  "{{.Passenger}} مش هيتضاف لمشاوير جدول {{.ScheduleID}} تاني.",
+	},
+	"standing.added": {
+		DB.LanguageEnglish: "{{.Name}} will ask to join every carpool of schedule {{.ScheduleID}} I create from now on. Accept them in each carpool like any other passenger.",
+		DB.LanguageArabic:  "{{.Name}} هيطلب ينضم لكل مشوار أعمله من جدول {{.ScheduleID}} من دلوقتي. اقبله في كل مشوار زي أي راكب تاني.",
+	},
+	"schedule.id": {
+		DB.LanguageEnglish: "Schedule: {{.ScheduleID}}",
+		DB.LanguageArabic:  "جدول: {{.ScheduleID}}",
+	},
+	"schedule.toGUC": {
+		DB.LanguageEnglish: "Going to the GUC every {{.Days}} at {{.Time}}",
+		DB.LanguageArabic:  "رايح الجامعة كل {{.Days}} الساعة {{.Time}}",
+	},
+	"schedule.fromGUC": {
+		DB.LanguageEnglish: "Leaving the GUC every {{.Days}} at {{.Time}}",
+		DB.LanguageArabic:  "خارج من الجامعة كل {{.Days}} الساعة {{.Time}}",
+	},
+	"schedule.dates": {
+		DB.LanguageEnglish: "From {{.From}} until {{.Until}}",
+		DB.LanguageArabic:  "من {{.From}} لحد {{.Until}}",
+	},
+	"schedule.seats": {
+		DB.LanguageEnglish: "Seats: {{.Seats}}",
+		DB.LanguageArabic:  "الكراسي: {{.Seats}}",
+	},
+	"schedule.standing": {
+		DB.LanguageEnglish: "Standing Passengers: {{.Passengers}}",
+		DB.LanguageArabic:  "الركاب الثابتين: {{.Passengers}}",
+	},
+	"schedule.skippedDays": {
+		DB.LanguageEnglish: "Skipped: {{.Days}}",
+		DB.LanguageArabic:  "أيام ملغية: {{.Days}}",
+	},
+	"schedule.pausedLine": {
+		DB.LanguageEnglish: "Paused",
+		DB.LanguageArabic:  "متوقف",
+	},
+
+	// Timetable
+	"timetable.saveError": {
+		DB.LanguageEnglish: "I could not save your timetable at the moment, please try again later.",
+		DB.LanguageArabic:  "مقدرتش أحفظ جدول محاضراتك دلوقتي، جرب تاني بعدين.",
+	},
+	"timetable.removed": {
+		DB.LanguageEnglish: "I forgot your timetable, and I won't email you about new carpools anymore.",
+		DB.LanguageArabic:  "نسيت جدول محاضراتك، ومش هبعتلك إيميلات عن المشاوير الجديدة تاني.",
+	},
+	"timetable.none": {
+		DB.LanguageEnglish: "I don't have your timetable yet. Type 'timetable', then paste your iCalendar file, or one class per line with its day, slot and course, on the next lines (ex. 'sun,1,CSEN 701'). The slots are 1 to 5, or the times of the class (ex. 'sun,8:30-10:00,CSEN 701').",
+		DB.LanguageArabic:  "لسه معنديش جدول محاضراتك. اكتب 'timetable'، وفي السطور اللي بعدها الزق ملف الـiCalendar بتاعك، أو كل محاضرة في سطر بيومها والفترة والمادة (مثلاً 'sun,1,CSEN 701'). الفترات من 1 لـ5، أو مواعيد المحاضرة (مثلاً 'sun,8:30-10:00,CSEN 701').",
+	},
+	"timetable.view": {
+		DB.LanguageEnglish: "Here is your timetable!",
+		DB.LanguageArabic:  "ده جدول محاضراتك!",
+	},
+	"timetable.readError": {
+		DB.LanguageEnglish: "I couldn't read your timetable: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أقرا جدول محاضراتك: {{.Error}}",
+	},
+	"timetable.saved": {
+		DB.LanguageEnglish: "I saved your timetable!",
+		DB.LanguageArabic:  "حفظت جدول محاضراتك!",
+	},
+	"timetable.class": {
+		DB.LanguageEnglish: "{{.Day}} {{.Start}} to {{.End}}{{if .Name}}: {{.Name}}{{end}}",
+		DB.LanguageArabic:  "{{.Day}} من {{.Start}} لـ{{.End}}{{if .Name}}: {{.Name}}{{end}}",
+	},
+	"suggestions.title": {
+		DB.LanguageEnglish: "The rides you need every week are:",
+		DB.LanguageArabic:  "المشاوير اللي محتاجها كل أسبوع:",
+	},
+	"suggestions.subscribed": {
+		DB.LanguageEnglish: " (subscribed)",
+		DB.LanguageArabic:  " (مشترك)",
+	},
+	"suggestions.usage": {
+		DB.LanguageEnglish: "Type 'request suggestion N' to request one of them, or 'subscribe suggestion N' to get an email whenever a carpool that fits it is created.",
+		DB.LanguageArabic:  "اكتب 'request suggestion N' عشان تطلب واحد منهم، أو 'subscribe suggestion N' عشان يجيلك إيميل كل ما يتعمل مشوار يناسبه.",
+	},
+	"suggestion.notFound": {
+		DB.LanguageEnglish: "There is no suggestion {{.Number}}. You can see your suggested rides by typing 'timetable'.",
+		DB.LanguageArabic:  "مفيش اقتراح رقم {{.Number}}. تقدر تشوف المشاوير المقترحة لما تكتب 'timetable'.",
+	},
+	"suggestion.subscribed": {
+		DB.LanguageEnglish: "I'll email you whenever a carpool {{.Suggestion}} is created. Type 'unsubscribe suggestion {{.Number}}' to stop.",
+		DB.LanguageArabic:  "هبعتلك إيميل كل ما يتعمل مشوار {{.Suggestion}}. اكتب 'unsubscribe suggestion {{.Number}}' عشان أبطل.",
+	},
+	"suggestion.unsubscribed": {
+		DB.LanguageEnglish: "I won't email you about carpools {{.Suggestion}} anymore.",
+		DB.LanguageArabic:  "مش هبعتلك إيميلات عن المشاوير {{.Suggestion}} تاني.",
+	},
+	"subscription.saveError": {
+		DB.LanguageEnglish: "I could not save your subscription at the moment, please try again later.",
+		DB.LanguageArabic:  "مقدرتش أحفظ اشتراكك دلوقتي، جرب تاني بعدين.",
+	},
+	"suggestion.closed": {
+		DB.LanguageEnglish: "The GUC is closed on {{.Day}} for the next {{.Weeks}} weeks, so there is no ride to request.",
+		DB.LanguageArabic:  "الجامعة قافلة يوم {{.Day}} الـ{{.Weeks}} أسابيع الجاية، فمفيش مشوار تطلبه.",
+	},
+	"suggestion.request": {
+		DB.LanguageEnglish: "Let's find you a carpool {{.Suggestion}}, on {{.Time}}. {{.Question}} Please tell me your desired location.",
+		DB.LanguageArabic:  "يلا نلاقيلك مشوار {{.Suggestion}}، يوم {{.Time}}. {{.Question}} قولي المكان اللي عايزه.",
+	},
+	"suggestion.toGUC": {
+		DB.LanguageEnglish: "to the GUC before your {{.Time}} lecture on {{.Day}}",
+		DB.LanguageArabic:  "رايح الجامعة قبل محاضرة {{.Time}} يوم {{.Day}}",
+	},
+	"suggestion.fromGUC": {
+		DB.LanguageEnglish: "from the GUC after {{.Time}} on {{.Day}}",
+		DB.LanguageArabic:  "من الجامعة بعد {{.Time}} يوم {{.Day}}",
+	},
+
+	// Profile
+	"profile.kept": {
+		DB.LanguageEnglish: "Okay, I left your profile as it was.",
+		DB.LanguageArabic:  "تمام، سبت بياناتك زي ما هي.",
+	},
+	"profile.which": {
+		DB.LanguageEnglish: "What would you like to change? You can change your {{.Fields}}. Type 'cancel' to leave it as it is.",
+		DB.LanguageArabic:  "عايز تغير إيه؟ تقدر تغير {{.Fields}}. اكتب 'cancel' عشان تسيبها زي ما هي.",
+	},
+	"profile.view": {
+		DB.LanguageEnglish: "Here is your profile:\n{{.Profile}}You can change it by typing 'edit profile'.",
+		DB.LanguageArabic:  "دي بياناتك:\n{{.Profile}}تقدر تغيرها لما تكتب 'edit profile'.",
+	},
+	"profile.onlyFields": {
+		DB.LanguageEnglish: "I'm sorry, but I can only change your {{.Fields}}. Which one would you like to change?",
+		DB.LanguageArabic:  "معلش، بس أقدر أغير {{.Fields}} بس. عايز تغير أنهي واحدة؟",
+	},
+	"profile.saveError": {
+		DB.LanguageEnglish: "I could not save your profile at the moment, please try again later.",
+		DB.LanguageArabic:  "مقدرتش أحفظ بياناتك دلوقتي، جرب تاني بعدين.",
+	},
+	"profile.changed": {
+		DB.LanguageEnglish: "I changed your {{.Field}}. Here is your profile now:\n{{.Profile}}",
+		DB.LanguageArabic:  "غيرت {{.Field}}. دي بياناتك دلوقتي:\n{{.Profile}}",
+	},
+	"profile.phone": {
+		DB.LanguageEnglish: "What is your phone number? (ex. '01012345678')",
+		DB.LanguageArabic:  "رقم موبايلك كام؟ (مثلاً '01012345678')",
+	},
+	"profile.language": {
+		DB.LanguageEnglish: "Which language would you like me to use, English or Arabic?",
+		DB.LanguageArabic:  "تحب أكلمك بأنهي لغة، English ولا عربي؟",
+	},
+	"profile.homeArea": {
+		DB.LanguageEnglish: "Which area do you live in? (ex. 'Maadi')",
+		DB.LanguageArabic:  "ساكن في أنهي منطقة؟ (مثلاً 'Maadi')",
+	},
+	"profile.vehicle": {
+		DB.LanguageEnglish: "Tell me the make, colour, plate and number of seats for passengers of your car. (ex. 'Hyundai Elantra, white, ABC 123, 4') You can also remove one by typing 'remove' and its plate.",
+		DB.LanguageArabic:  "قولي نوع عربيتك ولونها ونمرتها وعدد كراسي الركاب فيها. (مثلاً 'Hyundai Elantra, white, ABC 123, 4') وتقدر كمان تشيل عربية لما تكتب 'remove' ونمرتها.",
+	},
+	"profile.emails": {
+		DB.LanguageEnglish: "Would you like me to email you when something happens to your carpools? (on or off)",
+		DB.LanguageArabic:  "تحب أبعتلك إيميل لما يحصل حاجة في مشاويرك؟ (on ولا off)",
+	},
+	"profile.reminders": {
+		DB.LanguageEnglish: "Would you like me to email you a reminder before your rides? (on or off)",
+		DB.LanguageArabic:  "تحب أبعتلك إيميل يفكرك قبل مشاويرك؟ (on ولا off)",
+	},
+	"profile.name": {
+		DB.LanguageEnglish: "Name: {{.Name}}, GUC ID: {{.GUCID}}",
+		DB.LanguageArabic:  "الاسم: {{.Name}}، رقم الجامعة: {{.GUCID}}",
+	},
+	"profile.phoneLine": {
+		DB.LanguageEnglish: "Phone: {{.Phone}}",
+		DB.LanguageArabic:  "الموبايل: {{.Phone}}",
+	},
+	"profile.languageLine": {
+		DB.LanguageEnglish: "Language: {{.Language}}",
+		DB.LanguageArabic:  "اللغة: {{.Language}}",
+	},
+	"profile.homeAreaLine": {
+		DB.LanguageEnglish: "Home Area: {{.Area}}",
+		DB.LanguageArabic:  "المنطقة: {{.Area}}",
+	},
+	"profile.noVehicles": {
+		DB.LanguageEnglish: "Vehicles: none",
+		DB.LanguageArabic:  "العربيات: مفيش",
+	},
+	"profile.vehicleLine": {
+		DB.LanguageEnglish: "Vehicle {{.Number}}: {{.Vehicle}} ({{.Seats}} seats)",
+		DB.LanguageArabic:  "العربية {{.Number}}: {{.Vehicle}} ({{.Seats}} كراسي)",
+	},
+	"profile.notifications": {
+		DB.LanguageEnglish: "Emails: {{.Emails}}, Departure Reminders: {{.Reminders}}",
+		DB.LanguageArabic:  "الإيميلات: {{.Emails}}، التفكير قبل المشوار: {{.Reminders}}",
+	},
+	"profile.notSet": {
+		DB.LanguageEnglish: "not set",
+		DB.LanguageArabic:  "مش متحدد",
+	},
+	"profile.on": {
+		DB.LanguageEnglish: "on",
+		DB.LanguageArabic:  "شغالة",
+	},
+	"profile.off": {
+		DB.LanguageEnglish: "off",
+		DB.LanguageArabic:  "مقفولة",
+	},
+	"language.english": {
+		DB.LanguageEnglish: "English",
+		DB.LanguageArabic:  "الإنجليزي",
+	},
+	"language.arabic": {
+		DB.LanguageEnglish: "Arabic",
+		DB.LanguageArabic:  "العربي",
+	},
+
+	// Academic calendar
+	"calendar.error": {
+		DB.LanguageEnglish: "I couldn't check the academic calendar right now. Please try again later",
+		DB.LanguageArabic:  "مقدرتش أشوف التقويم الأكاديمي دلوقتي. جرب تاني بعدين",
+	},
+	"campus.closed": {
+		DB.LanguageEnglish: "The GUC is closed on {{.Day}} ({{.Reason}}). Please choose a different day",
+		DB.LanguageArabic:  "الجامعة قافلة يوم {{.Day}} ({{.Reason}}). اختار يوم تاني",
+	},
+	"closure.adminsOnly": {
+		DB.LanguageEnglish: "Only the admins of GUC Carpool can close the campus.",
+		DB.LanguageArabic:  "الأدمنز بتوع GUC Carpool بس هما اللي يقدروا يقفلوا الجامعة.",
+	},
+	"closure.usage": {
+		DB.LanguageEnglish: "To close the campus, type 'close campus on', the day, and optionally the last day and the reason (ex. 'close campus on 2026-11-02 until 2026-11-03 because of the storm').",
+		DB.LanguageArabic:  "عشان تقفل الجامعة، اكتب 'close campus on' واليوم، ولو حابب آخر يوم والسبب (مثلاً 'close campus on 2026-11-02 until 2026-11-03 because of the storm').",
+	},
+	"closure.saveError": {
+		DB.LanguageEnglish: "I could not close the campus at the moment, please try again later.",
+		DB.LanguageArabic:  "مقدرتش أقفل الجامعة دلوقتي، جرب تاني بعدين.",
+	},
+	"closure.findError": {
+		DB.LanguageEnglish: "The campus is closed, but I couldn't find the carpools on those days. Error: {{.Error}}",
+		DB.LanguageArabic:  "الجامعة اتقفلت، بس مقدرتش ألاقي المشاوير اللي في الأيام دي. الخطأ: {{.Error}}",
+	},
+	"closure.done": {
+		DB.LanguageEnglish: "Done! {{.Closure}} is on the calendar. I cancelled the {{.Count}} carpools on those days and emailed their drivers and passengers.",
+		DB.LanguageArabic:  "تمام! {{.Closure}} اتضاف للتقويم. لغيت الـ{{.Count}} مشاوير اللي في الأيام دي وبعت إيميل لسواقينها وركابها.",
+	},
+
+	// Calendar feed
+	"feed.linkError": {
+		DB.LanguageEnglish: "I couldn't make your calendar link right now. Please try again in a moment.",
+		DB.LanguageArabic:  "مقدرتش أعمل لينك التقويم بتاعك دلوقتي. جرب تاني كمان شوية.",
+	},
+	"feed.link": {
+		DB.LanguageEnglish: "Add this address to your calendar app as a subscription, and your rides will show up in it: {{.URL}}\nKeep it to yourself, anyone with it can see your rides.",
+		DB.LanguageArabic:  "ضيف العنوان ده لأبلكيشن التقويم بتاعك كاشتراك، ومشاويرك هتظهر فيه: {{.URL}}\nخليه معاك، أي حد معاه يقدر يشوف مشاويرك.",
+	},
+	"feed.renewed": {
+		DB.LanguageEnglish: "Your old calendar link doesn't work anymore. {{.Link}}",
+		DB.LanguageArabic:  "لينك التقويم القديم مبقاش شغال. {{.Link}}",
+	},
+	"feed.shareHint": {
+		DB.LanguageEnglish: " If you shared it by mistake, type 'new calendar link'.",
+		DB.LanguageArabic:  " لو شيرته بالغلط، اكتب 'new calendar link'.",
+	},
+	"feed.ridesError": {
+		DB.LanguageEnglish: "I couldn't find your rides right now. Please try again in a moment.",
+		DB.LanguageArabic:  "مقدرتش ألاقي مشاويرك دلوقتي. جرب تاني كمان شوية.",
+	},
+	"feed.driving": {
+		DB.LanguageEnglish: "Driving carpool {{.PostID}} {{.Direction}}",
+		DB.LanguageArabic:  "سايق مشوار {{.PostID}} {{.Direction}}",
+	},
+	"feed.riding": {
+		DB.LanguageEnglish: "Carpool {{.PostID}} with {{.Driver}} {{.Direction}}",
+		DB.LanguageArabic:  "مشوار {{.PostID}} مع {{.Driver}} {{.Direction}}",
+	},
+	"feed.toGUC": {
+		DB.LanguageEnglish: "to the GUC",
+		DB.LanguageArabic:  "رايح الجامعة",
+	},
+	"feed.fromGUC": {
+		DB.LanguageEnglish: "from the GUC",
+		DB.LanguageArabic:  "من الجامعة",
+	},
+	"feed.location": {
+		DB.LanguageEnglish: "latitude {{.Latitude}}, longitude {{.Longitude}}",
+		DB.LanguageArabic:  "خط العرض {{.Latitude}}، خط الطول {{.Longitude}}",
+	},
+	"feed.pickup": {
+		DB.LanguageEnglish: "Pickup: {{.Place}}",
+		DB.LanguageArabic:  "مكان الركوب: {{.Place}}",
+	},
+	"feed.pickupGUC": {
+		DB.LanguageEnglish: "Pickup: the GUC\nDrop off: {{.Place}}",
+		DB.LanguageArabic:  "مكان الركوب: الجامعة\nمكان النزول: {{.Place}}",
+	},
+	"feed.driver": {
+		DB.LanguageEnglish: "Driver: {{.Driver}}",
+		DB.LanguageArabic:  "السواق: {{.Driver}}",
+	},
+	"feed.passengers": {
+		DB.LanguageEnglish: "Passengers: {{.Passengers}}",
+		DB.LanguageArabic:  "الركاب: {{.Passengers}}",
+	},
+	"feed.car": {
+		DB.LanguageEnglish: "Car: {{.Vehicle}}",
+		DB.LanguageArabic:  "العربية: {{.Vehicle}}",
+	},
+
+	// Round trips
+	"join.notOpen": {
+		DB.LanguageEnglish: "Carpool {{.PostID}} is {{.Status}}, so it can't be joined anymore. Type 'view all' to see the carpools you can join.",
+		DB.LanguageArabic:  "مشوار {{.PostID}} {{.Status}}، فمينفعش تنضمله خلاص. اكتب 'view all' عشان تشوف المشاوير اللي تقدر تنضملها.",
+	},
+	"join.full": {
+		DB.LanguageEnglish: "Carpool {{.PostID}} is full. Type 'view all' to see the carpools you can join.",
+		DB.LanguageArabic:  "مشوار {{.PostID}} مليان. اكتب 'view all' عشان تشوف المشاوير اللي تقدر تنضملها.",
+	},
+
+	// Logging in
+	"login.both": {
+		DB.LanguageEnglish: "Something went wrong. You have to give me both your name and your GUC-ID in order to successfully start your session.  It is so easy you just write them :D",
+		DB.LanguageArabic:  "في حاجة غلط. لازم تديني اسمك ورقم الجامعة بتاعك عشان أبدألك الجلسة. سهلة خالص، اكتبهم بس :D",
+	},
+	"login.empty": {
+		DB.LanguageEnglish: "Something went wrong. You have to give me both your name and your GUC-ID in order to successfully start your session. Please try again. I can not infer this Info but when I grow up I may.",
+		DB.LanguageArabic:  "في حاجة غلط. لازم تديني اسمك ورقم الجامعة بتاعك عشان أبدألك الجلسة. جرب تاني. مقدرش أخمنهم دلوقتي، بس لما أكبر يمكن أقدر.",
+	},
+	"login.invalidID": {
+		DB.LanguageEnglish: "Your GUC ID is invalid. Are you sure you entered it correctly? type it correctly or I will keep anoying you with this message. (ex. '12-3456')",
+		DB.LanguageArabic:  "رقم الجامعة ده مش صحيح. متأكد إنك كتبته صح؟ اكتبه صح وإلا هفضل أزهقك بالرسالة دي. (مثلاً '12-3456')",
+	},
+	"login.sendError": {
+		DB.LanguageEnglish: "I could not send you a code: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أبعتلك كود: {{.Error}}",
+	},
+	"login.codeSent": {
+		DB.LanguageEnglish: "Hello {{.Name}}. To make sure it's you, I emailed a 6-digit code to {{.Address}}. Please send me that code to log in.",
+		DB.LanguageArabic:  "أهلاً {{.Name}}. عشان أتأكد إنه إنت، بعتلك كود من 6 أرقام على {{.Address}}. ابعتلي الكود ده عشان تدخل.",
+	},
+	"login.busy": {
+		DB.LanguageEnglish: "I'm still busy with another code of yours. Please try again in a moment.",
+		DB.LanguageArabic:  "لسه مشغول بكود تاني بتاعك. جرب تاني كمان شوية.",
+	},
+	"login.wrongCode": {
+		DB.LanguageEnglish: "I'm sorry, but {{.Error}}. Please check the email and try again.",
+		DB.LanguageArabic:  "معلش، بس {{.Error}}. بص في الإيميل تاني وجرب كمان مرة.",
+	},
+	"login.failed": {
+		DB.LanguageEnglish: "I could not log you in: {{.Error}}",
+		DB.LanguageArabic:  "مقدرتش أدخلك: {{.Error}}",
+	},
+	"login.notStudent": {
+		DB.LanguageEnglish: "I'm sorry, but {{.Error}}. Only current GUC students can use GUC Carpool.",
+		DB.LanguageArabic:  "معلش، بس {{.Error}}. طلبة الجامعة الحاليين بس هما اللي يقدروا يستخدموا GUC Carpool.",
+	},
+	"login.welcome": {
+		DB.LanguageEnglish: "Hello {{.Name}}. You can view all available carpools by typing 'view all', or 'view carpool' to view the ones you already have, cancel your request by typing 'cancel request', edit your request by typing 'edit request' or choose an available carpool by typing 'choose ID' where ID is the postID of the carpool of your choice, or 'choose both ID' to ride both ways of a round trip. You can also choose to offer other people a ride by creating a carpool by typing 'create', as many times as you drive, and change one by typing 'edit carpool ID' or 'delete carpool ID', repeat one every week by typing 'repeat carpool ID every sun, tue until year-month-day' and see those by typing 'view schedules', or specify the details of a carpool you wish to request by typing 'request'. or view notifications for  your carpool or request by typing notify",
+		DB.LanguageArabic:  "أهلاً {{.Name}}. تقدر تشوف كل المشاوير المتاحة لما تكتب 'view all'، أو 'view carpool' عشان تشوف مشاويرك، تلغي طلبك لما تكتب 'cancel request'، تعدل طلبك لما تكتب 'edit request' أو تختار مشوار متاح لما تكتب 'choose ID' و ID هو رقم المشوار اللي اخترته، أو 'choose both ID' عشان تركب الرايح والجاي. وتقدر كمان توصل ناس تانية لما تعمل مشوار وتكتب 'create'، كل ما تسوق، وتغير واحد لما تكتب 'edit carpool ID' أو 'delete carpool ID'، تكرره كل أسبوع لما تكتب 'repeat carpool ID every sun, tue until year-month-day' وتشوف المتكرر لما تكتب 'view schedules'، أو تقولي تفاصيل المشوار اللي عايز تطلبه لما تكتب 'request'. أو تشوف إشعارات مشوارك أو طلبك لما تكتب 'notify'",
+	},
+	"sso.off": {
+		DB.LanguageEnglish: "Single sign-on is not set up on this server. Please use the route '/welcome' and log in through the chat.",
+		DB.LanguageArabic:  "الدخول بحساب الجامعة مش متظبط على السيرفر ده. استخدم '/welcome' وادخل من الشات.",
+	},
+	"sso.startError": {
+		DB.LanguageEnglish: "I couldn't start logging you in right now. Please try again in a moment.",
+		DB.LanguageArabic:  "مقدرتش أبدأ أدخلك دلوقتي. جرب تاني كمان شوية.",
+	},
+	"sso.unknown": {
+		DB.LanguageEnglish: "I don't know this login. Please use the route '/login' to log in again.",
+		DB.LanguageArabic:  "معرفش الدخول ده. استخدم '/login' عشان تدخل تاني.",
+	},
+	"sso.denied": {
+		DB.LanguageEnglish: "You were not logged in: {{.Error}}",
+		DB.LanguageArabic:  "مدخلتش: {{.Error}}",
+	},
+	"sso.sessionError": {
+		DB.LanguageEnglish: "I couldn't start a session for you right now. Please try again in a moment.",
+		DB.LanguageArabic:  "مقدرتش أبدألك جلسة دلوقتي. جرب تاني كمان شوية.",
+	},
+
+	// Problems
+	"problem.phone": {
+		DB.LanguageEnglish: "this is not a phone number.",
+		DB.LanguageArabic:  "ده مش رقم موبايل.",
+	},
+	"problem.language": {
+		DB.LanguageEnglish: "I can only speak English or Arabic.",
+		DB.LanguageArabic:  "أنا بتكلم English وعربي بس.",
+	},
+	"problem.homeArea": {
+		DB.LanguageEnglish: "please keep it between 1 and 100 characters.",
+		DB.LanguageArabic:  "خليها من حرف لحد 100 حرف.",
+	},
+	"problem.onOff": {
+		DB.LanguageEnglish: "please answer with on or off.",
+		DB.LanguageArabic:  "جاوب بـ on أو off.",
+	},
+	"problem.field": {
+		DB.LanguageEnglish: "you can't change your {{.Field}}",
+		DB.LanguageArabic:  "مينفعش تغير {{.Field}}",
+	},
+	"problem.vehicleDetails": {
+		DB.LanguageEnglish: "I need all four details of your car.",
+		DB.LanguageArabic:  "محتاج التفاصيل الأربعة بتاعة عربيتك.",
+	},
+	"problem.capacity": {
+		DB.LanguageEnglish: "a car can take from 1 to {{.Max}} passengers, not counting the driver.",
+		DB.LanguageArabic:  "العربية تاخد من راكب واحد لحد {{.Max}} ركاب، من غير السواق.",
+	},
+	"problem.noVehicle": {
+		DB.LanguageEnglish: "you don't have a car with the plate {{.Plate}}",
+		DB.LanguageArabic:  "معندكش عربية نمرتها {{.Plate}}",
+	},
+	"problem.timeOfDay": {
+		DB.LanguageEnglish: "I need the time of day too (ex. 'tomorrow at 8am' or 'bokra 8 el sob7')",
+		DB.LanguageArabic:  "محتاج الساعة كمان (مثلاً 'tomorrow at 8am' أو 'bokra 8 el sob7')",
+	},
+	"problem.when": {
+		DB.LanguageEnglish: "I couldn't understand when that is (ex. 'tomorrow 8am', 'sunday at 7:45', 'in 2 hours' or 'bokra 8 el sob7')",
+		DB.LanguageArabic:  "مفهمتش ده إمتى (مثلاً 'tomorrow 8am' أو 'sunday at 7:45' أو 'in 2 hours' أو 'bokra 8 el sob7')",
+	},
+	"problem.endBeforeStart": {
+		DB.LanguageEnglish: "the end of the time has to be after its start",
+		DB.LanguageArabic:  "آخر الوقت لازم يكون بعد أوله",
+	},
+	"problem.noDay": {
+		DB.LanguageEnglish: "there is no day {{.Day}}",
+		DB.LanguageArabic:  "مفيش يوم {{.Day}}",
+	},
+	"problem.noTime": {
+		DB.LanguageEnglish: "there is no time {{.Time}}",
+		DB.LanguageArabic:  "مفيش ساعة {{.Time}}",
+	},
+	"problem.noPost": {
+		DB.LanguageEnglish: "no post with this id",
+		DB.LanguageArabic:  "مفيش مشوار بالرقم ده",
+	},
+	"problem.noSeat": {
+		DB.LanguageEnglish: "no seat available",
+		DB.LanguageArabic:  "مفيش كرسي فاضي",
+	},
+	"problem.carpoolGone": {
+		DB.LanguageEnglish: "this carpool does not exist or already departed",
+		DB.LanguageArabic:  "المشوار ده مش موجود أو اتحرك خلاص",
+	},
+	"problem.deleteStatus": {
+		DB.LanguageEnglish: "you can not delete a carpool that is {{.Status}}",
+		DB.LanguageArabic:  "مينفعش تمسح مشوار حالته {{.Status}}",
+	},
+	"problem.rejectUnknown": {
+		DB.LanguageEnglish: "The passenger you're trying to reject did not request a carpool",
+		DB.LanguageArabic:  "الراكب اللي عايز ترفضه مطلبش مشوار",
+	},
+	"problem.rejectOther": {
+		DB.LanguageEnglish: "You can not reject a passenger that did not request your carpool",
+		DB.LanguageArabic:  "مينفعش ترفض راكب مطلبش مشوارك",
+	},
+	"problem.acceptUnknown": {
+		DB.LanguageEnglish: "The passenger you're trying to accept did not request your carpool",
+		DB.LanguageArabic:  "الراكب اللي عايز تقبله مطلبش مشوارك",
+	},
+	"problem.acceptOther": {
+		DB.LanguageEnglish: "you can not accept a passenger that did not request your carpool",
+		DB.LanguageArabic:  "مينفعش تقبل راكب مطلبش مشوارك",
+	},
+	"problem.passengerCancelled": {
+		DB.LanguageEnglish: "this passenger has cancelled their request",
+		DB.LanguageArabic:  "الراكب ده لغى طلبه",
+	},
+	"problem.notPossible": {
+		DB.LanguageEnglish: "not a possible passenger",
+		DB.LanguageArabic:  "ده مش من الركاب اللي طلبوا المشوار",
+	},
+	"problem.noDeleted": {
+		DB.LanguageEnglish: "no deleted carpool with this id",
+		DB.LanguageArabic:  "مفيش مشوار ممسوح بالرقم ده",
+	},
+	"problem.notDeleted": {
+		DB.LanguageEnglish: "only a deleted carpool can be brought back",
+		DB.LanguageArabic:  "المشوار الممسوح بس هو اللي ينفع يرجع",
+	},
+	"problem.notOut": {
+		DB.LanguageEnglish: "the passenger is not out of the carpool anymore",
+		DB.LanguageArabic:  "الراكب مبقاش برة المشوار",
+	},
+	"problem.alreadyIn": {
+		DB.LanguageEnglish: "the passenger is already in the carpool",
+		DB.LanguageArabic:  "الراكب في المشوار أصلاً",
+	},
+	"problem.transition": {
+		DB.LanguageEnglish: "a carpool that is {{.From}} can not become {{.To}}",
+		DB.LanguageArabic:  "مشوار حالته {{.From}} مينفعش يبقى {{.To}}",
+	},
+	"problem.changed": {
+		DB.LanguageEnglish: "the carpool changed while updating it, please try again",
+		DB.LanguageArabic:  "المشوار اتغير وأنا بعدله، جرب تاني",
+	},
+	"problem.endBeforeStartRide": {
+		DB.LanguageEnglish: "you can only end a ride after starting it",
+		DB.LanguageArabic:  "مينفعش تنهي مشوار قبل ما تبدأه",
+	},
+	"problem.already": {
+		DB.LanguageEnglish: "this carpool is already {{.Status}}",
+		DB.LanguageArabic:  "المشوار ده حالته {{.Status}} خلاص",
+	},
+	"problem.checkInNotAccepted": {
+		DB.LanguageEnglish: "you can only check in to a carpool that accepted you",
+		DB.LanguageArabic:  "تقدر تسجل وصولك بس في مشوار قبلك",
+	},
+	"problem.checkedIn": {
+		DB.LanguageEnglish: "you already checked in",
+		DB.LanguageArabic:  "إنت سجلت وصولك خلاص",
+	},
+	"problem.stars": {
+		DB.LanguageEnglish: "ratings are from 1 to 5 stars",
+		DB.LanguageArabic:  "التقييم من نجمة لحد 5 نجوم",
+	},
+	"problem.rateOnce": {
+		DB.LanguageEnglish: "you can only rate someone you rode with in the last week, and only once per ride",
+		DB.LanguageArabic:  "تقدر تقيم بس حد ركبت معاه في آخر أسبوع، ومرة واحدة بس في كل مشوار",
+	},
+	"problem.startTime": {
+		DB.LanguageEnglish: "the carpool needs a start time",
+		DB.LanguageArabic:  "المشوار محتاج ميعاد يبدأ فيه",
+	},
+	"problem.weekdays": {
+		DB.LanguageEnglish: "I need the days of the week you drive on (ex. 'sun, tue and thu')",
+		DB.LanguageArabic:  "محتاج أيام الأسبوع اللي بتسوق فيها (مثلاً 'sun, tue and thu')",
+	},
+	"problem.date": {
+		DB.LanguageEnglish: "please write the day as year-month-day (ex. '{{.Example}}')",
+		DB.LanguageArabic:  "اكتب اليوم كده سنة-شهر-يوم (مثلاً '{{.Example}}')",
+	},
+	"problem.scheduleEnd": {
+		DB.LanguageEnglish: "the schedule has to end after its first carpool, on {{.Date}}",
+		DB.LanguageArabic:  "المشوار المتكرر لازم يخلص بعد أول مشوار فيه، يوم {{.Date}}",
+	},
+	"problem.scheduleLength": {
+		DB.LanguageEnglish: "a schedule can't be longer than a semester, please end it before {{.Date}}",
+		DB.LanguageArabic:  "المشوار المتكرر مينفعش يكون أطول من ترم، خليه يخلص قبل {{.Date}}",
+	},
+	"problem.driving": {
+		DB.LanguageEnglish: "you are already driving it",
+		DB.LanguageArabic:  "إنت اللي سايقه أصلاً",
+	},
+	"problem.standing": {
+		DB.LanguageEnglish: "{{.GUCID}} is already in it",
+		DB.LanguageArabic:  "{{.GUCID}} فيه أصلاً",
+	},
+	"problem.standingFull": {
+		DB.LanguageEnglish: "all its seats are already taken by standing passengers",
+		DB.LanguageArabic:  "كل كراسيه محجوزة لركاب ثابتين",
+	},
+	"problem.notStanding": {
+		DB.LanguageEnglish: "{{.GUCID}} is not a standing passenger of it",
+		DB.LanguageArabic:  "{{.GUCID}} مش راكب ثابت فيه",
+	},
+	"problem.clock": {
+		DB.LanguageEnglish: "\"{{.Time}}\" is not a time like 8:30",
+		DB.LanguageArabic:  "\"{{.Time}}\" مش ساعة زي 8:30",
+	},
+	"problem.slot": {
+		DB.LanguageEnglish: "there is no slot {{.Slot}}, the slots go from 1 to {{.Slots}}",
+		DB.LanguageArabic:  "مفيش سلوت {{.Slot}}، السلوتات من 1 لحد {{.Slots}}",
+	},
+	"problem.slotFormat": {
+		DB.LanguageEnglish: "\"{{.Slot}}\" is not a slot number or a time like 8:30-10:00",
+		DB.LanguageArabic:  "\"{{.Slot}}\" مش رقم سلوت ولا وقت زي 8:30-10:00",
+	},
+	"problem.classEnds": {
+		DB.LanguageEnglish: "the class at {{.Slot}} ends before it starts",
+		DB.LanguageArabic:  "المحاضرة اللي الساعة {{.Slot}} بتخلص قبل ما تبدأ",
+	},
+	"problem.timetableRow": {
+		DB.LanguageEnglish: "line {{.Line}}: expected the day, the slot and the course",
+		DB.LanguageArabic:  "سطر {{.Line}}: محتاج اليوم والسلوت والمادة",
+	},
+	"problem.timetableDay": {
+		DB.LanguageEnglish: "line {{.Line}}: \"{{.Day}}\" is not a day of the week",
+		DB.LanguageArabic:  "سطر {{.Line}}: \"{{.Day}}\" مش يوم من أيام الأسبوع",
+	},
+	"problem.line": {
+		DB.LanguageEnglish: "line {{.Line}}: {{.Error}}",
+		DB.LanguageArabic:  "سطر {{.Line}}: {{.Error}}",
+	},
+	"problem.noClasses": {
+		DB.LanguageEnglish: "the timetable has no classes",
+		DB.LanguageArabic:  "الجدول مفيهوش محاضرات",
+	},
+	"problem.closure": {
+		DB.LanguageEnglish: "the campus has to open again after it closes",
+		DB.LanguageArabic:  "الجامعة لازم تفتح تاني بعد ما تقفل",
+	},
+	"problem.noCode": {
+		DB.LanguageEnglish: "I didn't send you a code yet, or you already used it",
+		DB.LanguageArabic:  "لسه مبعتلكش كود، أو إنت استخدمته خلاص",
+	},
+	"problem.codeExpired": {
+		DB.LanguageEnglish: "this code has expired, please log in again to get a new one",
+		DB.LanguageArabic:  "الكود ده خلص، ادخل تاني عشان تاخد كود جديد",
+	},
+	"problem.codeAttempts": {
+		DB.LanguageEnglish: "too many wrong codes, please log in again to get a new one",
+		DB.LanguageArabic:  "أكواد غلط كتير، ادخل تاني عشان تاخد كود جديد",
+	},
+	"problem.wrongCode": {
+		DB.LanguageEnglish: "this code is not correct",
+		DB.LanguageArabic:  "الكود ده مش صح",
+	},
+	"problem.tooManyCodes": {
+		DB.LanguageEnglish: "I already sent you too many codes, please wait a bit before asking for a new one",
+		DB.LanguageArabic:  "بعتلك أكواد كتير، استنى شوية قبل ما تطلب كود جديد",
+	},
+	"problem.notStudent": {
+		DB.LanguageEnglish: "I can't find this GUC ID among the students of the GUC",
+		DB.LanguageArabic:  "مش لاقي رقم الجامعة ده في طلبة الجامعة",
+	},
+	"problem.inactive": {
+		DB.LanguageEnglish: "this GUC ID is no longer on the roster of the GUC",
+		DB.LanguageArabic:  "رقم الجامعة ده مبقاش في كشوف الجامعة",
+	},
+}
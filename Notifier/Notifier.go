@@ -0,0 +1,191 @@
+package Notifier
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// EmailDomain : the domain of the GUC student email addresses.
+const EmailDomain = "student.guc.edu.eg"
+
+// Names of the templates that can be sent to the students.
+const (
+	RequestAccepted   = "accepted"
+	RequestRejected   = "rejected"
+	CarpoolDeleted    = "deleted"
+	DepartureReminder = "reminder"
+	CampusClosed      = "closed"
+	CarpoolMatch      = "match"
+	RideRestored      = "restored"
+	RateRide          = "rate"
+)
+
+// Data : the values that get filled into a template.
+type Data map[string]interface{}
+
+// Mailer : anything that is able to deliver an email.
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+// SMTPMailer : a mailer that delivers emails through an SMTP server.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send : sends a plain text email through the SMTP server.
+func (m *SMTPMailer) Send(to string, subject string, body string) error {
+	msg := "From: " + m.From + "\r\n"
+	msg += "To: " + to + "\r\n"
+	msg += "Subject: " + subject + "\r\n"
+	msg += "Content-Type: text/plain; charset=UTF-8\r\n"
+	msg += "\r\n" + body + "\r\n"
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.From, []string{to}, []byte(msg))
+}
+
+// LogMailer : a local stand-in for the SMTP server that only writes the emails to the log.
+type LogMailer struct{}
+
+// Send : logs the email instead of sending it.
+func (LogMailer) Send(to string, subject string, body string) error {
+	log.Printf("[email] to: %s, subject: %s\n%s\n", to, subject, body)
+	return nil
+}
+
+// NewMailerFromEnv : creates an SMTP mailer from the SMTP_* environment variables, or a LogMailer if no server is configured.
+func NewMailerFromEnv() Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return LogMailer{}
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "carpool@guc.edu.eg"
+	}
+	return &SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USER"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}
+}
+
+// EmailAddress : derives the GUC email address of a student from the name in their profile (eg. "Ahmed Ali" -> ahmed.ali@student.guc.edu.eg).
+func EmailAddress(name string) string {
+	parts := strings.Fields(strings.ToLower(name))
+	return strings.Join(parts, ".") + "@" + EmailDomain
+}
+
+type emailTemplate struct {
+	subject string
+	body    *template.Template
+}
+
+var templates = map[string]emailTemplate{
+	RequestAccepted: {
+		subject: "Your carpool request was accepted",
+		body:    template.Must(template.New(RequestAccepted).Parse("Hello {{.Name}},\n\nGood news! {{.DriverName}} accepted your request to join carpool {{.PostID}}.\n{{if .Details}}Here are the details of your ride:\n{{.Details}}\n{{if .Vehicle}}Look for a {{.Vehicle}}.\n{{end}}{{end}}\nHave a nice ride!\nGUC Carpool")),
+	},
+	RequestRejected: {
+		subject: "Your carpool request was not accepted",
+		body:    template.Must(template.New(RequestRejected).Parse("Hello {{.Name}},\n\nI'm sorry, but your request to join carpool {{.PostID}} couldn't be made. You can choose another carpool from the chat.\n\nGUC Carpool")),
+	},
+	CarpoolDeleted: {
+		subject: "A carpool you requested was deleted",
+		body:    template.Must(template.New(CarpoolDeleted).Parse("Hello {{.Name}},\n\n{{.DriverName}} deleted carpool {{.PostID}}. {{if .LinkedPostID}}You are still in carpool {{.LinkedPostID}}, the other way of the round trip. Type 'cancel request' in the chat if you don't need it on its own.{{else}}You can choose another carpool from the chat.{{end}}\n\nGUC Carpool")),
+	},
+	DepartureReminder: {
+		subject: "Your carpool leaves soon",
+		body:    template.Must(template.New(DepartureReminder).Parse("Hello {{.Name}},\n\nThis is a reminder that carpool {{.PostID}}{{if .IsDriver}}, which you are driving,{{else}} with {{.DriverName}}{{end}} starts in {{.Minutes}} minutes, on {{.StartTime}}.\n\nGUC Carpool")),
+	},
+	CampusClosed: {
+		subject: "A carpool was cancelled, the GUC is closed",
+		body:    template.Must(template.New(CampusClosed).Parse("Hello {{.Name}},\n\nThe GUC is closed on {{.Date}} ({{.Reason}}), so carpool {{.PostID}} was cancelled.\n\nGUC Carpool")),
+	},
+	CarpoolMatch: {
+		subject: "A new carpool fits your timetable",
+		body:    template.Must(template.New(CarpoolMatch).Parse("Hello {{.Name}},\n\n{{.DriverName}} is driving carpool {{.PostID}} {{if .FromGUC}}from{{else}}to{{end}} the GUC on {{.StartTime}}, which fits your timetable. Type 'choose {{.PostID}}' in the chat to join it.\n\nGUC Carpool")),
+	},
+	RateRide: {
+		subject: "How was your ride?",
+		body:    template.Must(template.New(RateRide).Parse("Hello {{.Name}},\n\nYour ride in carpool {{.PostID}} with {{.DriverName}} is over. How was it? Rate {{.DriverName}} by typing 'rate {{.DriverID}} 1-5' in the chat, with a comment if you like. You can rate them for a week.\n\nGUC Carpool")),
+	},
+	RideRestored: {
+		subject: "Your carpool is back on",
+		body:    template.Must(template.New(RideRestored).Parse("Hello {{.Name}},\n\nPlease ignore my last email, {{.DriverName}} {{if .Deleted}}deleted carpool {{.PostID}}{{else}}turned down your request to join carpool {{.PostID}}{{end}} by mistake. {{if .Accepted}}You are in the carpool again.{{else}}Your request to join it is waiting for them again.{{end}}\n\nGUC Carpool")),
+	},
+}
+
+// Notifier : sends templated emails to students who did not opt out of them.
+type Notifier struct {
+	Mailer   Mailer
+	Address  func(GUCID string) (string, error) // nil derives the address from the name of the student
+	OptedOut func(GUCID string) (bool, error)
+}
+
+// New : creates a notifier that sends its emails through the given mailer, to the address it looks up for each student.
+func New(mailer Mailer, address func(GUCID string) (string, error), optedOut func(GUCID string) (bool, error)) *Notifier {
+	return &Notifier{
+		Mailer:   mailer,
+		Address:  address,
+		OptedOut: optedOut,
+	}
+}
+
+// AddressOf : returns where the emails of the student are sent.
+func (n *Notifier) AddressOf(GUCID string, name string) (string, error) {
+	if n.Address == nil {
+		return EmailAddress(name), nil
+	}
+	return n.Address(GUCID)
+}
+
+// Notify : fills the template with the data and emails it to the student, unless they opted out.
+func (n *Notifier) Notify(GUCID string, name string, templateName string, data Data) error {
+	tmpl, found := templates[templateName]
+	if !found {
+		return errors.New("no email template called " + templateName)
+	}
+	if n.OptedOut != nil {
+		optedOut, err := n.OptedOut(GUCID)
+		if err != nil {
+			return err
+		}
+		if optedOut {
+			return nil
+		}
+	}
+	to, err := n.AddressOf(GUCID, name)
+	if err != nil {
+		return err
+	}
+	values := Data{"Name": name, "GUCID": GUCID}
+	for key, val := range data {
+		values[key] = val
+	}
+	body := &bytes.Buffer{}
+	err = tmpl.body.Execute(body, values)
+	if err != nil {
+		return err
+	}
+	return n.Mailer.Send(to, tmpl.subject, body.String())
+}
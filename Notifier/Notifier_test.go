@@ -0,0 +1,159 @@
+package Notifier
+
+import (
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// fakeSMTPServer : a tiny in-process SMTP server that records the emails it receives.
+type fakeSMTPServer struct {
+	listener net.Listener
+	received chan string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeSMTPServer{listener: listener, received: make(chan string, 10)}
+	go server.serve()
+	return server
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost fake smtp")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			text.PrintfLine("250 localhost")
+		case strings.HasPrefix(command, "DATA"):
+			text.PrintfLine("354 go ahead")
+			lines, err := text.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.received <- strings.Join(lines, "\n")
+			text.PrintfLine("250 ok")
+		case strings.HasPrefix(command, "QUIT"):
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) mailer() *SMTPMailer {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return &SMTPMailer{Host: host, Port: port, From: "carpool@guc.edu.eg"}
+}
+
+// recordingMailer : a mailer that remembers what it was asked to send.
+type recordingMailer struct {
+	to      []string
+	subject []string
+	body    []string
+}
+
+func (m *recordingMailer) Send(to string, subject string, body string) error {
+	m.to = append(m.to, to)
+	m.subject = append(m.subject, subject)
+	m.body = append(m.body, body)
+	return nil
+}
+
+func TestEmailAddress(t *testing.T) {
+	if address := EmailAddress("Ahmed  Mohamed Ali"); address != "ahmed.mohamed.ali@student.guc.edu.eg" {
+		t.Error("wrong address " + address)
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	defer server.listener.Close()
+
+	notifier := New(server.mailer(), nil, nil)
+	err := notifier.Notify("34-1111", "Ahmed Ali", RequestAccepted, Data{"DriverName": "Omar", "PostID": 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := <-server.received
+	if !strings.Contains(email, "To: ahmed.ali@student.guc.edu.eg") {
+		t.Error("email was sent to the wrong address:\n" + email)
+	}
+	if !strings.Contains(email, "Subject: Your carpool request was accepted") {
+		t.Error("email has the wrong subject:\n" + email)
+	}
+	if !strings.Contains(email, "Omar accepted your request to join carpool 12") {
+		t.Error("template was not filled:\n" + email)
+	}
+}
+
+func TestNotifyOptedOut(t *testing.T) {
+	mailer := &recordingMailer{}
+	notifier := New(mailer, nil, func(GUCID string) (bool, error) {
+		return GUCID == "34-1111", nil
+	})
+	notifier.Notify("34-1111", "Ahmed Ali", CarpoolDeleted, Data{"DriverName": "Omar", "PostID": 3})
+	notifier.Notify("34-2222", "Mona Adel", CarpoolDeleted, Data{"DriverName": "Omar", "PostID": 3})
+	if len(mailer.to) != 1 || mailer.to[0] != "mona.adel@student.guc.edu.eg" {
+		t.Error("opted out student got an email", mailer.to)
+	}
+}
+
+// The email goes to the address looked up for the student, not the one derived from their name.
+func TestNotifyLooksUpAddress(t *testing.T) {
+	mailer := &recordingMailer{}
+	notifier := New(mailer, func(GUCID string) (string, error) {
+		if GUCID == "34-1111" {
+			return "ahmed.ali1@student.guc.edu.eg", nil
+		}
+		return "", errors.New("not on the roster")
+	}, nil)
+	notifier.Notify("34-1111", "Ahmed Ali", CarpoolDeleted, Data{"DriverName": "Omar", "PostID": 3})
+	if err := notifier.Notify("34-2222", "Mona Adel", CarpoolDeleted, Data{"DriverName": "Omar", "PostID": 3}); err == nil {
+		t.Error("emailed a student without an address")
+	}
+	if len(mailer.to) != 1 || mailer.to[0] != "ahmed.ali1@student.guc.edu.eg" {
+		t.Error("wrong addresses", mailer.to)
+	}
+}
+
+// A passenger of both ways of a round trip is told they are still in the other one.
+func TestCarpoolDeletedRoundTrip(t *testing.T) {
+	mailer := &recordingMailer{}
+	notifier := New(mailer, nil, nil)
+	notifier.Notify("34-1111", "Ahmed Ali", CarpoolDeleted, Data{"DriverName": "Omar", "PostID": 3, "LinkedPostID": 4})
+	notifier.Notify("34-2222", "Mona Adel", CarpoolDeleted, Data{"DriverName": "Omar", "PostID": 3})
+	if !strings.Contains(mailer.body[0], "still in carpool 4") || strings.Contains(mailer.body[1], "still in") || !strings.Contains(mailer.body[1], "choose another carpool") {
+		t.Error("wrong emails", mailer.body)
+	}
+}
+
+func TestNotifyUnknownTemplate(t *testing.T) {
+	notifier := New(&recordingMailer{}, nil, nil)
+	if err := notifier.Notify("34-1111", "Ahmed Ali", "party", nil); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+}
@@ -0,0 +1,298 @@
+package OIDC
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Roster"
+)
+
+// Config : where the identity provider is and how this server is registered with it.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // the /callback route of this server
+	IDClaim      string // the claim holding the GUC ID
+}
+
+// ConfigFromEnv : reads the configuration from OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET, OIDC_REDIRECT_URL and OIDC_ID_CLAIM (default "guc_id"). Returns false if single sign-on is not set up.
+func ConfigFromEnv() (Config, bool) {
+	config := Config{
+		Issuer:       os.Getenv("OIDC_ISSUER"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		IDClaim:      os.Getenv("OIDC_ID_CLAIM"),
+	}
+	if config.IDClaim == "" {
+		config.IDClaim = "guc_id"
+	}
+	return config, config.Issuer != "" && config.ClientID != ""
+}
+
+// Identity : the student the identity provider logged in.
+type Identity struct {
+	GUCID string
+	Name  string
+}
+
+// Provider : an identity provider found through its discovery document.
+type Provider struct {
+	Config                Config
+	AuthorizationEndpoint string
+	TokenEndpoint         string
+	JWKSURI               string
+	Client                *http.Client
+
+	mutex sync.Mutex
+	keys  map[string]*rsa.PublicKey
+}
+
+// Discover : reads the discovery document of the issuer.
+func Discover(config Config) (*Provider, error) {
+	provider := &Provider{Config: config, Client: &http.Client{Timeout: 10 * time.Second}}
+	var document struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	err := provider.getJSON(strings.TrimSuffix(config.Issuer, "/")+"/.well-known/openid-configuration", &document)
+	if err != nil {
+		return nil, err
+	}
+	if document.Issuer != config.Issuer {
+		return nil, errors.New("the discovery document is for issuer " + document.Issuer + ", not " + config.Issuer)
+	}
+	provider.AuthorizationEndpoint = document.AuthorizationEndpoint
+	provider.TokenEndpoint = document.TokenEndpoint
+	provider.JWKSURI = document.JWKSURI
+	return provider, nil
+}
+
+// NewState : creates a random value to use as the state or the nonce of a login.
+func NewState() (string, error) {
+	random := make([]byte, 16)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
+
+// AuthURL : the address to send the user to, to log in at the identity provider.
+func (p *Provider) AuthURL(state string, nonce string) string {
+	values := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.Config.ClientID},
+		"redirect_uri":  {p.Config.RedirectURL},
+		"scope":         {"openid profile email"},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+	separator := "?"
+	if strings.Contains(p.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return p.AuthorizationEndpoint + separator + values.Encode()
+}
+
+// Exchange : trades the code the identity provider sent back for the identity of the user, checking the identity token on the way.
+func (p *Provider) Exchange(code string, nonce string) (Identity, error) {
+	res, err := p.Client.PostForm(p.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.Config.RedirectURL},
+		"client_id":     {p.Config.ClientID},
+		"client_secret": {p.Config.ClientSecret},
+	})
+	if err != nil {
+		return Identity{}, err
+	}
+	defer res.Body.Close()
+	var token struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&token)
+	if err != nil {
+		return Identity{}, err
+	}
+	if res.StatusCode != http.StatusOK || token.IDToken == "" {
+		return Identity{}, errors.New("the identity provider refused the code: " + token.Error)
+	}
+	claims, err := p.Verify(token.IDToken, nonce)
+	if err != nil {
+		return Identity{}, err
+	}
+	return p.identityFrom(claims)
+}
+
+// Verify : checks the signature, issuer, audience, expiry and nonce of an identity token, and returns its claims.
+func (p *Provider) Verify(rawToken string, nonce string) (map[string]interface{}, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("the identity token is malformed")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return nil, err
+	}
+	if header.Alg != "RS256" {
+		return nil, errors.New("the identity token is signed with " + header.Alg + ", only RS256 is accepted")
+	}
+	key, err := p.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	if err != nil {
+		return nil, errors.New("the identity token has a bad signature")
+	}
+
+	claims := map[string]interface{}{}
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return nil, err
+	}
+	if claims["iss"] != p.Config.Issuer {
+		return nil, errors.New("the identity token is from another issuer")
+	}
+	if !hasAudience(claims["aud"], p.Config.ClientID) {
+		return nil, errors.New("the identity token is not meant for this server")
+	}
+	expiry, ok := claims["exp"].(float64)
+	if !ok || time.Now().After(time.Unix(int64(expiry), 0)) {
+		return nil, errors.New("the identity token has expired")
+	}
+	if claims["nonce"] != nonce {
+		return nil, errors.New("the identity token is not for this login")
+	}
+	return claims, nil
+}
+
+// Function that maps the claims of the identity token to a student. The GUC ID is read from the configured claim, or from a GUC student email address.
+func (p *Provider) identityFrom(claims map[string]interface{}) (Identity, error) {
+	gucID, _ := claims[p.Config.IDClaim].(string)
+	if gucID == "" {
+		email, _ := claims["email"].(string)
+		if strings.HasSuffix(strings.ToLower(email), "@"+Notifier.EmailDomain) {
+			gucID = email[:strings.LastIndex(email, "@")]
+		}
+	}
+	if !Roster.ValidGUCID(gucID) {
+		return Identity{}, errors.New("your account does not have a GUC ID")
+	}
+	name, _ := claims["name"].(string)
+	if name == "" {
+		given, _ := claims["given_name"].(string)
+		family, _ := claims["family_name"].(string)
+		name = strings.TrimSpace(given + " " + family)
+	}
+	if name == "" {
+		return Identity{}, errors.New("your account does not have a name")
+	}
+	return Identity{GUCID: gucID, Name: name}, nil
+}
+
+// Function that returns the signing key with the given ID, reloading the keys of the identity provider if it is not known (eg. after a key rotation).
+func (p *Provider) key(kid string) (*rsa.PublicKey, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if key, found := p.keys[kid]; found {
+		return key, nil
+	}
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	err := p.getJSON(p.JWKSURI, &set)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = map[string]*rsa.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+		p.keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	key, found := p.keys[kid]
+	if !found {
+		return nil, errors.New("the identity token is signed with an unknown key")
+	}
+	return key, nil
+}
+
+// Function that GETs a JSON document from the identity provider.
+func (p *Provider) getJSON(address string, result interface{}) error {
+	res, err := p.Client.Get(address)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return errors.New("the identity provider replied " + res.Status + " to " + address)
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// Function that decodes a base64 part of a token.
+func decodeSegment(segment string, result interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.New("the identity token is malformed")
+	}
+	return json.Unmarshal(data, result)
+}
+
+// Function that checks if the audience claim, a string or a list, includes the client.
+func hasAudience(audience interface{}, clientID string) bool {
+	switch audience := audience.(type) {
+	case string:
+		return audience == clientID
+	case []interface{}:
+		for _, val := range audience {
+			if val == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package OIDC
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Function that starts a stand-in provider and discovers it.
+func setup(t *testing.T, claims map[string]interface{}) (*FakeProvider, *Provider) {
+	fake := NewFakeProvider("carpool", "secret", claims)
+	provider, err := Discover(fake.Config("http://carpool.test/callback"))
+	if err != nil {
+		fake.Close()
+		t.Fatal(err)
+	}
+	return fake, provider
+}
+
+// Function that goes through the login page of the provider and returns the code it sends back.
+func authorize(t *testing.T, provider *Provider, state string, nonce string) string {
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := client.Get(provider.AuthURL(state, nonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	location, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(location.String(), "http://carpool.test/callback") || location.Query().Get("state") != state {
+		t.Fatal("provider did not send the user back with the state", location)
+	}
+	return location.Query().Get("code")
+}
+
+func TestLogin(t *testing.T) {
+	fake, provider := setup(t, map[string]interface{}{"guc_id": "34-1234", "name": "Ahmed Ali"})
+	defer fake.Close()
+
+	code := authorize(t, provider, "state", "nonce")
+	identity, err := provider.Exchange(code, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.GUCID != "34-1234" || identity.Name != "Ahmed Ali" {
+		t.Error("wrong identity", identity)
+	}
+	if _, err = provider.Exchange(code, "nonce"); err == nil {
+		t.Error("a code could be used twice")
+	}
+}
+
+func TestGUCIDFromEmail(t *testing.T) {
+	fake, provider := setup(t, map[string]interface{}{"email": "34-1234@student.guc.edu.eg", "given_name": "Ahmed", "family_name": "Ali"})
+	defer fake.Close()
+
+	identity, err := provider.Exchange(authorize(t, provider, "state", "nonce"), "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.GUCID != "34-1234" || identity.Name != "Ahmed Ali" {
+		t.Error("wrong identity", identity)
+	}
+}
+
+func TestAccountWithoutGUCID(t *testing.T) {
+	fake, provider := setup(t, map[string]interface{}{"email": "ahmed@example.com", "name": "Ahmed"})
+	defer fake.Close()
+
+	if _, err := provider.Exchange(authorize(t, provider, "state", "nonce"), "nonce"); err == nil {
+		t.Error("logged in an account without a GUC ID")
+	}
+}
+
+func TestBadTokens(t *testing.T) {
+	fake, provider := setup(t, map[string]interface{}{"guc_id": "34-1234", "name": "Ahmed"})
+	defer fake.Close()
+
+	if _, err := provider.Verify(fake.Sign("nonce", nil), "nonce"); err != nil {
+		t.Fatal("good token was refused", err)
+	}
+	cases := map[string]string{
+		"other nonce":    fake.Sign("other", nil),
+		"other audience": fake.Sign("nonce", map[string]interface{}{"aud": "someone-else"}),
+		"other issuer":   fake.Sign("nonce", map[string]interface{}{"iss": "https://evil.test"}),
+		"expired":        fake.Sign("nonce", map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()}),
+		"malformed":      "not.a-token",
+	}
+	// A token signed by another key.
+	other := NewFakeProvider("carpool", "secret", fake.Claims)
+	defer other.Close()
+	cases["forged"] = other.Sign("nonce", map[string]interface{}{"iss": fake.Server.URL})
+	// A token whose claims were changed after signing.
+	parts := strings.Split(fake.Sign("nonce", nil), ".")
+	parts[1] = strings.Split(fake.Sign("nonce", map[string]interface{}{"guc_id": "34-9999"}), ".")[1]
+	cases["tampered"] = strings.Join(parts, ".")
+
+	for name, token := range cases {
+		if _, err := provider.Verify(token, "nonce"); err == nil {
+			t.Error("accepted a token with " + name)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package OIDC
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// FakeProvider : a stand-in identity provider for tests. It logs in whoever asks as the user described by Claims.
+type FakeProvider struct {
+	Server       *httptest.Server
+	Key          *rsa.PrivateKey
+	ClientID     string
+	ClientSecret string
+	Claims       map[string]interface{}
+
+	mutex sync.Mutex
+	codes map[string]string // code -> nonce
+}
+
+// NewFakeProvider : starts a stand-in identity provider for the given client.
+func NewFakeProvider(clientID string, clientSecret string, claims map[string]interface{}) *FakeProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	fake := &FakeProvider{Key: key, ClientID: clientID, ClientSecret: clientSecret, Claims: claims, codes: map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", fake.discovery)
+	mux.HandleFunc("/jwks", fake.jwks)
+	mux.HandleFunc("/authorize", fake.authorize)
+	mux.HandleFunc("/token", fake.token)
+	fake.Server = httptest.NewServer(mux)
+	return fake
+}
+
+// Config : the configuration of a client registered with the provider.
+func (f *FakeProvider) Config(redirectURL string) Config {
+	return Config{Issuer: f.Server.URL, ClientID: f.ClientID, ClientSecret: f.ClientSecret, RedirectURL: redirectURL, IDClaim: "guc_id"}
+}
+
+// Close : stops the provider.
+func (f *FakeProvider) Close() {
+	f.Server.Close()
+}
+
+// Sign : signs an identity token with the claims of the user, the standard claims and the extra ones given.
+func (f *FakeProvider) Sign(nonce string, extra map[string]interface{}) string {
+	claims := map[string]interface{}{
+		"iss":   f.Server.URL,
+		"aud":   f.ClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": nonce,
+	}
+	for key, val := range f.Claims {
+		claims[key] = val
+	}
+	for key, val := range extra {
+		claims[key] = val
+	}
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, f.Key, crypto.SHA256, digest[:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (f *FakeProvider) discovery(res http.ResponseWriter, req *http.Request) {
+	json.NewEncoder(res).Encode(map[string]string{
+		"issuer":                 f.Server.URL,
+		"authorization_endpoint": f.Server.URL + "/authorize",
+		"token_endpoint":         f.Server.URL + "/token",
+		"jwks_uri":               f.Server.URL + "/jwks",
+	})
+}
+
+func (f *FakeProvider) jwks(res http.ResponseWriter, req *http.Request) {
+	json.NewEncoder(res).Encode(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(f.Key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.Key.E)).Bytes()),
+		}},
+	})
+}
+
+// Function that logs the user in straight away and sends them back to the client with a code.
+func (f *FakeProvider) authorize(res http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	if query.Get("client_id") != f.ClientID {
+		http.Error(res, "unknown client", http.StatusBadRequest)
+		return
+	}
+	code, _ := NewState()
+	f.mutex.Lock()
+	f.codes[code] = query.Get("nonce")
+	f.mutex.Unlock()
+	http.Redirect(res, req, query.Get("redirect_uri")+"?"+url.Values{"code": {code}, "state": {query.Get("state")}}.Encode(), http.StatusFound)
+}
+
+// Function that trades a code for an identity token. Each code can only be used once.
+func (f *FakeProvider) token(res http.ResponseWriter, req *http.Request) {
+	req.ParseForm()
+	f.mutex.Lock()
+	nonce, found := f.codes[req.Form.Get("code")]
+	delete(f.codes, req.Form.Get("code"))
+	f.mutex.Unlock()
+	if !found || req.Form.Get("client_id") != f.ClientID || req.Form.Get("client_secret") != f.ClientSecret {
+		res.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(res).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+	json.NewEncoder(res).Encode(map[string]string{"id_token": f.Sign(nonce, nil), "token_type": "Bearer"})
+}
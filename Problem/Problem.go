@@ -0,0 +1,21 @@
+package Problem
+
+// Error : something the student wrote or asked for that can't be done, with the message that explains it to them in their language.
+type Error struct {
+	// Key : the key of the message in the catalog of the Messages package.
+	Key string
+	// Params : the values the message is filled with.
+	Params map[string]interface{}
+	// Text : the explanation in English, for the logs and the tools that don't speak to a student.
+	Text string
+}
+
+// New : creates the error that the message with the key and params explains, and that reads as the text in English.
+func New(key string, params map[string]interface{}, text string) *Error {
+	return &Error{Key: key, Params: params, Text: text}
+}
+
+// Error : the explanation in English.
+func (e *Error) Error() string {
+	return e.Text
+}
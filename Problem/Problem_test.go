@@ -0,0 +1,26 @@
+package Problem
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	err := error(New("problem.noDay", map[string]interface{}{"Day": "31/2"}, "there is no day 31/2"))
+	if err.Error() != "there is no day 31/2" {
+		t.Errorf("wrong text %q", err.Error())
+	}
+
+	// The message can still be found when the error was wrapped on the way up.
+	var problem *Error
+	if !errors.As(fmt.Errorf("reading the time: %w", err), &problem) {
+		t.Fatal("the wrapped problem was not found")
+	}
+	if problem.Key != "problem.noDay" || problem.Params["Day"] != "31/2" {
+		t.Errorf("wrong problem %+v", problem)
+	}
+	if errors.As(errors.New("the database is down"), &problem) {
+		t.Error("another error was taken for a problem")
+	}
+}
@@ -0,0 +1,401 @@
+package Recurring
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+// Store : keeps the weekly schedules, and the carpools created from them.
+type Store interface {
+	Insert(schedule *DB.Schedule) error
+	Save(schedule *DB.Schedule) error
+	Get(ID uint64) (DB.Schedule, bool, error)
+	ByDriver(GUCID string) ([]DB.Schedule, error)
+	Active(now time.Time) ([]DB.Schedule, error)
+	Occurrences(ScheduleID uint64, from time.Time, to time.Time) ([]DB.CarpoolRequest, error)
+	AddOccurrence(carpool *DB.CarpoolRequest) error
+	RemovePassenger(PostID uint64, GUCID string) error
+}
+
+// DBStore : the store that keeps the schedules in the database.
+type DBStore struct{}
+
+// Insert : inserts the schedule in the database.
+func (DBStore) Insert(schedule *DB.Schedule) error { return DB.InsertSchedule(schedule) }
+
+// Save : saves the schedule in the database.
+func (DBStore) Save(schedule *DB.Schedule) error { return DB.SaveSchedule(schedule) }
+
+// Get : loads the schedule from the database.
+func (DBStore) Get(ID uint64) (DB.Schedule, bool, error) { return DB.GetSchedule(ID) }
+
+// ByDriver : loads the schedules of a driver from the database.
+func (DBStore) ByDriver(GUCID string) ([]DB.Schedule, error) { return DB.GetSchedulesByGUCID(GUCID) }
+
+// Active : loads the schedules that still create carpools from the database.
+func (DBStore) Active(now time.Time) ([]DB.Schedule, error) { return DB.GetActiveSchedules(now) }
+
+// Occurrences : loads the carpools created from the schedule from the database.
+func (DBStore) Occurrences(ScheduleID uint64, from time.Time, to time.Time) ([]DB.CarpoolRequest, error) {
+	return DB.GetScheduleOccurrences(ScheduleID, from, to)
+}
+
+// AddOccurrence : inserts the carpool in the database.
+func (DBStore) AddOccurrence(carpool *DB.CarpoolRequest) error { return DB.InsertOccurrence(carpool) }
+
+// RemovePassenger : takes the passenger out of a carpool created from a schedule in the database.
+func (DBStore) RemovePassenger(PostID uint64, GUCID string) error {
+	return DB.RemoveOccurrencePassenger(PostID, GUCID)
+}
+
+// MemoryStore : a store that keeps the schedules and their carpools in memory, for tests and running locally.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	schedules map[uint64]DB.Schedule
+	carpools  []DB.CarpoolRequest
+	lastID    uint64
+}
+
+// NewMemoryStore : creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{schedules: map[uint64]DB.Schedule{}}
+}
+
+// Insert : saves the schedule in memory, giving it the next ID.
+func (m *MemoryStore) Insert(schedule *DB.Schedule) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastID++
+	schedule.ID = m.lastID
+	m.schedules[schedule.ID] = *schedule
+	return nil
+}
+
+// Save : saves the changes to the schedule in memory.
+func (m *MemoryStore) Save(schedule *DB.Schedule) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, found := m.schedules[schedule.ID]; !found {
+		return errors.New("no schedule with this id")
+	}
+	m.schedules[schedule.ID] = *schedule
+	return nil
+}
+
+// Get : returns the schedule from memory.
+func (m *MemoryStore) Get(ID uint64) (DB.Schedule, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	schedule, found := m.schedules[ID]
+	return schedule, found, nil
+}
+
+// ByDriver : returns the schedules of a driver from memory.
+func (m *MemoryStore) ByDriver(GUCID string) ([]DB.Schedule, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	schedules := []DB.Schedule{}
+	for _, schedule := range m.schedules {
+		if schedule.GUCID == GUCID {
+			schedules = append(schedules, schedule)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID < schedules[j].ID })
+	return schedules, nil
+}
+
+// Active : returns the schedules that still create carpools from memory.
+func (m *MemoryStore) Active(now time.Time) ([]DB.Schedule, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	schedules := []DB.Schedule{}
+	for _, schedule := range m.schedules {
+		if !schedule.Paused && !schedule.EndDate.Before(now.AddDate(0, 0, -1)) {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+// Occurrences : returns the carpools created from the schedule from memory.
+func (m *MemoryStore) Occurrences(ScheduleID uint64, from time.Time, to time.Time) ([]DB.CarpoolRequest, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	carpools := []DB.CarpoolRequest{}
+	for _, carpool := range m.carpools {
+		if carpool.ScheduleID == ScheduleID && !carpool.StartTime.Before(from) && carpool.StartTime.Before(to) {
+			carpools = append(carpools, carpool)
+		}
+	}
+	return carpools, nil
+}
+
+// AddOccurrence : saves the carpool in memory, giving it the next post ID.
+func (m *MemoryStore) AddOccurrence(carpool *DB.CarpoolRequest) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	carpool.PostID = uint64(len(m.carpools) + 1)
+	m.carpools = append(m.carpools, *carpool)
+	return nil
+}
+
+// RemovePassenger : takes the passenger out of a carpool in memory, giving back their seat if they were accepted.
+func (m *MemoryStore) RemovePassenger(PostID uint64, GUCID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for i := range m.carpools {
+		if m.carpools[i].PostID == PostID {
+			m.carpools[i] = DB.WithoutPassenger(m.carpools[i], GUCID)
+		}
+	}
+	return nil
+}
+
+// MaxLength : the longest a schedule can run, about a semester.
+const MaxLength = 20 * 7 * 24 * time.Hour
+
+// dateFormat : how a day is written in the commands and in Skipped.
+const dateFormat = "2006-01-02"
+
+// dayNames : the days of the week, as the students may write them.
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var dayWord = regexp.MustCompile(`[a-z]+`)
+
+// ParseDays : reads the days of the week in a text like "sun, tue and thu".
+func ParseDays(text string) ([]time.Weekday, error) {
+	found := map[time.Weekday]bool{}
+	for _, word := range dayWord.FindAllString(strings.ToLower(text), -1) {
+		if day, isDay := dayNames[word]; isDay {
+			found[day] = true
+		}
+	}
+	if len(found) == 0 {
+		return nil, Problem.New("problem.weekdays", nil, "I need the days of the week you drive on (ex. 'sun, tue and thu')")
+	}
+	days := []time.Weekday{}
+	for day := range found {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+	return days, nil
+}
+
+// ParseDate : reads a day written as "2006-01-02", at the GUC.
+func ParseDate(text string) (time.Time, error) {
+	date, err := time.ParseInLocation(dateFormat, strings.TrimSpace(text), Timezone.Location)
+	if err != nil {
+		example := Timezone.Now().Format(dateFormat)
+		return time.Time{}, Problem.New("problem.date", map[string]interface{}{"Example": example}, "please write the day as year-month-day (ex. '"+example+"')")
+	}
+	return date, nil
+}
+
+// FromCarpool : makes a schedule that repeats a carpool on the days of the week until the end date. The accepted passengers of the carpool become its standing passengers.
+func FromCarpool(carpool DB.CarpoolRequest, days []time.Weekday, endDate time.Time) (DB.Schedule, error) {
+	// The carpool itself is the first ride, so the schedule starts the day after it.
+	startDate := dateOf(carpool.StartTime).AddDate(0, 0, 1)
+	endDate = dateOf(endDate)
+	if endDate.Before(startDate) {
+		first := dateOf(carpool.StartTime).Format(dateFormat)
+		return DB.Schedule{}, Problem.New("problem.scheduleEnd", map[string]interface{}{"Date": first}, "the schedule has to end after its first carpool, on "+first)
+	}
+	if endDate.Sub(startDate) > MaxLength {
+		last := startDate.Add(MaxLength).Format(dateFormat)
+		return DB.Schedule{}, Problem.New("problem.scheduleLength", map[string]interface{}{"Date": last}, "a schedule can't be longer than a semester, please end it before "+last)
+	}
+	return DB.Schedule{
+		GUCID:              carpool.GUCID,
+		Name:               carpool.Name,
+		Days:               days,
+		Hour:               carpool.StartTime.In(Timezone.Location).Hour(),
+		Minute:             carpool.StartTime.In(Timezone.Location).Minute(),
+		Shift:              carpool.Shift,
+		FromGUC:            carpool.FromGUC,
+		Latitude:           carpool.Latitude,
+		Longitude:          carpool.Longitude,
+		Seats:              carpool.AvailableSeats + len(carpool.CurrentPassengers),
+		Vehicle:            carpool.Vehicle,
+		StartDate:          startDate,
+		EndDate:            endDate,
+		Skipped:            []string{},
+		StandingPassengers: append([]string{}, carpool.CurrentPassengers...),
+	}, nil
+}
+
+// Occurrences : the start times of the carpools of the schedule between the two times. A paused schedule has none.
+func Occurrences(schedule DB.Schedule, from time.Time, to time.Time) []time.Time {
+	times := []time.Time{}
+	if schedule.Paused {
+		return times
+	}
+	onDay := map[time.Weekday]bool{}
+	for _, day := range schedule.Days {
+		onDay[day] = true
+	}
+	skipped := map[string]bool{}
+	for _, day := range schedule.Skipped {
+		skipped[day] = true
+	}
+	// Midnight is skipped on the day daylight saving time starts, so every day is taken back to its start.
+	for date := dateOf(schedule.StartDate); !date.After(schedule.EndDate); date = dateOf(date.AddDate(0, 0, 1)) {
+		startTime := time.Date(date.Year(), date.Month(), date.Day(), schedule.Hour, schedule.Minute, 0, 0, date.Location())
+		if !onDay[date.Weekday()] || skipped[date.Format(dateFormat)] || startTime.Before(from) || !startTime.Before(to) {
+			continue
+		}
+		times = append(times, startTime)
+	}
+	return times
+}
+
+// Materialize : creates the carpools of the schedule that start in the next ahead of now, and were not created yet. Days without classes on the academic calendar are left out. Returns the new carpools.
+func Materialize(store Store, year Calendar.Year, schedule DB.Schedule, now time.Time, ahead time.Duration) ([]DB.CarpoolRequest, error) {
+	created := []DB.CarpoolRequest{}
+	for _, startTime := range Occurrences(schedule, now, now.Add(ahead)) {
+		if _, noClasses := year.NoClasses(startTime); noClasses {
+			continue
+		}
+		existing, err := store.Occurrences(schedule.ID, startTime, startTime.Add(time.Minute))
+		if err != nil {
+			return created, err
+		}
+		if len(existing) > 0 {
+			continue
+		}
+		carpool := occurrence(schedule, startTime, now)
+		err = store.AddOccurrence(&carpool)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, carpool)
+	}
+	return created, nil
+}
+
+// Function that makes the carpool of the schedule that starts at the time. The standing passengers are asking to join it, so it stays theirs to accept or cancel.
+func occurrence(schedule DB.Schedule, startTime time.Time, now time.Time) DB.CarpoolRequest {
+	standing := append([]string{}, schedule.StandingPassengers...)
+	if len(standing) > schedule.Seats {
+		standing = standing[:schedule.Seats]
+	}
+	return DB.CarpoolRequest{
+		GUCID:              schedule.GUCID,
+		Name:               schedule.Name,
+		Longitude:          schedule.Longitude,
+		Latitude:           schedule.Latitude,
+		Time:               now,
+		StartTime:          startTime.UTC(),
+		CurrentPassengers:  []string{},
+		PossiblePassengers: standing,
+		FromGUC:            schedule.FromGUC,
+		AvailableSeats:     schedule.Seats,
+		Status:             DB.StatusOpen,
+		CheckedIn:          []string{},
+		Vehicle:            schedule.Vehicle,
+		ScheduleID:         schedule.ID,
+		Shift:              schedule.Shift,
+	}
+}
+
+// Skip : leaves the day out of the schedule.
+func Skip(schedule *DB.Schedule, date time.Time) {
+	day := date.In(Timezone.Location).Format(dateFormat)
+	for _, skipped := range schedule.Skipped {
+		if skipped == day {
+			return
+		}
+	}
+	schedule.Skipped = append(schedule.Skipped, day)
+}
+
+// Unskip : brings the day back into the schedule.
+func Unskip(schedule *DB.Schedule, date time.Time) {
+	day := date.In(Timezone.Location).Format(dateFormat)
+	for i, skipped := range schedule.Skipped {
+		if skipped == day {
+			schedule.Skipped = append(schedule.Skipped[:i], schedule.Skipped[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddStandingPassenger : asks for a seat for the passenger in every carpool of the schedule from now on.
+func AddStandingPassenger(schedule *DB.Schedule, GUCID string) error {
+	if GUCID == schedule.GUCID {
+		return Problem.New("problem.driving", nil, "you are already driving it")
+	}
+	for _, passenger := range schedule.StandingPassengers {
+		if passenger == GUCID {
+			return Problem.New("problem.standing", map[string]interface{}{"GUCID": GUCID}, GUCID+" is already in it")
+		}
+	}
+	if len(schedule.StandingPassengers) >= schedule.Seats {
+		return Problem.New("problem.standingFull", nil, "all its seats are already taken by standing passengers")
+	}
+	schedule.StandingPassengers = append(schedule.StandingPassengers, GUCID)
+	return nil
+}
+
+// RemoveStandingPassenger : stops adding a passenger to the carpools of the schedule.
+func RemoveStandingPassenger(schedule *DB.Schedule, GUCID string) error {
+	for i, passenger := range schedule.StandingPassengers {
+		if passenger == GUCID {
+			schedule.StandingPassengers = append(schedule.StandingPassengers[:i], schedule.StandingPassengers[i+1:]...)
+			return nil
+		}
+	}
+	return Problem.New("problem.notStanding", map[string]interface{}{"GUCID": GUCID}, GUCID+" is not a standing passenger of it")
+}
+
+// ScheduleToString : describes the schedule.
+func ScheduleToString(schedule DB.Schedule) string {
+	days := []string{}
+	for _, day := range schedule.Days {
+		days = append(days, day.String()[:3])
+	}
+	direction := "Going to the GUC"
+	if schedule.FromGUC {
+		direction = "Leaving the GUC"
+	}
+	str := "->\n\tSchedule: " + strconv.FormatUint(schedule.ID, 10)
+	str += ",\n\t" + direction + " every " + strings.Join(days, ", ") + " at " + time.Date(2000, 1, 1, schedule.Hour, schedule.Minute, 0, 0, time.UTC).Format("3:04pm")
+	if schedule.Shift > 0 {
+		str += ", give or take " + strconv.Itoa(schedule.Shift) + " minutes"
+	}
+	str += ",\n\tFrom " + dateOf(schedule.StartDate).Format(dateFormat) + " until " + dateOf(schedule.EndDate).Format(dateFormat)
+	str += ",\n\tSeats: " + strconv.Itoa(schedule.Seats)
+	if len(schedule.StandingPassengers) > 0 {
+		str += ",\n\tStanding Passengers: " + strings.Join(schedule.StandingPassengers, ", ")
+	}
+	if len(schedule.Skipped) > 0 {
+		str += ",\n\tSkipped: " + strings.Join(schedule.Skipped, ", ")
+	}
+	if schedule.Paused {
+		str += ",\n\tPaused"
+	}
+	return str + "\n\n"
+}
+
+// Function that returns the start of the day of the time at the GUC.
+func dateOf(t time.Time) time.Time {
+	t = t.In(Timezone.Location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
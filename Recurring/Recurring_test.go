@@ -0,0 +1,185 @@
+package Recurring
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+func TestParseDays(t *testing.T) {
+	days, err := ParseDays("every Sunday, tue and THU")
+	if err != nil || !reflect.DeepEqual(days, []time.Weekday{time.Sunday, time.Tuesday, time.Thursday}) {
+		t.Error("wrong days", days, err)
+	}
+	if _, err = ParseDays("every day"); err == nil {
+		t.Error("expected an error without days")
+	}
+}
+
+// A carpool on Sunday Nov 1 2026 at 8:30 give or take 15 minutes, with one accepted passenger and two seats left.
+func sundayCarpool() DB.CarpoolRequest {
+	return DB.CarpoolRequest{
+		PostID:            12,
+		GUCID:             "34-1234",
+		Name:              "Ahmed",
+		StartTime:         time.Date(2026, 11, 1, 8, 30, 0, 0, Timezone.Location),
+		CurrentPassengers: []string{"34-1"},
+		AvailableSeats:    2,
+		Vehicle:           DB.Vehicle{Plate: "ABC 123", Capacity: 3},
+		Shift:             15,
+	}
+}
+
+func TestFromCarpool(t *testing.T) {
+	days := []time.Weekday{time.Sunday, time.Tuesday}
+	schedule, err := FromCarpool(sundayCarpool(), days, time.Date(2026, 12, 31, 0, 0, 0, 0, Timezone.Location))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Hour != 8 || schedule.Minute != 30 || schedule.Shift != 15 || schedule.Seats != 3 || !reflect.DeepEqual(schedule.StandingPassengers, []string{"34-1"}) || schedule.Vehicle.Plate != "ABC 123" {
+		t.Error("wrong schedule", schedule)
+	}
+	if !schedule.StartDate.Equal(time.Date(2026, 11, 2, 0, 0, 0, 0, Timezone.Location)) {
+		t.Error("the schedule should start the day after the carpool", schedule.StartDate)
+	}
+
+	if _, err = FromCarpool(sundayCarpool(), days, time.Date(2026, 10, 1, 0, 0, 0, 0, Timezone.Location)); err == nil {
+		t.Error("expected an error for a schedule that ends before it starts")
+	}
+	if _, err = FromCarpool(sundayCarpool(), days, time.Date(2027, 6, 1, 0, 0, 0, 0, Timezone.Location)); err == nil {
+		t.Error("expected an error for a schedule longer than a semester")
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	schedule, _ := FromCarpool(sundayCarpool(), []time.Weekday{time.Sunday, time.Tuesday}, time.Date(2026, 11, 15, 0, 0, 0, 0, Timezone.Location))
+	Skip(&schedule, time.Date(2026, 11, 10, 0, 0, 0, 0, Timezone.Location))
+	Skip(&schedule, time.Date(2026, 11, 10, 0, 0, 0, 0, Timezone.Location))
+	if len(schedule.Skipped) != 1 {
+		t.Error("skipped a day twice", schedule.Skipped)
+	}
+
+	// Tue 3, Sun 8, (Tue 10 is skipped) and Sun 15, the last day.
+	times := Occurrences(schedule, time.Date(2026, 10, 1, 0, 0, 0, 0, Timezone.Location), time.Date(2027, 1, 1, 0, 0, 0, 0, Timezone.Location))
+	expected := []time.Time{
+		time.Date(2026, 11, 3, 8, 30, 0, 0, Timezone.Location),
+		time.Date(2026, 11, 8, 8, 30, 0, 0, Timezone.Location),
+		time.Date(2026, 11, 15, 8, 30, 0, 0, Timezone.Location),
+	}
+	if !reflect.DeepEqual(times, expected) {
+		t.Error("wrong occurrences", times)
+	}
+
+	// Only the ones between the two times.
+	times = Occurrences(schedule, time.Date(2026, 11, 3, 9, 0, 0, 0, Timezone.Location), time.Date(2026, 11, 8, 8, 30, 0, 0, Timezone.Location))
+	if len(times) != 0 {
+		t.Error("occurrences outside the times", times)
+	}
+
+	// A day brought back has its carpool again.
+	Unskip(&schedule, time.Date(2026, 11, 10, 8, 30, 0, 0, Timezone.Location))
+	if times = Occurrences(schedule, time.Date(2026, 11, 10, 0, 0, 0, 0, Timezone.Location), time.Date(2026, 11, 11, 0, 0, 0, 0, Timezone.Location)); len(times) != 1 || len(schedule.Skipped) != 0 {
+		t.Error("the day was not brought back", times, schedule.Skipped)
+	}
+
+	schedule.Paused = true
+	if times = Occurrences(schedule, time.Date(2026, 10, 1, 0, 0, 0, 0, Timezone.Location), time.Date(2027, 1, 1, 0, 0, 0, 0, Timezone.Location)); len(times) != 0 {
+		t.Error("a paused schedule has occurrences", times)
+	}
+}
+
+func TestOccurrencesAcrossDaylightSaving(t *testing.T) {
+	// Cairo moves its clocks back in the night after Thursday Oct 29 2026, and forward at the start of Friday Apr 30 2027, when there is no midnight.
+	carpool := sundayCarpool()
+	carpool.StartTime = time.Date(2026, 10, 25, 8, 30, 0, 0, Timezone.Location)
+	schedule, _ := FromCarpool(carpool, []time.Weekday{time.Tuesday, time.Sunday}, time.Date(2026, 11, 1, 0, 0, 0, 0, Timezone.Location))
+	times := Occurrences(schedule, carpool.StartTime, time.Date(2027, 1, 1, 0, 0, 0, 0, Timezone.Location))
+	if len(times) != 2 || times[0].Hour() != 8 || times[1].Hour() != 8 || times[1].Sub(times[0]) != 5*24*time.Hour+time.Hour {
+		t.Error("the carpools don't keep their time at the GUC", times)
+	}
+
+	carpool.StartTime = time.Date(2027, 4, 29, 8, 30, 0, 0, Timezone.Location)
+	schedule, _ = FromCarpool(carpool, []time.Weekday{time.Thursday, time.Friday}, time.Date(2027, 5, 6, 0, 0, 0, 0, Timezone.Location))
+	times = Occurrences(schedule, carpool.StartTime, time.Date(2027, 6, 1, 0, 0, 0, 0, Timezone.Location))
+	expected := []time.Time{
+		time.Date(2027, 4, 30, 8, 30, 0, 0, Timezone.Location),
+		time.Date(2027, 5, 6, 8, 30, 0, 0, Timezone.Location),
+	}
+	if !reflect.DeepEqual(times, expected) {
+		t.Error("wrong occurrences after the clocks moved forward", times)
+	}
+}
+
+func TestMaterialize(t *testing.T) {
+	store := NewMemoryStore()
+	schedule, _ := FromCarpool(sundayCarpool(), []time.Weekday{time.Sunday, time.Tuesday}, time.Date(2026, 11, 30, 0, 0, 0, 0, Timezone.Location))
+	store.Insert(&schedule)
+
+	// A week ahead of Monday Nov 2 is Tue 3 and Sun 8.
+	now := time.Date(2026, 11, 2, 12, 0, 0, 0, Timezone.Location)
+	created, err := Materialize(store, Calendar.Year{}, schedule, now, 7*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 2 || created[0].ScheduleID != schedule.ID || created[0].AvailableSeats != 3 || len(created[0].CurrentPassengers) != 0 || !reflect.DeepEqual(created[0].PossiblePassengers, []string{"34-1"}) || created[0].Status != DB.StatusOpen || created[0].Shift != 15 {
+		t.Error("wrong carpools", created)
+	}
+
+	// The carpools that were created are not created again.
+	created, _ = Materialize(store, Calendar.Year{}, schedule, now.Add(24*time.Hour), 7*24*time.Hour)
+	if len(created) != 1 || !created[0].StartTime.Equal(time.Date(2026, 11, 10, 8, 30, 0, 0, Timezone.Location)) {
+		t.Error("wrong carpools the next day", created)
+	}
+
+	// The standing passengers only ask to join, so the seats are not taken until the driver accepts them (Sun 15 and Tue 17).
+	AddStandingPassenger(&schedule, "34-2")
+	AddStandingPassenger(&schedule, "34-3")
+	created, _ = Materialize(store, Calendar.Year{}, schedule, now.Add(8*24*time.Hour), 7*24*time.Hour)
+	if len(created) != 2 || created[0].AvailableSeats != 3 || created[0].Status != DB.StatusOpen || len(created[0].PossiblePassengers) != 3 {
+		t.Error("wrong carpool with standing passengers", created)
+	}
+
+	// A passenger who is not standing anymore is taken out of the carpools already created.
+	store.RemovePassenger(created[0].PostID, "34-2")
+	carpools, _ := store.Occurrences(schedule.ID, created[0].StartTime, created[0].StartTime.Add(time.Minute))
+	if len(carpools) != 1 || !reflect.DeepEqual(carpools[0].PossiblePassengers, []string{"34-1", "34-3"}) {
+		t.Error("passenger was not taken out", carpools)
+	}
+
+	// No carpools on holidays (Tue 24) or between semesters (Sun 29).
+	year, _ := Calendar.Load(Calendar.NewMemoryStore(
+		DB.CalendarEntry{Kind: Calendar.Semester, Name: "Winter", Start: "2026-09-13", End: "2026-11-28"},
+		DB.CalendarEntry{Kind: Calendar.Holiday, Name: "Holiday", Start: "2026-11-24", End: "2026-11-24"},
+	))
+	created, _ = Materialize(store, year, schedule, now.Add(15*24*time.Hour), 14*24*time.Hour)
+	if len(created) != 1 || !created[0].StartTime.Equal(time.Date(2026, 11, 22, 8, 30, 0, 0, Timezone.Location)) {
+		t.Error("wrong carpools around the holidays", created)
+	}
+}
+
+func TestStandingPassengers(t *testing.T) {
+	schedule, _ := FromCarpool(sundayCarpool(), []time.Weekday{time.Sunday}, time.Date(2026, 12, 1, 0, 0, 0, 0, Timezone.Location))
+	if err := AddStandingPassenger(&schedule, "34-1234"); err == nil {
+		t.Error("the driver was added as a passenger")
+	}
+	if err := AddStandingPassenger(&schedule, "34-1"); err == nil {
+		t.Error("a passenger was added twice")
+	}
+	if err := AddStandingPassenger(&schedule, "34-2"); err != nil {
+		t.Error(err)
+	}
+	AddStandingPassenger(&schedule, "34-3")
+	if err := AddStandingPassenger(&schedule, "34-4"); err == nil {
+		t.Error("more standing passengers than seats")
+	}
+	if err := RemoveStandingPassenger(&schedule, "34-1"); err != nil || !reflect.DeepEqual(schedule.StandingPassengers, []string{"34-2", "34-3"}) {
+		t.Error("wrong standing passengers", schedule.StandingPassengers, err)
+	}
+	if err := RemoveStandingPassenger(&schedule, "34-1"); err == nil {
+		t.Error("removed a passenger that isn't standing")
+	}
+}
@@ -0,0 +1,212 @@
+package Roster
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+)
+
+// Errors returned when looking up a student.
+var (
+	ErrNotFound = Problem.New("problem.notStudent", nil, "I can't find this GUC ID among the students of the GUC")
+	ErrInactive = Problem.New("problem.inactive", nil, "this GUC ID is no longer on the roster of the GUC")
+)
+
+// gucIDFormat : what a whole GUC ID looks like (eg. 34-1234), nothing before or after it.
+var gucIDFormat = regexp.MustCompile(`^[0-9]+-[0-9]+$`)
+
+// ValidGUCID : checks that the text is a GUC ID and nothing else.
+func ValidGUCID(GUCID string) bool {
+	return gucIDFormat.MatchString(GUCID)
+}
+
+// Store : keeps the roster of the students.
+type Store interface {
+	Get(GUCID string) (DB.Student, bool, error)
+	Upsert(student *DB.Student) (bool, error)
+	DeactivateNotImportedSince(since time.Time) (int, error)
+}
+
+// DBStore : the store that keeps the roster in the database.
+type DBStore struct{}
+
+// Get : loads the student from the database.
+func (DBStore) Get(GUCID string) (DB.Student, bool, error) { return DB.GetStudent(GUCID) }
+
+// Upsert : saves the student in the database.
+func (DBStore) Upsert(student *DB.Student) (bool, error) { return DB.UpsertStudent(student) }
+
+// DeactivateNotImportedSince : deactivates the students in the database that were left out of the imports since the given time.
+func (DBStore) DeactivateNotImportedSince(since time.Time) (int, error) {
+	return DB.DeactivateStudentsNotImportedSince(since)
+}
+
+// MemoryStore : a store that keeps the roster in memory, for tests and running locally.
+type MemoryStore struct {
+	mutex    sync.Mutex
+	students map[string]DB.Student
+}
+
+// NewMemoryStore : creates a memory store with the given students, all active.
+func NewMemoryStore(students ...DB.Student) *MemoryStore {
+	m := &MemoryStore{students: map[string]DB.Student{}}
+	for _, student := range students {
+		student.Active = true
+		m.students[student.GUCID] = student
+	}
+	return m
+}
+
+// Get : returns the student from memory.
+func (m *MemoryStore) Get(GUCID string) (DB.Student, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	student, found := m.students[GUCID]
+	return student, found, nil
+}
+
+// Upsert : saves the student in memory.
+func (m *MemoryStore) Upsert(student *DB.Student) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, found := m.students[student.GUCID]
+	m.students[student.GUCID] = *student
+	return !found, nil
+}
+
+// DeactivateNotImportedSince : deactivates the students in memory that were left out of the imports since the given time.
+func (m *MemoryStore) DeactivateNotImportedSince(since time.Time) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	count := 0
+	for GUCID, student := range m.students {
+		if student.Active && student.ImportedAt.Before(since) {
+			student.Active = false
+			m.students[GUCID] = student
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Lookup : returns the active student with the given GUC ID.
+func Lookup(store Store, GUCID string) (DB.Student, error) {
+	GUCID = strings.TrimSpace(GUCID)
+	if !ValidGUCID(GUCID) {
+		return DB.Student{}, ErrNotFound
+	}
+	student, found, err := store.Get(GUCID)
+	if err != nil {
+		return student, err
+	}
+	if !found {
+		return student, ErrNotFound
+	}
+	if !student.Active {
+		return student, ErrInactive
+	}
+	return student, nil
+}
+
+// Address : where to email a student, the address on the roster or else the one derived from their GUC ID. Only active students have one.
+func Address(store Store) func(GUCID string) (string, error) {
+	return func(GUCID string) (string, error) {
+		student, err := Lookup(store, GUCID)
+		if err != nil {
+			return "", err
+		}
+		if student.Email != "" {
+			return student.Email, nil
+		}
+		return student.GUCID + "@" + Notifier.EmailDomain, nil
+	}
+}
+
+// Parse : reads a roster CSV with the columns ID, full name, email, faculty and gender. A header row is skipped. Every row has to be valid, since students left out would be deactivated.
+func Parse(reader io.Reader) ([]DB.Student, error) {
+	rows := csv.NewReader(reader)
+	rows.FieldsPerRecord = 5
+	rows.TrimLeadingSpace = true
+	var students []DB.Student
+	seen := map[string]int{}
+	for line := 1; ; line++ {
+		row, err := rows.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+		if line == 1 && strings.EqualFold(row[0], "id") {
+			continue
+		}
+		student := DB.Student{
+			GUCID:   row[0],
+			Name:    strings.Join(strings.Fields(row[1]), " "),
+			Email:   strings.ToLower(row[2]),
+			Faculty: row[3],
+			Gender:  strings.ToLower(row[4]),
+		}
+		if !ValidGUCID(student.GUCID) {
+			return nil, fmt.Errorf("line %d: %q is not a GUC ID", line, student.GUCID)
+		}
+		if student.Name == "" {
+			return nil, fmt.Errorf("line %d: the name of %s is missing", line, student.GUCID)
+		}
+		if student.Email != "" && !strings.Contains(student.Email, "@") {
+			return nil, fmt.Errorf("line %d: %q is not an email address", line, student.Email)
+		}
+		if first, found := seen[student.GUCID]; found {
+			return nil, fmt.Errorf("line %d: %s is already on line %d", line, student.GUCID, first)
+		}
+		seen[student.GUCID] = line
+		students = append(students, student)
+	}
+	if len(students) == 0 {
+		return nil, errors.New("the roster is empty")
+	}
+	return students, nil
+}
+
+// Result : what an import changed.
+type Result struct {
+	Added       int
+	Updated     int
+	Deactivated int
+}
+
+// Import : saves the students as the current roster. Students that are not on it anymore are deactivated, and students that came back are activated again.
+func Import(store Store, students []DB.Student, now time.Time) (Result, error) {
+	// The database keeps times in milliseconds, so this import has to be too, or its own students would be older than it.
+	now = now.Truncate(time.Millisecond)
+	var result Result
+	for i := range students {
+		student := students[i]
+		student.Active = true
+		student.ImportedAt = now
+		added, err := store.Upsert(&student)
+		if err != nil {
+			return result, err
+		}
+		if added {
+			result.Added++
+		} else {
+			result.Updated++
+		}
+	}
+	deactivated, err := store.DeactivateNotImportedSince(now)
+	result.Deactivated = deactivated
+	return result, err
+}
@@ -0,0 +1,96 @@
+package Roster
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+)
+
+func TestValidGUCID(t *testing.T) {
+	for _, id := range []string{"34-1234", "1-2"} {
+		if !ValidGUCID(id) {
+			t.Error(id + " should be valid")
+		}
+	}
+	for _, id := range []string{"abc1-2xyz", "34-1234 ", "34_1234", "-1234", "34-", "34-1234-5", ""} {
+		if ValidGUCID(id) {
+			t.Error(id + " should not be valid")
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	students, err := Parse(strings.NewReader("ID,Full name,Email,Faculty,Gender\n34-1234, Ahmed  Ali ,Ahmed.Ali@student.guc.edu.eg,MET,Male\n34-5678,Sara Omar,,EMS,female\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(students) != 2 {
+		t.Fatal("expected 2 students, got", len(students))
+	}
+	if students[0] != (DB.Student{GUCID: "34-1234", Name: "Ahmed Ali", Email: "ahmed.ali@student.guc.edu.eg", Faculty: "MET", Gender: "male"}) {
+		t.Error("wrong student", students[0])
+	}
+}
+
+func TestParseRefusesBadRows(t *testing.T) {
+	cases := map[string]string{
+		"bad ID":       "abc1-2xyz,Ahmed,,MET,male\n",
+		"no name":      "34-1234,,,MET,male\n",
+		"bad email":    "34-1234,Ahmed,ahmed,MET,male\n",
+		"duplicate ID": "34-1234,Ahmed,,MET,male\n34-1234,Omar,,MET,male\n",
+		"few columns":  "34-1234,Ahmed\n",
+		"empty":        "ID,Full name,Email,Faculty,Gender\n",
+	}
+	for name, roster := range cases {
+		if _, err := Parse(strings.NewReader(roster)); err == nil {
+			t.Error("accepted a roster with " + name)
+		}
+	}
+}
+
+func TestImportDeactivatesGraduates(t *testing.T) {
+	store := NewMemoryStore()
+	first := []DB.Student{{GUCID: "34-1", Name: "Ahmed"}, {GUCID: "34-2", Name: "Sara"}}
+	result, err := Import(store, first, time.Now())
+	if err != nil || result != (Result{Added: 2}) {
+		t.Fatal("first import", result, err)
+	}
+
+	// Sara graduated, Omar joined and Ahmed changed his name.
+	second := []DB.Student{{GUCID: "34-1", Name: "Ahmed Ali"}, {GUCID: "34-3", Name: "Omar"}}
+	result, err = Import(store, second, time.Now().Add(time.Second))
+	if err != nil || result != (Result{Added: 1, Updated: 1, Deactivated: 1}) {
+		t.Fatal("second import", result, err)
+	}
+	if student, err := Lookup(store, "34-1"); err != nil || student.Name != "Ahmed Ali" {
+		t.Error("the official name was not updated", student, err)
+	}
+	if _, err := Lookup(store, "34-2"); err != ErrInactive {
+		t.Error("a graduate can still log in", err)
+	}
+	if _, err := Lookup(store, "34-9"); err != ErrNotFound {
+		t.Error("a student that is not on the roster was found", err)
+	}
+
+	// Sara came back.
+	result, _ = Import(store, append(second, DB.Student{GUCID: "34-2", Name: "Sara"}), time.Now().Add(2*time.Second))
+	if _, err := Lookup(store, "34-2"); err != nil || result.Deactivated != 0 {
+		t.Error("a returning student was not activated again", result, err)
+	}
+}
+
+func TestAddress(t *testing.T) {
+	store := NewMemoryStore(DB.Student{GUCID: "34-1", Name: "Ahmed", Email: "ahmed@guc.edu.eg"}, DB.Student{GUCID: "34-2", Name: "Sara"})
+	address := Address(store)
+	if email, _ := address("34-1"); email != "ahmed@guc.edu.eg" {
+		t.Error("the email on the roster was not used", email)
+	}
+	if email, _ := address("34-2"); email != "34-2@student.guc.edu.eg" {
+		t.Error("the email was not derived from the GUC ID", email)
+	}
+	if _, err := address("34-3"); err == nil {
+		t.Error("a code can be sent to a student that is not on the roster")
+	}
+}
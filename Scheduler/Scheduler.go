@@ -0,0 +1,263 @@
+package Scheduler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+)
+
+// Store : keeps the scheduled jobs somewhere all the server instances can see them.
+type Store interface {
+	Save(job *DB.ScheduledJob) error
+	Due(now time.Time) ([]DB.ScheduledJob, error)
+	Claim(ID string, instance string, now time.Time, lease time.Duration) (bool, error)
+	MarkSent(ID string) error
+	DeleteByPostID(PostID uint64) error
+}
+
+// DBStore : the store that keeps the jobs in the database, so they survive restarts.
+type DBStore struct{}
+
+// Save : saves the job in the database.
+func (DBStore) Save(job *DB.ScheduledJob) error { return DB.SaveScheduledJob(job) }
+
+// Due : returns the jobs in the database that should be sent by now.
+func (DBStore) Due(now time.Time) ([]DB.ScheduledJob, error) { return DB.GetDueScheduledJobs(now) }
+
+// Claim : claims the job in the database.
+func (DBStore) Claim(ID string, instance string, now time.Time, lease time.Duration) (bool, error) {
+	return DB.ClaimScheduledJob(ID, instance, now, lease)
+}
+
+// MarkSent : marks the job as sent in the database.
+func (DBStore) MarkSent(ID string) error { return DB.MarkScheduledJobSent(ID) }
+
+// DeleteByPostID : removes the jobs of a carpool from the database.
+func (DBStore) DeleteByPostID(PostID uint64) error { return DB.DeleteScheduledJobsByPostID(PostID) }
+
+// MemoryStore : a store that keeps the jobs in memory. Jobs are lost on restart, so it is only meant for testing and running locally.
+type MemoryStore struct {
+	mutex sync.Mutex
+	jobs  map[string]DB.ScheduledJob
+}
+
+// NewMemoryStore : creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: map[string]DB.ScheduledJob{}}
+}
+
+// Save : saves the job in memory.
+func (m *MemoryStore) Save(job *DB.ScheduledJob) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+// Due : returns the jobs in memory that should be sent by now.
+func (m *MemoryStore) Due(now time.Time) ([]DB.ScheduledJob, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var results []DB.ScheduledJob
+	for _, job := range m.jobs {
+		if !job.Sent && !job.RunAt.After(now) {
+			results = append(results, job)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].RunAt.Before(results[j].RunAt) })
+	return results, nil
+}
+
+// Claim : claims the job in memory.
+func (m *MemoryStore) Claim(ID string, instance string, now time.Time, lease time.Duration) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	job, found := m.jobs[ID]
+	if !found || job.Sent {
+		return false, nil
+	}
+	if job.ClaimedBy != "" && !job.ClaimedAt.Before(now.Add(-lease)) {
+		return false, nil
+	}
+	job.ClaimedBy = instance
+	job.ClaimedAt = now
+	m.jobs[ID] = job
+	return true, nil
+}
+
+// MarkSent : marks the job as sent in memory.
+func (m *MemoryStore) MarkSent(ID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	job, found := m.jobs[ID]
+	if !found {
+		return errors.New("no job with ID " + ID)
+	}
+	job.Sent = true
+	m.jobs[ID] = job
+	return nil
+}
+
+// DeleteByPostID : removes the jobs of a carpool from memory.
+func (m *MemoryStore) DeleteByPostID(PostID uint64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for ID, job := range m.jobs {
+		if job.PostID == PostID {
+			delete(m.jobs, ID)
+		}
+	}
+	return nil
+}
+
+// Scheduler : sends reminders about carpools at fixed offsets before their start time.
+type Scheduler struct {
+	Store    Store
+	Offsets  []time.Duration
+	Send     func(job DB.ScheduledJob) error
+	Instance string
+	Interval time.Duration // how often to look for due jobs
+	Lease    time.Duration // how long a claimed job is left alone before another instance retries it
+}
+
+// New : creates a scheduler with a unique instance name.
+func New(store Store, offsets []time.Duration, send func(job DB.ScheduledJob) error) *Scheduler {
+	return &Scheduler{
+		Store:    store,
+		Offsets:  offsets,
+		Send:     send,
+		Instance: instanceName(),
+		Interval: time.Minute,
+		Lease:    5 * time.Minute,
+	}
+}
+
+// ParseOffsets : parses a comma separated list of minutes (eg. "60,15").
+func ParseOffsets(minutes string) ([]time.Duration, error) {
+	var offsets []time.Duration
+	for _, part := range strings.Split(minutes, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		number, err := strconv.Atoi(part)
+		if err != nil || number <= 0 {
+			return nil, errors.New("invalid reminder offset " + part)
+		}
+		offsets = append(offsets, time.Duration(number)*time.Minute)
+	}
+	return offsets, nil
+}
+
+// ScheduleReminders : schedules a reminder for every offset before the start time of the carpool, replacing old reminders if the carpool was edited.
+func (s *Scheduler) ScheduleReminders(PostID uint64, startTime time.Time) error {
+	err := s.Store.DeleteByPostID(PostID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, offset := range s.Offsets {
+		runAt := startTime.Add(-offset)
+		if runAt.Before(now) {
+			continue
+		}
+		minutes := int(offset / time.Minute)
+		job := DB.ScheduledJob{
+			ID:      fmt.Sprintf("%d-%d", PostID, minutes),
+			PostID:  PostID,
+			Minutes: minutes,
+			RunAt:   runAt,
+		}
+		err = s.Store.Save(&job)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CancelReminders : removes the reminders of a carpool.
+func (s *Scheduler) CancelReminders(PostID uint64) error {
+	return s.Store.DeleteByPostID(PostID)
+}
+
+// RunOnce : sends every due job that no other instance is sending, and returns how many were sent. The jobs of carpools that already left (eg. while every instance was down) are dropped without sending them.
+func (s *Scheduler) RunOnce(now time.Time) (int, error) {
+	jobs, err := s.Store.Due(now)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, job := range jobs {
+		if now.After(job.RunAt.Add(time.Duration(job.Minutes) * time.Minute)) {
+			err = s.Store.MarkSent(job.ID)
+			if err != nil {
+				return sent, err
+			}
+			log.Printf("dropped job %s, its carpool already left\n", job.ID)
+			continue
+		}
+		claimed, err := s.Store.Claim(job.ID, s.Instance, now, s.Lease)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+		err = s.Send(job)
+		if err != nil {
+			// Leave the job claimed, it will be retried once the lease runs out.
+			log.Printf("could not send job %s: %s\n", job.ID, err.Error())
+			continue
+		}
+		err = s.Store.MarkSent(job.ID)
+		if err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Run : keeps checking for due jobs until the stop channel is closed.
+func (s *Scheduler) Run(stop <-chan struct{}) {
+	Every(s.Interval, stop, func() error {
+		_, err := s.RunOnce(time.Now())
+		return err
+	})
+}
+
+// Every : runs the task at every interval until the stop channel is closed. Used for the periodic jobs that are not tied to a carpool.
+func Every(interval time.Duration, stop <-chan struct{}, task func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := task()
+		if err != nil {
+			log.Println("periodic task error: " + err.Error())
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Function that gives every server instance its own name, so they can tell their claims apart.
+func instanceName() string {
+	host, _ := os.Hostname()
+	random := make([]byte, 4)
+	rand.Read(random)
+	return host + "-" + strconv.Itoa(os.Getpid()) + "-" + hex.EncodeToString(random)
+}
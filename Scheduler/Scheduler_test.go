@@ -0,0 +1,176 @@
+package Scheduler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+)
+
+func TestParseOffsets(t *testing.T) {
+	offsets, err := ParseOffsets("60, 15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offsets) != 2 || offsets[0] != time.Hour || offsets[1] != 15*time.Minute {
+		t.Error("wrong offsets", offsets)
+	}
+	if _, err = ParseOffsets("60,soon"); err == nil {
+		t.Error("expected an error for an invalid offset")
+	}
+}
+
+func TestScheduleReminders(t *testing.T) {
+	store := NewMemoryStore()
+	scheduler := New(store, []time.Duration{time.Hour, 15 * time.Minute}, nil)
+
+	// The carpool starts in 30 minutes, so the reminder an hour before is already too late.
+	startTime := time.Now().Add(30 * time.Minute)
+	err := scheduler.ScheduleReminders(7, startTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs, _ := store.Due(startTime)
+	if len(jobs) != 1 || jobs[0].Minutes != 15 || !jobs[0].RunAt.Equal(startTime.Add(-15*time.Minute)) {
+		t.Error("wrong jobs", jobs)
+	}
+
+	err = scheduler.CancelReminders(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs, _ = store.Due(startTime)
+	if len(jobs) != 0 {
+		t.Error("reminders were not cancelled", jobs)
+	}
+}
+
+func TestRunOnceSendsOnlyDueJobs(t *testing.T) {
+	store := NewMemoryStore()
+	var sent []DB.ScheduledJob
+	scheduler := New(store, []time.Duration{time.Hour, 15 * time.Minute}, func(job DB.ScheduledJob) error {
+		sent = append(sent, job)
+		return nil
+	})
+	startTime := time.Now().Add(2 * time.Hour)
+	scheduler.ScheduleReminders(3, startTime)
+
+	count, err := scheduler.RunOnce(startTime.Add(-time.Hour))
+	if err != nil || count != 1 || sent[0].Minutes != 60 {
+		t.Error("expected only the 60 minute reminder", count, err, sent)
+	}
+	count, _ = scheduler.RunOnce(startTime.Add(-time.Hour))
+	if count != 0 {
+		t.Error("reminder was sent twice")
+	}
+	count, _ = scheduler.RunOnce(startTime.Add(-10 * time.Minute))
+	if count != 1 || sent[1].Minutes != 15 {
+		t.Error("expected the 15 minute reminder", count, sent)
+	}
+}
+
+func TestSurvivesRestart(t *testing.T) {
+	store := NewMemoryStore()
+	startTime := time.Now().Add(2 * time.Hour)
+	New(store, []time.Duration{time.Hour}, nil).ScheduleReminders(4, startTime)
+
+	// A new scheduler on the same store picks up the jobs of the old one.
+	sent := 0
+	restarted := New(store, []time.Duration{time.Hour}, func(job DB.ScheduledJob) error {
+		sent++
+		return nil
+	})
+	restarted.RunOnce(startTime)
+	if sent != 1 {
+		t.Error("job was lost on restart")
+	}
+}
+
+func TestNoDoubleSendingAcrossInstances(t *testing.T) {
+	store := NewMemoryStore()
+	var mutex sync.Mutex
+	sent := map[string]int{}
+	send := func(job DB.ScheduledJob) error {
+		mutex.Lock()
+		sent[job.ID]++
+		mutex.Unlock()
+		return nil
+	}
+	startTime := time.Now().Add(3 * time.Hour)
+	first := New(store, []time.Duration{2 * time.Hour, time.Hour, 15 * time.Minute}, send)
+	second := New(store, first.Offsets, send)
+	for postID := uint64(1); postID <= 20; postID++ {
+		first.ScheduleReminders(postID, startTime)
+	}
+
+	var wait sync.WaitGroup
+	for _, scheduler := range []*Scheduler{first, second} {
+		wait.Add(1)
+		go func(scheduler *Scheduler) {
+			defer wait.Done()
+			scheduler.RunOnce(startTime)
+		}(scheduler)
+	}
+	wait.Wait()
+
+	if len(sent) != 60 {
+		t.Error("expected 60 reminders, got", len(sent))
+	}
+	for ID, count := range sent {
+		if count != 1 {
+			t.Error("job", ID, "was sent", count, "times")
+		}
+	}
+}
+
+func TestFailedJobIsRetriedAfterLease(t *testing.T) {
+	store := NewMemoryStore()
+	fail := true
+	scheduler := New(store, []time.Duration{time.Hour}, func(job DB.ScheduledJob) error {
+		if fail {
+			return errTest
+		}
+		return nil
+	})
+	startTime := time.Now().Add(2 * time.Hour)
+	scheduler.ScheduleReminders(5, startTime)
+	runAt := startTime.Add(-time.Hour)
+
+	count, _ := scheduler.RunOnce(runAt)
+	if count != 0 {
+		t.Error("failed job was counted as sent")
+	}
+	fail = false
+	count, _ = scheduler.RunOnce(runAt.Add(time.Minute))
+	if count != 0 {
+		t.Error("job was retried before its lease ran out")
+	}
+	count, _ = scheduler.RunOnce(runAt.Add(scheduler.Lease + time.Minute))
+	if count != 1 {
+		t.Error("job was not retried after its lease ran out")
+	}
+}
+
+func TestJobsOfLeftCarpoolsAreDropped(t *testing.T) {
+	store := NewMemoryStore()
+	sent := 0
+	scheduler := New(store, []time.Duration{time.Hour, 15 * time.Minute}, func(job DB.ScheduledJob) error {
+		sent++
+		return nil
+	})
+	startTime := time.Now().Add(2 * time.Hour)
+	scheduler.ScheduleReminders(6, startTime)
+
+	// No instance was running until after the carpool left.
+	count, err := scheduler.RunOnce(startTime.Add(time.Minute))
+	if err != nil || count != 0 || sent != 0 {
+		t.Error("sent reminders about a carpool that already left", count, err)
+	}
+	if jobs, _ := store.Due(startTime.Add(time.Hour)); len(jobs) != 0 {
+		t.Error("the dropped jobs are still due", jobs)
+	}
+}
+
+var errTest = errors.New("test error")
@@ -0,0 +1,212 @@
+package Sessions
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+)
+
+// Keys of the session values the stores need to know about.
+const (
+	GUCIDKey     = "gucID"
+	ExpiresAtKey = "expiresAt"
+)
+
+// Session : models the session of a user, everything the chat remembers about them.
+type Session map[string]interface{}
+
+// Store : keeps the sessions of the users. Get returns a copy of the session, so changes have to be saved with Save.
+type Store interface {
+	Get(uuid string) (Session, bool, error)
+	Save(uuid string, session Session) error
+	Delete(uuid string) error
+	// FindByGUCID returns another session of the same user, if there is one.
+	FindByGUCID(GUCID string, except string) (string, Session, bool, error)
+	DeleteExpired(now time.Time) (int, error)
+}
+
+// NewToken : creates a random 256-bit session token.
+func NewToken() (string, error) {
+	token := make([]byte, 32)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// Touch : pushes the expiry of the session to ttl from now.
+func (s Session) Touch(now time.Time, ttl time.Duration) {
+	s[ExpiresAtKey] = now.Add(ttl)
+}
+
+// Expired : checks if the session was idle for longer than its TTL.
+func (s Session) Expired(now time.Time) bool {
+	expiresAt, found := s[ExpiresAtKey].(time.Time)
+	return !found || now.After(expiresAt)
+}
+
+// Copy : returns a shallow copy of the session.
+func (s Session) Copy() Session {
+	copied := Session{}
+	for key, val := range s {
+		copied[key] = val
+	}
+	return copied
+}
+
+// Function that returns the GUC ID of a logged in session.
+func gucIDOf(session Session) string {
+	gucID, _ := session[GUCIDKey].(string)
+	return gucID
+}
+
+// MemoryStore : a store that keeps the sessions in memory. Safe to use from concurrent requests, but the sessions are lost on restart.
+type MemoryStore struct {
+	mutex    sync.Mutex
+	sessions map[string]Session
+}
+
+// NewMemoryStore : creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: map[string]Session{}}
+}
+
+// Get : returns a copy of the session.
+func (m *MemoryStore) Get(uuid string) (Session, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	session, found := m.sessions[uuid]
+	if !found {
+		return nil, false, nil
+	}
+	return session.Copy(), true, nil
+}
+
+// Save : saves a copy of the session.
+func (m *MemoryStore) Save(uuid string, session Session) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sessions[uuid] = session.Copy()
+	return nil
+}
+
+// Delete : removes the session.
+func (m *MemoryStore) Delete(uuid string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.sessions, uuid)
+	return nil
+}
+
+// FindByGUCID : returns another session logged in with the same GUC ID.
+func (m *MemoryStore) FindByGUCID(GUCID string, except string) (string, Session, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for uuid, session := range m.sessions {
+		if uuid != except && strings.EqualFold(gucIDOf(session), GUCID) {
+			return uuid, session.Copy(), true, nil
+		}
+	}
+	return "", nil, false, nil
+}
+
+// DeleteExpired : removes the expired sessions and returns how many were removed.
+func (m *MemoryStore) DeleteExpired(now time.Time) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	count := 0
+	for uuid, session := range m.sessions {
+		if session.Expired(now) {
+			delete(m.sessions, uuid)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MongoStore : a store that keeps the sessions in the database, so they survive restarts.
+type MongoStore struct{}
+
+// Get : loads the session from the database.
+func (MongoStore) Get(uuid string) (Session, bool, error) {
+	stored, found, err := DB.GetSession(uuid)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	session, err := decode(stored.Data)
+	if err != nil {
+		return nil, false, err
+	}
+	return session, true, nil
+}
+
+// Save : saves the session in the database.
+func (MongoStore) Save(uuid string, session Session) error {
+	data, err := encode(session)
+	if err != nil {
+		return err
+	}
+	expiresAt, _ := session[ExpiresAtKey].(time.Time)
+	return DB.SaveSession(&DB.StoredSession{
+		UUID:      uuid,
+		GUCID:     strings.ToLower(gucIDOf(session)),
+		ExpiresAt: expiresAt,
+		Data:      data,
+	})
+}
+
+// Delete : removes the session from the database.
+func (MongoStore) Delete(uuid string) error {
+	return DB.DeleteSession(uuid)
+}
+
+// FindByGUCID : returns another session in the database logged in with the same GUC ID.
+func (MongoStore) FindByGUCID(GUCID string, except string) (string, Session, bool, error) {
+	stored, found, err := DB.FindSessionByGUCID(strings.ToLower(GUCID), except)
+	if err != nil || !found {
+		return "", nil, false, err
+	}
+	session, err := decode(stored.Data)
+	if err != nil {
+		return "", nil, false, err
+	}
+	return stored.UUID, session, true, nil
+}
+
+// DeleteExpired : removes the expired sessions from the database.
+func (MongoStore) DeleteExpired(now time.Time) (int, error) {
+	return DB.DeleteExpiredSessions(now)
+}
+
+// The values kept in sessions have to be registered, so gob can bring them back with the same types.
+func init() {
+	gob.Register(time.Time{})
+	gob.Register([]string{})
+}
+
+// encode : turns the session into bytes. Gob keeps the types of the values (eg. uint64 post IDs), which bson would not.
+func encode(session Session) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	err := gob.NewEncoder(buffer).Encode(map[string]interface{}(session))
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// decode : turns the bytes saved by encode back into a session.
+func decode(data []byte) (Session, error) {
+	session := Session{}
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode((*map[string]interface{})(&session))
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
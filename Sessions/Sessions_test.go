@@ -0,0 +1,144 @@
+package Sessions
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreReturnsCopies(t *testing.T) {
+	store := NewMemoryStore()
+	store.Save("a", Session{"name": "Ahmed"})
+	session, found, _ := store.Get("a")
+	if !found {
+		t.Fatal("session not found")
+	}
+	session["name"] = "Omar"
+	again, _, _ := store.Get("a")
+	if again["name"] != "Ahmed" {
+		t.Error("changing a session changed the store before saving it")
+	}
+}
+
+func TestMemoryStoreFindByGUCID(t *testing.T) {
+	store := NewMemoryStore()
+	store.Save("old", Session{GUCIDKey: "34-1234", "postID": uint64(3)})
+	store.Save("new", Session{GUCIDKey: "34-1234"})
+	uuid, session, found, _ := store.FindByGUCID("34-1234", "new")
+	if !found || uuid != "old" || session["postID"] != uint64(3) {
+		t.Error("old session not found", uuid, session)
+	}
+	_, _, found, _ = store.FindByGUCID("34-9999", "new")
+	if found {
+		t.Error("found a session for a user that never logged in")
+	}
+}
+
+func TestMemoryStoreDeleteExpired(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	active := Session{}
+	active.Touch(now, time.Minute)
+	expired := Session{}
+	expired.Touch(now.Add(-time.Hour), time.Minute)
+	store.Save("active", active)
+	store.Save("expired", expired)
+
+	count, _ := store.DeleteExpired(now)
+	if count != 1 {
+		t.Error("expected one expired session, got", count)
+	}
+	if _, found, _ := store.Get("active"); !found {
+		t.Error("active session was removed")
+	}
+	if _, found, _ := store.Get("expired"); found {
+		t.Error("expired session was kept")
+	}
+}
+
+func TestEncodeKeepsTypes(t *testing.T) {
+	startTime := time.Date(2017, 11, 5, 8, 30, 0, 0, time.UTC)
+	session := Session{
+		"gucID":              "34-1234",
+		"postID":             uint64(12),
+		"availableSeats":     3,
+		"latitude":           30.0320,
+		"fromGUC":            true,
+		"time":               startTime,
+		"currentPassengers":  []string{"34-1", "34-2"},
+		"possiblePassengers": []string{},
+	}
+	data, err := encode(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded["postID"].(uint64); !ok {
+		t.Error("postID is no longer a uint64")
+	}
+	if _, ok := decoded["availableSeats"].(int); !ok {
+		t.Error("availableSeats is no longer an int")
+	}
+	if passengers, ok := decoded["currentPassengers"].([]string); !ok || len(passengers) != 2 {
+		t.Error("currentPassengers is no longer a []string")
+	}
+	if decodedTime, ok := decoded["time"].(time.Time); !ok || !decodedTime.Equal(startTime) {
+		t.Error("time was not kept")
+	}
+	if decoded["fromGUC"] != true || decoded["latitude"] != 30.0320 || decoded["gucID"] != "34-1234" {
+		t.Error("values were not kept", decoded)
+	}
+}
+
+// Run with -race.
+func TestMemoryStoreConcurrentAccess(t *testing.T) {
+	store := NewMemoryStore()
+	var wait sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			uuid := strconv.Itoa(i % 5)
+			session, found, _ := store.Get(uuid)
+			if !found {
+				session = Session{GUCIDKey: "34-" + uuid}
+			}
+			session.Touch(time.Now(), time.Minute)
+			store.Save(uuid, session)
+			store.FindByGUCID("34-1", uuid)
+			store.DeleteExpired(time.Now())
+		}(i)
+	}
+	wait.Wait()
+}
+
+// Run with -race: only one holder of a key at a time, and different keys don't wait for each other.
+func TestMemoryLocker(t *testing.T) {
+	locker := NewMemoryLocker()
+	holders := 0
+	var wait sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			unlock, _ := locker.Lock("session:a")
+			holders++
+			if holders != 1 {
+				t.Error("two holders of the same lock")
+			}
+			time.Sleep(time.Millisecond)
+			holders--
+			unlock()
+		}()
+	}
+	unlock, _ := locker.Lock("session:b")
+	unlock()
+	wait.Wait()
+	if len(locker.locks) != 0 {
+		t.Error("unused locks were not forgotten")
+	}
+}
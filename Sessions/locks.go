@@ -0,0 +1,95 @@
+package Sessions
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+)
+
+// ErrLockTimeout : returned when a lock is still held by someone else after waiting for it.
+var ErrLockTimeout = errors.New("timed out waiting for the lock")
+
+// Locker : makes sure only one message of a user is handled at a time, even across server instances.
+type Locker interface {
+	Lock(key string) (unlock func(), err error)
+}
+
+// MemoryLocker : a locker for a single server instance, or for instances running in the same process.
+type MemoryLocker struct {
+	mutex sync.Mutex
+	locks map[string]*memoryLock
+}
+
+type memoryLock struct {
+	held    chan struct{}
+	waiters int
+}
+
+// NewMemoryLocker : creates a memory locker with no locks held.
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{locks: map[string]*memoryLock{}}
+}
+
+// Lock : waits until the key is free and takes it.
+func (m *MemoryLocker) Lock(key string) (func(), error) {
+	m.mutex.Lock()
+	lock, found := m.locks[key]
+	if !found {
+		lock = &memoryLock{held: make(chan struct{}, 1)}
+		m.locks[key] = lock
+	}
+	lock.waiters++
+	m.mutex.Unlock()
+
+	lock.held <- struct{}{}
+	return func() {
+		<-lock.held
+		m.mutex.Lock()
+		lock.waiters--
+		// Forget the lock once nobody needs it, so they don't pile up.
+		if lock.waiters == 0 {
+			delete(m.locks, key)
+		}
+		m.mutex.Unlock()
+	}, nil
+}
+
+// MongoLocker : a locker that keeps the locks in the database, so it works across server instances.
+type MongoLocker struct {
+	TTL     time.Duration // how long a lock is held if its owner never releases it
+	Timeout time.Duration // how long to wait for a lock
+	Retry   time.Duration // how often to try again while waiting
+}
+
+// NewMongoLocker : creates a locker with the default times.
+func NewMongoLocker() *MongoLocker {
+	return &MongoLocker{
+		TTL:     30 * time.Second,
+		Timeout: 10 * time.Second,
+		Retry:   50 * time.Millisecond,
+	}
+}
+
+// Lock : waits until the key is free and takes it.
+func (m *MongoLocker) Lock(key string) (func(), error) {
+	owner, err := NewToken()
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(m.Timeout)
+	for {
+		acquired, err := DB.AcquireLock(key, owner, m.TTL)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			return func() { DB.ReleaseLock(key, owner) }, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrLockTimeout
+		}
+		time.Sleep(m.Retry)
+	}
+}
@@ -0,0 +1,259 @@
+package Timetable
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Recurring"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+// Slots : the start and end of the slots of the GUC day, in minutes after midnight (eg. the first slot is from 8:30 to 10:00).
+var Slots = map[int][2]int{
+	1: {8*60 + 30, 10 * 60},
+	2: {10*60 + 15, 11*60 + 45},
+	3: {12 * 60, 13*60 + 30},
+	4: {13*60 + 45, 15*60 + 15},
+	5: {15*60 + 45, 17*60 + 15},
+}
+
+const (
+	// ArriveBefore : how many minutes before the first class a ride to the GUC is suggested to leave at the latest.
+	ArriveBefore = 30
+	// Window : how many minutes the suggested rides can be early, going to the GUC, or late, leaving it.
+	Window = 60
+)
+
+var (
+	clockFormat = regexp.MustCompile(`^([0-9]{1,2})[:.]([0-9]{2})$`)
+	byDay       = regexp.MustCompile(`BYDAY=([A-Z,]+)`)
+	icsDays     = map[string]time.Weekday{"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday}
+)
+
+// Function that reads a time of the day (eg. "8:30" or "15.45") in minutes after midnight.
+func parseClock(text string) (int, error) {
+	parts := clockFormat.FindStringSubmatch(strings.TrimSpace(text))
+	if parts == nil {
+		return 0, notClock(text)
+	}
+	hour, _ := strconv.Atoi(parts[1])
+	minute, _ := strconv.Atoi(parts[2])
+	if hour > 23 || minute > 59 {
+		return 0, notClock(text)
+	}
+	return hour*60 + minute, nil
+}
+
+// Function that returns the error for a time of the day that can't be read.
+func notClock(text string) error {
+	return Problem.New("problem.clock", map[string]interface{}{"Time": text}, fmt.Sprintf("%q is not a time like 8:30", text))
+}
+
+// Function that reads a slot, by its number (eg. "1") or its start and end (eg. "8:30-10:00").
+func parseSlot(text string) (int, int, error) {
+	if number, err := strconv.Atoi(text); err == nil {
+		slot, found := Slots[number]
+		if !found {
+			return 0, 0, Problem.New("problem.slot", map[string]interface{}{"Slot": number, "Slots": len(Slots)}, fmt.Sprintf("there is no slot %d, the slots go from 1 to %d", number, len(Slots)))
+		}
+		return slot[0], slot[1], nil
+	}
+	times := strings.Split(text, "-")
+	if len(times) != 2 {
+		return 0, 0, Problem.New("problem.slotFormat", map[string]interface{}{"Slot": text}, fmt.Sprintf("%q is not a slot number or a time like 8:30-10:00", text))
+	}
+	start, err := parseClock(times[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(times[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	if end <= start {
+		return 0, 0, Problem.New("problem.classEnds", map[string]interface{}{"Slot": text}, fmt.Sprintf("the class at %s ends before it starts", text))
+	}
+	return start, end, nil
+}
+
+// Clock : writes the minutes after midnight as a time of the day (eg. "8:30am").
+func Clock(minutes int) string {
+	return time.Date(2000, 1, 1, minutes/60, minutes%60, 0, 0, time.UTC).Format("3:04pm")
+}
+
+// ParseCSV : reads a timetable CSV with the columns day, slot and optionally the course (eg. "sun,1,CSEN 701"). The slot is its number or its times (eg. "8:30-10:00"). A header row is skipped.
+func ParseCSV(reader io.Reader) ([]DB.Class, error) {
+	rows := csv.NewReader(reader)
+	rows.FieldsPerRecord = -1
+	rows.TrimLeadingSpace = true
+	var classes []DB.Class
+	for line := 1; ; line++ {
+		row, err := rows.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+		if line == 1 && strings.EqualFold(row[0], "day") {
+			continue
+		}
+		if len(row) != 2 && len(row) != 3 {
+			return nil, Problem.New("problem.timetableRow", map[string]interface{}{"Line": line}, fmt.Sprintf("line %d: expected the day, the slot and the course", line))
+		}
+		days, err := Recurring.ParseDays(row[0])
+		if err != nil {
+			return nil, Problem.New("problem.timetableDay", map[string]interface{}{"Line": line, "Day": row[0]}, fmt.Sprintf("line %d: %q is not a day of the week", line, row[0]))
+		}
+		start, end, err := parseSlot(row[1])
+		if err != nil {
+			return nil, Problem.New("problem.line", map[string]interface{}{"Line": line, "Error": err}, fmt.Sprintf("line %d: %s", line, err.Error()))
+		}
+		name := ""
+		if len(row) == 3 {
+			name = row[2]
+		}
+		for _, day := range days {
+			classes = append(classes, DB.Class{Day: day, Start: start, End: end, Name: name})
+		}
+	}
+	return tidy(classes)
+}
+
+// ParseICS : reads the classes of an iCalendar timetable. An event repeated weekly (RRULE with BYDAY) is a class on each of its days. All day events are not classes, so they are left out.
+func ParseICS(reader io.Reader) ([]DB.Class, error) {
+	events, err := Calendar.ReadEvents(reader)
+	if err != nil {
+		return nil, err
+	}
+	var classes []DB.Class
+	for _, event := range events {
+		if !strings.Contains(event["DTSTART"], "T") {
+			continue
+		}
+		start, err := event.Time("DTSTART")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", event.Summary(), err.Error())
+		}
+		end := start.Add(90 * time.Minute)
+		if event["DTEND"] != "" {
+			end, err = event.Time("DTEND")
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", event.Summary(), err.Error())
+			}
+		}
+		days := []time.Weekday{start.Weekday()}
+		if parts := byDay.FindStringSubmatch(strings.ToUpper(event["RRULE"])); parts != nil {
+			days = nil
+			for _, code := range strings.Split(parts[1], ",") {
+				if day, found := icsDays[code]; found {
+					days = append(days, day)
+				}
+			}
+		}
+		for _, day := range days {
+			classes = append(classes, DB.Class{Day: day, Start: start.Hour()*60 + start.Minute(), End: end.Hour()*60 + end.Minute(), Name: event.Summary()})
+		}
+	}
+	return tidy(classes)
+}
+
+// Function that sorts the classes by day and time, leaving out the ones that are there twice (eg. the same class on every week of an iCalendar file).
+func tidy(classes []DB.Class) ([]DB.Class, error) {
+	if len(classes) == 0 {
+		return nil, Problem.New("problem.noClasses", nil, "the timetable has no classes")
+	}
+	seen := map[DB.Class]bool{}
+	tidied := []DB.Class{}
+	for _, class := range classes {
+		if !seen[class] {
+			seen[class] = true
+			tidied = append(tidied, class)
+		}
+	}
+	sort.Slice(tidied, func(i, j int) bool {
+		if tidied[i].Day != tidied[j].Day {
+			return tidied[i].Day < tidied[j].Day
+		}
+		return tidied[i].Start < tidied[j].Start
+	})
+	return tidied, nil
+}
+
+// Suggest : the rides a student needs every week: to the GUC before their first class of each day, and back after their last one.
+func Suggest(classes []DB.Class) []DB.RideSubscription {
+	first, last := map[time.Weekday]int{}, map[time.Weekday]int{}
+	for _, class := range classes {
+		if start, found := first[class.Day]; !found || class.Start < start {
+			first[class.Day] = class.Start
+		}
+		if end, found := last[class.Day]; !found || class.End > end {
+			last[class.Day] = class.End
+		}
+	}
+	suggestions := []DB.RideSubscription{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		start, found := first[day]
+		if !found {
+			continue
+		}
+		suggestions = append(suggestions,
+			DB.RideSubscription{Day: day, FromGUC: false, Earliest: start - ArriveBefore - Window, Latest: start - ArriveBefore},
+			DB.RideSubscription{Day: day, FromGUC: true, Earliest: last[day], Latest: last[day] + Window},
+		)
+	}
+	return suggestions
+}
+
+// RideTime : the time of the day, in minutes after midnight, a suggested ride leaves at: as late as it can going to the GUC, and right after the classes leaving it.
+func RideTime(suggestion DB.RideSubscription) int {
+	if suggestion.FromGUC {
+		return suggestion.Earliest
+	}
+	return suggestion.Latest
+}
+
+// Next : the next time the suggested ride leaves after now, at the GUC.
+func Next(suggestion DB.RideSubscription, now time.Time) time.Time {
+	now = now.In(Timezone.Location)
+	minutes := RideTime(suggestion)
+	next := time.Date(now.Year(), now.Month(), now.Day(), minutes/60, minutes%60, 0, 0, now.Location())
+	next = next.AddDate(0, 0, (int(suggestion.Day)-int(next.Weekday())+7)%7)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+// SuggestionToString : describes a suggested ride (eg. "to the GUC before your 8:30am lecture on Sunday").
+func SuggestionToString(suggestion DB.RideSubscription) string {
+	if suggestion.FromGUC {
+		return "from the GUC after " + Clock(suggestion.Earliest) + " on " + suggestion.Day.String()
+	}
+	return "to the GUC before your " + Clock(suggestion.Latest+ArriveBefore) + " lecture on " + suggestion.Day.String()
+}
+
+// ClassesToString : describes the timetable, one class per line.
+func ClassesToString(classes []DB.Class) string {
+	str := ""
+	for _, class := range classes {
+		str += "\t" + class.Day.String() + " " + Clock(class.Start) + " to " + Clock(class.End)
+		if class.Name != "" {
+			str += ": " + class.Name
+		}
+		str += "\n"
+	}
+	return str
+}
@@ -0,0 +1,88 @@
+package Timetable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+func TestParseCSV(t *testing.T) {
+	classes, err := ParseCSV(strings.NewReader("Day,Slot,Course\nsunday,4,CSEN 701\nsun,1,MATH 401\ntue,8:30-10:00\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DB.Class{
+		{Day: time.Sunday, Start: 8*60 + 30, End: 10 * 60, Name: "MATH 401"},
+		{Day: time.Sunday, Start: 13*60 + 45, End: 15*60 + 15, Name: "CSEN 701"},
+		{Day: time.Tuesday, Start: 8*60 + 30, End: 10 * 60},
+	}
+	if !reflect.DeepEqual(classes, expected) {
+		t.Error("wrong classes", classes)
+	}
+
+	cases := map[string]string{
+		"bad day":     "someday,1\n",
+		"bad slot":    "sun,9\n",
+		"backwards":   "sun,10:00-8:30\n",
+		"few columns": "sun\n",
+		"empty":       "day,slot,course\n",
+	}
+	for name, timetable := range cases {
+		if _, err := ParseCSV(strings.NewReader(timetable)); err == nil {
+			t.Error("accepted a timetable with " + name)
+		}
+	}
+}
+
+func TestParseICS(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;TZID=Africa/Cairo:20260913T083000\r\nDTEND;TZID=Africa/Cairo:20260913T100000\r\nRRULE:FREQ=WEEKLY;BYDAY=SU,TU\r\nSUMMARY:CSEN 701\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261006\r\nSUMMARY:Holiday\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	classes, err := ParseICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes) != 2 || classes[0] != (DB.Class{Day: time.Sunday, Start: 8*60 + 30, End: 10 * 60, Name: "CSEN 701"}) || classes[1].Day != time.Tuesday {
+		t.Error("wrong classes", classes)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	classes := []DB.Class{
+		{Day: time.Sunday, Start: 8*60 + 30, End: 10 * 60},
+		{Day: time.Sunday, Start: 13*60 + 45, End: 15*60 + 45},
+	}
+	suggestions := Suggest(classes)
+	if len(suggestions) != 2 {
+		t.Fatal("expected 2 suggestions, got", suggestions)
+	}
+	if got := SuggestionToString(suggestions[0]); got != "to the GUC before your 8:30am lecture on Sunday" {
+		t.Error("wrong suggestion: " + got)
+	}
+	if got := SuggestionToString(suggestions[1]); got != "from the GUC after 3:45pm on Sunday" {
+		t.Error("wrong suggestion: " + got)
+	}
+
+	// Wednesday Oct 21, so the next Sunday is Oct 25.
+	now := time.Date(2026, 10, 21, 12, 0, 0, 0, Timezone.Location)
+	if next := Next(suggestions[0], now); !next.Equal(time.Date(2026, 10, 25, 8, 0, 0, 0, Timezone.Location)) {
+		t.Error("wrong next ride", next)
+	}
+	sunday := time.Date(2026, 10, 25, 12, 0, 0, 0, Timezone.Location)
+	if next := Next(suggestions[0], sunday); !next.Equal(time.Date(2026, 11, 1, 8, 0, 0, 0, Timezone.Location)) {
+		t.Error("wrong next ride after it left", next)
+	}
+	if next := Next(suggestions[1], sunday); !next.Equal(time.Date(2026, 10, 25, 15, 45, 0, 0, Timezone.Location)) {
+		t.Error("wrong next ride back", next)
+	}
+
+	// The clocks go back in Cairo on Oct 29, the ride still leaves at 8:00 there.
+	if next := Next(suggestions[0], time.Date(2026, 10, 28, 12, 0, 0, 0, time.UTC)); !next.Equal(time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC)) {
+		t.Error("wrong next ride after the clocks went back", next)
+	}
+}
@@ -0,0 +1,56 @@
+package Timezone
+
+import (
+	"log"
+	"os"
+	"time"
+	// The servers may not have the time zone database, so it is built in.
+	_ "time/tzdata"
+
+	"github.com/jinzhu/now"
+)
+
+// DefaultLocation : the time zone of the GUC, used when TIMEZONE is not set.
+const DefaultLocation = "Africa/Cairo"
+
+// Layout : how the times of the carpools are written to the students, with the abbreviation of the time zone on that day (eg. "Oct 30, 2026 at 8:00am (EET)").
+const Layout = "Jan 2, 2006 at 3:04pm (MST)"
+
+// DayLayout : how the days are written to the students.
+const DayLayout = "Jan 2, 2006"
+
+// Location : the time zone every time the students read or write is in, from the TIMEZONE environment variable (eg. "Africa/Cairo").
+var Location = Load(os.Getenv("TIMEZONE"))
+
+// Load : finds the time zone with the IANA name, or the GUC's if the name is empty or unknown.
+func Load(name string) *time.Location {
+	if name == "" {
+		name = DefaultLocation
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("unknown time zone %q, using %s: %s\n", name, DefaultLocation, err.Error())
+		location, _ = time.LoadLocation(DefaultLocation)
+	}
+	return location
+}
+
+// Now : the current time at the GUC.
+func Now() time.Time {
+	return time.Now().In(Location)
+}
+
+// Format : writes the time as the students read it, at the GUC.
+func Format(t time.Time) string {
+	return t.In(Location).Format(Layout)
+}
+
+// FormatDay : writes the day of the time at the GUC.
+func FormatDay(t time.Time) string {
+	return t.In(Location).Format(DayLayout)
+}
+
+// Parse : reads a time a student wrote, as a time at the GUC whatever the time zone of the server is.
+func Parse(text string) (time.Time, error) {
+	return now.New(Now()).Parse(text)
+}
@@ -0,0 +1,55 @@
+package Timezone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// The server may run in UTC, the times the students write are still in Cairo.
+	local := time.Local
+	time.Local = time.UTC
+	defer func() { time.Local = local }()
+
+	summer, err := Parse("2026-07-01 08:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !summer.Equal(time.Date(2026, 7, 1, 5, 30, 0, 0, time.UTC)) {
+		t.Error("wrong time in the summer", summer.UTC())
+	}
+	winter, err := Parse("2026-11-01 08:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !winter.Equal(time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC)) {
+		t.Error("wrong time in the winter", winter.UTC())
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cases := map[time.Time]string{
+		time.Date(2026, 7, 1, 5, 30, 0, 0, time.UTC):    "Jul 1, 2026 at 8:30am (EEST)",
+		time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC):   "Nov 1, 2026 at 8:30am (EET)",
+		time.Date(2026, 10, 29, 20, 30, 0, 0, time.UTC): "Oct 29, 2026 at 11:30pm (EEST)",
+		time.Date(2026, 10, 29, 21, 30, 0, 0, time.UTC): "Oct 29, 2026 at 11:30pm (EET)",
+		time.Date(2026, 4, 23, 22, 30, 0, 0, time.UTC):  "Apr 24, 2026 at 1:30am (EEST)",
+	}
+	for utc, expected := range cases {
+		if got := Format(utc); got != expected {
+			t.Error("expected " + expected + ", got " + got)
+		}
+	}
+	if got := FormatDay(time.Date(2026, 10, 5, 22, 30, 0, 0, time.UTC)); got != "Oct 6, 2026" {
+		t.Error("wrong day at the GUC: " + got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if Load("").String() != DefaultLocation || Load("Nowhere/Town").String() != DefaultLocation {
+		t.Error("expected to fall back to " + DefaultLocation)
+	}
+	if Load("Europe/Berlin").String() != "Europe/Berlin" {
+		t.Error("the configured time zone was not used")
+	}
+}
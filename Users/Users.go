@@ -0,0 +1,319 @@
+package Users
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+)
+
+// Store : keeps the profiles of the students.
+type Store interface {
+	Get(GUCID string) (DB.User, bool, error)
+	// Save saves the fields of the profile (eg. DB.UserPhone) and leaves the others as they are, or the whole profile without fields.
+	Save(user *DB.User, fields ...string) error
+	// Ensure creates the profile on the first login, and updates the name after that.
+	Ensure(GUCID string, name string) error
+	Subscribers(day time.Weekday, fromGUC bool, earliest int, latest int) ([]DB.User, error)
+	ByCalendarToken(token string) (DB.User, bool, error)
+}
+
+// DBStore : the store that keeps the profiles in the database.
+type DBStore struct{}
+
+// Get : loads the profile from the database.
+func (DBStore) Get(GUCID string) (DB.User, bool, error) { return DB.GetUser(GUCID) }
+
+// Save : saves the fields of the profile in the database.
+func (DBStore) Save(user *DB.User, fields ...string) error { return DB.SaveUser(user, fields...) }
+
+// Ensure : creates or renames the profile in the database.
+func (DBStore) Ensure(GUCID string, name string) error { return DB.EnsureUser(GUCID, name) }
+
+// Subscribers : loads the students from the database that want to hear about rides on the day, in the direction, leaving between the earliest and latest minutes.
+func (DBStore) Subscribers(day time.Weekday, fromGUC bool, earliest int, latest int) ([]DB.User, error) {
+	return DB.GetSubscribers(day, fromGUC, earliest, latest)
+}
+
+// ByCalendarToken : loads the profile from the database whose calendar feed has the token.
+func (DBStore) ByCalendarToken(token string) (DB.User, bool, error) {
+	return DB.GetUserByCalendarToken(token)
+}
+
+// MemoryStore : a store that keeps the profiles in memory, for tests and running locally.
+type MemoryStore struct {
+	mutex sync.Mutex
+	users map[string]DB.User
+}
+
+// NewMemoryStore : creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{users: map[string]DB.User{}}
+}
+
+// Get : returns the profile from memory.
+func (m *MemoryStore) Get(GUCID string) (DB.User, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	user, found := m.users[GUCID]
+	return user, found, nil
+}
+
+// Save : saves the fields of the profile in memory.
+func (m *MemoryStore) Save(user *DB.User, fields ...string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if len(fields) == 0 {
+		m.users[user.GUCID] = *user
+		return nil
+	}
+	saved, found := m.users[user.GUCID]
+	if !found {
+		saved = DB.User{GUCID: user.GUCID, Name: user.Name, Language: user.Language}
+	}
+	err := DB.SetUserFields(&saved, user, fields)
+	if err != nil {
+		return err
+	}
+	m.users[user.GUCID] = saved
+	return nil
+}
+
+// Ensure : creates or renames the profile in memory.
+func (m *MemoryStore) Ensure(GUCID string, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	user, found := m.users[GUCID]
+	if !found {
+		user = DB.User{GUCID: GUCID, Language: DB.LanguageEnglish}
+	}
+	user.Name = name
+	m.users[GUCID] = user
+	return nil
+}
+
+// Subscribers : returns the students in memory that want to hear about rides on the day, in the direction, leaving between the earliest and latest minutes.
+func (m *MemoryStore) Subscribers(day time.Weekday, fromGUC bool, earliest int, latest int) ([]DB.User, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	subscribers := []DB.User{}
+	for _, user := range m.users {
+		for _, subscription := range user.Subscriptions {
+			if subscription.Day == day && subscription.FromGUC == fromGUC && subscription.Earliest <= latest && earliest <= subscription.Latest {
+				subscribers = append(subscribers, user)
+				break
+			}
+		}
+	}
+	return subscribers, nil
+}
+
+// ByCalendarToken : returns the profile from memory whose calendar feed has the token.
+func (m *MemoryStore) ByCalendarToken(token string) (DB.User, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, user := range m.users {
+		if token != "" && user.CalendarToken == token {
+			return user, true, nil
+		}
+	}
+	return DB.User{}, false, nil
+}
+
+// The parts of a profile a student can change. The name comes from the roster, so it can't be changed.
+const (
+	FieldPhone     = "phone"
+	FieldLanguage  = "language"
+	FieldHomeArea  = "home area"
+	FieldVehicle   = "vehicle"
+	FieldEmails    = "emails"
+	FieldReminders = "reminders"
+)
+
+// Fields : the parts of a profile a student can change, in the order they are offered.
+var Fields = []string{FieldPhone, FieldLanguage, FieldHomeArea, FieldVehicle, FieldEmails, FieldReminders}
+
+// fieldWords : the words that pick each field in a message. Reminders are checked before emails, since reminders are emails too.
+var fieldWords = []struct {
+	field string
+	words *regexp.Regexp
+}{
+	{FieldPhone, regexp.MustCompile(`\b(phone|mobile|number)`)},
+	{FieldLanguage, regexp.MustCompile(`\b(language|lang)\b`)},
+	{FieldHomeArea, regexp.MustCompile(`\b(home|area|live)`)},
+	{FieldVehicle, regexp.MustCompile(`\b(vehicle|car)s?\b`)},
+	{FieldReminders, regexp.MustCompile(`\breminder`)},
+	{FieldEmails, regexp.MustCompile(`\be-?mail`)},
+}
+
+// Paths : where each field is saved in the profile, for saving only the field that changed.
+var Paths = map[string]string{
+	FieldPhone:     DB.UserPhone,
+	FieldLanguage:  DB.UserLanguage,
+	FieldHomeArea:  DB.UserHomeArea,
+	FieldVehicle:   DB.UserVehicles,
+	FieldEmails:    DB.UserEmailOptOut,
+	FieldReminders: DB.UserRemindersOptOut,
+}
+
+// FieldIn : finds the field a message is about.
+func FieldIn(message string) (string, bool) {
+	comparable := strings.ToLower(message)
+	for _, candidate := range fieldWords {
+		if candidate.words.MatchString(comparable) {
+			return candidate.field, true
+		}
+	}
+	return "", false
+}
+
+// Prompt : what to ask the student for a new value of the field.
+func Prompt(field string) string {
+	switch field {
+	case FieldPhone:
+		return "What is your phone number? (ex. '01012345678')"
+	case FieldLanguage:
+		return "Which language would you like me to use, English or Arabic?"
+	case FieldHomeArea:
+		return "Which area do you live in? (ex. 'Maadi')"
+	case FieldVehicle:
+		return VehiclePrompt + " You can also remove one by typing 'remove' and its plate."
+	case FieldEmails:
+		return "Would you like me to email you when something happens to your carpools? (on or off)"
+	case FieldReminders:
+		return "Would you like me to email you a reminder before your rides? (on or off)"
+	}
+	return ""
+}
+
+// phoneFormat : an Egyptian mobile number, or an international one starting with +.
+var phoneFormat = regexp.MustCompile(`^(01[0-9]{9}|\+[0-9]{8,15})$`)
+
+// Set : validates the value and sets the field of the profile to it.
+func Set(user *DB.User, field string, value string) error {
+	value = strings.TrimSpace(value)
+	comparable := strings.ToLower(value)
+	switch field {
+	case FieldPhone:
+		phone := strings.NewReplacer(" ", "", "-", "").Replace(value)
+		if !phoneFormat.MatchString(phone) {
+			return Problem.New("problem.phone", nil, "this is not a phone number. "+Prompt(field))
+		}
+		user.Phone = phone
+	case FieldLanguage:
+		switch {
+		case strings.HasPrefix(comparable, "en"):
+			user.Language = DB.LanguageEnglish
+		case strings.HasPrefix(comparable, "ar") || value == "عربي":
+			user.Language = DB.LanguageArabic
+		default:
+			return Problem.New("problem.language", nil, "I can only speak English or Arabic. "+Prompt(field))
+		}
+	case FieldHomeArea:
+		if value == "" || len(value) > 100 {
+			return Problem.New("problem.homeArea", nil, "please keep it between 1 and 100 characters. "+Prompt(field))
+		}
+		user.HomeArea = value
+	case FieldVehicle:
+		if strings.HasPrefix(comparable, "remove ") {
+			return RemoveVehicle(user, value[len("remove "):])
+		}
+		vehicle, err := ParseVehicle(value)
+		if err != nil {
+			return err
+		}
+		AddVehicle(user, vehicle)
+	case FieldEmails, FieldReminders:
+		var optOut bool
+		switch comparable {
+		case "on", "yes", "y":
+			optOut = false
+		case "off", "no", "n":
+			optOut = true
+		default:
+			return Problem.New("problem.onOff", nil, "please answer with on or off. "+Prompt(field))
+		}
+		if field == FieldEmails {
+			user.Notifications.EmailOptOut = optOut
+		} else {
+			user.Notifications.RemindersOptOut = optOut
+		}
+	default:
+		return Problem.New("problem.field", map[string]interface{}{"Field": field}, "you can't change your "+field)
+	}
+	return nil
+}
+
+// VehiclePrompt : how to describe a car.
+const VehiclePrompt = "Tell me the make, colour, plate and number of seats for passengers of your car. (ex. 'Hyundai Elantra, white, ABC 123, 4')"
+
+// MaxCapacity : the most passengers a car can be registered for.
+const MaxCapacity = 8
+
+// ParseVehicle : reads a car described as "make, colour, plate, capacity".
+func ParseVehicle(text string) (DB.Vehicle, error) {
+	parts := strings.Split(text, ",")
+	if len(parts) != 4 {
+		return DB.Vehicle{}, Problem.New("problem.vehicleDetails", nil, "I need all four details of your car. "+VehiclePrompt)
+	}
+	for i := range parts {
+		parts[i] = strings.Join(strings.Fields(parts[i]), " ")
+		if parts[i] == "" || len(parts[i]) > 50 {
+			return DB.Vehicle{}, Problem.New("problem.vehicleDetails", nil, "I need all four details of your car. "+VehiclePrompt)
+		}
+	}
+	capacity, err := strconv.Atoi(parts[3])
+	if err != nil || capacity < 1 || capacity > MaxCapacity {
+		return DB.Vehicle{}, Problem.New("problem.capacity", map[string]interface{}{"Max": MaxCapacity}, "a car can take from 1 to "+strconv.Itoa(MaxCapacity)+" passengers, not counting the driver. "+VehiclePrompt)
+	}
+	return DB.Vehicle{Make: parts[0], Colour: strings.ToLower(parts[1]), Plate: strings.ToUpper(parts[2]), Capacity: capacity}, nil
+}
+
+// AddVehicle : adds the car to the profile, replacing the car with the same plate if there is one.
+func AddVehicle(user *DB.User, vehicle DB.Vehicle) {
+	for i := range user.Vehicles {
+		if samePlate(user.Vehicles[i].Plate, vehicle.Plate) {
+			user.Vehicles[i] = vehicle
+			return
+		}
+	}
+	user.Vehicles = append(user.Vehicles, vehicle)
+}
+
+// RemoveVehicle : removes the car with the plate from the profile.
+func RemoveVehicle(user *DB.User, plate string) error {
+	for i := range user.Vehicles {
+		if samePlate(user.Vehicles[i].Plate, plate) {
+			user.Vehicles = append(user.Vehicles[:i], user.Vehicles[i+1:]...)
+			return nil
+		}
+	}
+	return Problem.New("problem.noVehicle", map[string]interface{}{"Plate": strings.TrimSpace(plate)}, "you don't have a car with the plate "+strings.TrimSpace(plate))
+}
+
+// FindVehicle : finds the car the message picks from the profile, by its number in the list or by its plate.
+func FindVehicle(vehicles []DB.Vehicle, message string) (DB.Vehicle, bool) {
+	message = strings.TrimSpace(message)
+	if number, err := strconv.Atoi(message); err == nil && number >= 1 && number <= len(vehicles) {
+		return vehicles[number-1], true
+	}
+	for _, vehicle := range vehicles {
+		if samePlate(vehicle.Plate, message) || strings.Contains(normalizePlate(message), normalizePlate(vehicle.Plate)) {
+			return vehicle, true
+		}
+	}
+	return DB.Vehicle{}, false
+}
+
+// samePlate : checks if two plates are the same, ignoring case and spaces.
+func samePlate(a string, b string) bool {
+	return normalizePlate(a) == normalizePlate(b)
+}
+
+func normalizePlate(plate string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(plate), ""))
+}
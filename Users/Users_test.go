@@ -0,0 +1,127 @@
+package Users
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+)
+
+func TestFieldIn(t *testing.T) {
+	cases := map[string]string{
+		"my phone number":         FieldPhone,
+		"Language":                FieldLanguage,
+		"where I live":            FieldHomeArea,
+		"my car":                  FieldVehicle,
+		"email reminders":         FieldReminders,
+		"E-mails":                 FieldEmails,
+		"the carpool I'm driving": "",
+	}
+	for message, expected := range cases {
+		field, _ := FieldIn(message)
+		if field != expected {
+			t.Errorf("%q should be about %q, not %q", message, expected, field)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	user := DB.User{}
+	valid := map[string]string{
+		FieldPhone:     "010-1234-5678",
+		FieldLanguage:  "Arabic",
+		FieldHomeArea:  "Maadi",
+		FieldVehicle:   "Hyundai Elantra, White, abc 123, 4",
+		FieldEmails:    "off",
+		FieldReminders: "OFF",
+	}
+	for field, value := range valid {
+		if err := Set(&user, field, value); err != nil {
+			t.Error(field, err)
+		}
+	}
+	expected := DB.User{Phone: "01012345678", Language: DB.LanguageArabic, HomeArea: "Maadi", Vehicles: []DB.Vehicle{{Make: "Hyundai Elantra", Colour: "white", Plate: "ABC 123", Capacity: 4}}, Notifications: DB.NotificationSettings{EmailOptOut: true, RemindersOptOut: true}}
+	if !reflect.DeepEqual(user, expected) {
+		t.Error("wrong profile", user)
+	}
+
+	invalid := map[string]string{
+		FieldPhone:    "12345",
+		FieldLanguage: "French",
+		FieldHomeArea: " ",
+		FieldEmails:   "maybe",
+		FieldVehicle:  "Hyundai Elantra, white, ABC 123, 12",
+		"name":        "Someone Else",
+	}
+	for field, value := range invalid {
+		if err := Set(&user, field, value); err == nil {
+			t.Errorf("accepted %q as the %s", value, field)
+		}
+	}
+	if !reflect.DeepEqual(user, expected) {
+		t.Error("an invalid value changed the profile", user)
+	}
+}
+
+func TestVehicles(t *testing.T) {
+	user := DB.User{}
+	Set(&user, FieldVehicle, "Hyundai Elantra, white, ABC 123, 4")
+	Set(&user, FieldVehicle, "Kia Picanto, red, XYZ 9, 3")
+	// The same plate replaces the car.
+	Set(&user, FieldVehicle, "Hyundai Elantra, silver, abc123, 4")
+	if len(user.Vehicles) != 2 || user.Vehicles[0].Colour != "silver" {
+		t.Fatal("wrong cars", user.Vehicles)
+	}
+	if vehicle, found := FindVehicle(user.Vehicles, "2"); !found || vehicle.Plate != "XYZ 9" {
+		t.Error("could not pick a car by its number", vehicle)
+	}
+	if vehicle, found := FindVehicle(user.Vehicles, "the one with plate xyz 9"); !found || vehicle.Plate != "XYZ 9" {
+		t.Error("could not pick a car by its plate", vehicle)
+	}
+	if _, found := FindVehicle(user.Vehicles, "3"); found {
+		t.Error("picked a car that is not there")
+	}
+	if err := Set(&user, FieldVehicle, "remove XYZ 9"); err != nil || len(user.Vehicles) != 1 {
+		t.Error("could not remove a car", user.Vehicles, err)
+	}
+	if err := Set(&user, FieldVehicle, "remove XYZ 9"); err == nil {
+		t.Error("removed a car that is not there")
+	}
+}
+
+// Two changes made at the same time to different fields of a profile are both kept.
+func TestSaveFields(t *testing.T) {
+	store := NewMemoryStore()
+	store.Save(&DB.User{GUCID: "34-1234", Name: "Ahmed Ali", Language: DB.LanguageEnglish, Phone: "01012345678"})
+	first, _, _ := store.Get("34-1234")
+	second, _, _ := store.Get("34-1234")
+
+	if err := Set(&first, FieldEmails, "off"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(&first, Paths[FieldEmails]); err != nil {
+		t.Fatal(err)
+	}
+	second.CalendarToken = "token"
+	second.Subscriptions = []DB.RideSubscription{{Day: 0, Earliest: 480, Latest: 540}}
+	if err := store.Save(&second, DB.UserCalendarToken, DB.UserSubscriptions); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _, _ := store.Get("34-1234")
+	if !saved.Notifications.EmailOptOut || saved.Notifications.RemindersOptOut {
+		t.Error("the email setting was lost", saved.Notifications)
+	}
+	if saved.CalendarToken != "token" || !reflect.DeepEqual(saved.Subscriptions, second.Subscriptions) {
+		t.Error("the calendar token or the subscriptions were lost", saved)
+	}
+	if saved.Phone != "01012345678" || saved.Name != "Ahmed Ali" {
+		t.Error("the other fields were changed", saved)
+	}
+
+	// A profile saved for the first time keeps its name and language.
+	store.Save(&DB.User{GUCID: "34-5678", Name: "Sara Omar", Language: DB.LanguageArabic, HomeArea: "Maadi"}, DB.UserHomeArea)
+	if saved, found, _ := store.Get("34-5678"); !found || saved.Name != "Sara Omar" || saved.Language != DB.LanguageArabic || saved.HomeArea != "Maadi" {
+		t.Error("the new profile was not saved", saved)
+	}
+}
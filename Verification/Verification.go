@@ -0,0 +1,194 @@
+package Verification
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+)
+
+// Errors returned when checking a code.
+var (
+	ErrNoCode          = Problem.New("problem.noCode", nil, "I didn't send you a code yet, or you already used it")
+	ErrExpired         = Problem.New("problem.codeExpired", nil, "this code has expired, please log in again to get a new one")
+	ErrTooManyAttempts = Problem.New("problem.codeAttempts", nil, "too many wrong codes, please log in again to get a new one")
+	ErrWrongCode       = Problem.New("problem.wrongCode", nil, "this code is not correct")
+	ErrTooManyCodes    = Problem.New("problem.tooManyCodes", nil, "I already sent you too many codes, please wait a bit before asking for a new one")
+)
+
+// Store : keeps the verification codes where all the server instances can see them.
+type Store interface {
+	Get(GUCID string) (DB.VerificationCode, bool, error)
+	Save(code *DB.VerificationCode) error
+}
+
+// DBStore : the store that keeps the codes in the database.
+type DBStore struct{}
+
+// Get : loads the code from the database.
+func (DBStore) Get(GUCID string) (DB.VerificationCode, bool, error) {
+	return DB.GetVerificationCode(GUCID)
+}
+
+// Save : saves the code in the database.
+func (DBStore) Save(code *DB.VerificationCode) error { return DB.SaveVerificationCode(code) }
+
+// MemoryStore : a store that keeps the codes in memory, for tests and running locally.
+type MemoryStore struct {
+	mutex sync.Mutex
+	codes map[string]DB.VerificationCode
+}
+
+// NewMemoryStore : creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{codes: map[string]DB.VerificationCode{}}
+}
+
+// Get : returns the code from memory.
+func (m *MemoryStore) Get(GUCID string) (DB.VerificationCode, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	code, found := m.codes[GUCID]
+	return code, found, nil
+}
+
+// Save : saves the code in memory.
+func (m *MemoryStore) Save(code *DB.VerificationCode) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.codes[code.GUCID] = *code
+	return nil
+}
+
+// AddressFromID : the GUC email address of a student, derived from their GUC ID.
+func AddressFromID(GUCID string) (string, error) {
+	return GUCID + "@" + Notifier.EmailDomain, nil
+}
+
+// KeyFromEnv : the server secret the codes are hashed with, from VERIFICATION_SECRET. All the server instances need the same one. If it is not set, a random one is made, so only this instance can check the codes it sends.
+func KeyFromEnv() []byte {
+	if secret := os.Getenv("VERIFICATION_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	log.Println("VERIFICATION_SECRET is not set, so only this server instance can check the codes it sends")
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+// Verifier : emails one-time codes to students and checks the codes they send back.
+type Verifier struct {
+	Store       Store
+	Mailer      Notifier.Mailer
+	Address     func(GUCID string) (string, error) // where to send the code of a GUC ID
+	Key         []byte                             // the server secret the codes are hashed with
+	TTL         time.Duration                      // how long a code is valid
+	MaxAttempts int                                // wrong guesses allowed per code
+	MaxSends    int                                // codes that can be sent per SendWindow
+	SendWindow  time.Duration
+}
+
+// New : creates a verifier that hashes the codes with the key, with the default limits.
+func New(store Store, mailer Notifier.Mailer, address func(GUCID string) (string, error), key []byte) *Verifier {
+	return &Verifier{
+		Store:       store,
+		Mailer:      mailer,
+		Address:     address,
+		Key:         key,
+		TTL:         10 * time.Minute,
+		MaxAttempts: 5,
+		MaxSends:    3,
+		SendWindow:  time.Hour,
+	}
+}
+
+// SendCode : emails a new code to the student and returns the address it was sent to.
+func (v *Verifier) SendCode(GUCID string) (string, error) {
+	GUCID = strings.ToLower(GUCID)
+	address, err := v.Address(GUCID)
+	if err != nil {
+		return "", err
+	}
+	code, _, err := v.Store.Get(GUCID)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	sends := make([]time.Time, 0)
+	for _, sent := range code.Sends {
+		if now.Sub(sent) < v.SendWindow {
+			sends = append(sends, sent)
+		}
+	}
+	if len(sends) >= v.MaxSends {
+		return "", ErrTooManyCodes
+	}
+
+	number, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	secret := fmt.Sprintf("%06d", number.Int64())
+	code = DB.VerificationCode{
+		GUCID:     GUCID,
+		Hash:      v.hash(GUCID, secret),
+		ExpiresAt: now.Add(v.TTL),
+		Sends:     append(sends, now),
+	}
+	err = v.Store.Save(&code)
+	if err != nil {
+		return "", err
+	}
+	body := "Your GUC Carpool code is " + secret + ". It is valid for " + fmt.Sprint(int(v.TTL/time.Minute)) + " minutes.\n\nIf you didn't try to log in, you can ignore this email."
+	return address, v.Mailer.Send(address, "Your GUC Carpool code", body)
+}
+
+// Check : checks the code the student sent back. Each code can only be used once.
+func (v *Verifier) Check(GUCID string, secret string) error {
+	GUCID = strings.ToLower(GUCID)
+	code, found, err := v.Store.Get(GUCID)
+	if err != nil {
+		return err
+	}
+	if !found || code.Hash == "" {
+		return ErrNoCode
+	}
+	if time.Now().After(code.ExpiresAt) {
+		return ErrExpired
+	}
+	if code.Attempts >= v.MaxAttempts {
+		return ErrTooManyAttempts
+	}
+	if subtle.ConstantTimeCompare([]byte(code.Hash), []byte(v.hash(GUCID, strings.TrimSpace(secret)))) != 1 {
+		code.Attempts++
+		err = v.Store.Save(&code)
+		if err != nil {
+			return err
+		}
+		if code.Attempts >= v.MaxAttempts {
+			return ErrTooManyAttempts
+		}
+		return ErrWrongCode
+	}
+	code.Hash = ""
+	return v.Store.Save(&code)
+}
+
+// hash : only a hash of the code is stored, so reading the database is not enough to log in. It is keyed with the server secret, so the million possible codes can't be tried against it without the server.
+func (v *Verifier) hash(GUCID string, secret string) string {
+	mac := hmac.New(sha256.New, v.Key)
+	mac.Write([]byte(GUCID + ":" + secret))
+	return hex.EncodeToString(mac.Sum(nil))
+}
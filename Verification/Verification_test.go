@@ -0,0 +1,124 @@
+package Verification
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+// testKey : the server secret of the tests.
+var testKey = []byte("test secret")
+
+// fakeMailer : a mailer that keeps the emails instead of sending them.
+type fakeMailer struct {
+	to   []string
+	body []string
+}
+
+func (m *fakeMailer) Send(to string, subject string, body string) error {
+	m.to = append(m.to, to)
+	m.body = append(m.body, body)
+	return nil
+}
+
+// Function that reads the code out of the last email.
+func (m *fakeMailer) lastCode(t *testing.T) string {
+	if len(m.body) == 0 {
+		t.Fatal("no email was sent")
+	}
+	return regexp.MustCompile(`[0-9]{6}`).FindString(m.body[len(m.body)-1])
+}
+
+func TestSendAndCheckCode(t *testing.T) {
+	mailer := &fakeMailer{}
+	verifier := New(NewMemoryStore(), mailer, AddressFromID, testKey)
+
+	address, err := verifier.SendCode("34-1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != "34-1234@student.guc.edu.eg" || mailer.to[0] != address {
+		t.Error("code was sent to the wrong address", address, mailer.to)
+	}
+	code := mailer.lastCode(t)
+	if err = verifier.Check("34-1234", code); err != nil {
+		t.Error("correct code was refused", err)
+	}
+	if err = verifier.Check("34-1234", code); err != ErrNoCode {
+		t.Error("a code could be used twice", err)
+	}
+}
+
+func TestCodeOfAnotherStudent(t *testing.T) {
+	mailer := &fakeMailer{}
+	verifier := New(NewMemoryStore(), mailer, AddressFromID, testKey)
+	verifier.SendCode("34-1234")
+	code := mailer.lastCode(t)
+	if err := verifier.Check("34-9999", code); err != ErrNoCode {
+		t.Error("the code of one student worked for another", err)
+	}
+}
+
+// The stored hash is keyed, so the code can't be checked without the server secret.
+func TestCodesNeedTheKey(t *testing.T) {
+	mailer := &fakeMailer{}
+	store := NewMemoryStore()
+	verifier := New(store, mailer, AddressFromID, testKey)
+	verifier.SendCode("34-1234")
+	code := mailer.lastCode(t)
+	other := New(store, mailer, AddressFromID, []byte("another secret"))
+	if err := other.Check("34-1234", code); err != ErrWrongCode {
+		t.Error("the code was checked with another key", err)
+	}
+	if err := verifier.Check("34-1234", code); err != nil {
+		t.Error("correct code was refused", err)
+	}
+}
+
+func TestWrongAttemptsAreLimited(t *testing.T) {
+	mailer := &fakeMailer{}
+	verifier := New(NewMemoryStore(), mailer, AddressFromID, testKey)
+	verifier.SendCode("34-1234")
+	code := mailer.lastCode(t)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	for i := 1; i < verifier.MaxAttempts; i++ {
+		if err := verifier.Check("34-1234", wrong); err != ErrWrongCode {
+			t.Fatal("expected a wrong code error", err)
+		}
+	}
+	if err := verifier.Check("34-1234", wrong); err != ErrTooManyAttempts {
+		t.Error("expected too many attempts on the last guess", err)
+	}
+	if err := verifier.Check("34-1234", code); err != ErrTooManyAttempts {
+		t.Error("the right code worked after too many wrong ones", err)
+	}
+}
+
+func TestExpiredCode(t *testing.T) {
+	mailer := &fakeMailer{}
+	verifier := New(NewMemoryStore(), mailer, AddressFromID, testKey)
+	verifier.TTL = -time.Second
+	verifier.SendCode("34-1234")
+	if err := verifier.Check("34-1234", mailer.lastCode(t)); err != ErrExpired {
+		t.Error("expired code was accepted", err)
+	}
+}
+
+func TestSendsAreLimited(t *testing.T) {
+	mailer := &fakeMailer{}
+	verifier := New(NewMemoryStore(), mailer, AddressFromID, testKey)
+	for i := 0; i < verifier.MaxSends; i++ {
+		if _, err := verifier.SendCode("34-1234"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := verifier.SendCode("34-1234"); err != ErrTooManyCodes {
+		t.Error("expected too many codes", err)
+	}
+	if len(mailer.to) != verifier.MaxSends {
+		t.Error("sent more codes than allowed", len(mailer.to))
+	}
+}
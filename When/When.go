@@ -0,0 +1,451 @@
+package When
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Problem"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+// Expression : the time of a ride a student wrote in the chat. A window (eg. "between 7:30 and 8:15") has an end, a moment does not.
+type Expression struct {
+	Start time.Time
+	End   time.Time
+	// Ambiguous is set when a part of the time was guessed (eg. "at 8" could be 8am or 8pm), so the student should confirm it.
+	Ambiguous bool
+}
+
+// Window : checks the expression is a window of time rather than a moment.
+func (e Expression) Window() bool {
+	return !e.End.IsZero()
+}
+
+// Latest : the end of the window, or the moment.
+func (e Expression) Latest() time.Time {
+	if e.Window() {
+		return e.End
+	}
+	return e.Start
+}
+
+// Words and phrases of Egyptian Arabic, in Arabic letters or in Franco-Arabic, and the English they are read as. Longer phrases come before the words they contain.
+var phrases = [][2]string{
+	{"بعد بكرة", "overmorrow"}, {"بعد بكره", "overmorrow"}, {"ba3d bokra", "overmorrow"}, {"ba3d bukra", "overmorrow"}, {"day after tomorrow", "overmorrow"},
+	{"بكرة", "tomorrow"}, {"بكره", "tomorrow"}, {"bokra", "tomorrow"}, {"bukra", "tomorrow"}, {"bokrah", "tomorrow"}, {"tmrw", "tomorrow"}, {"tmr", "tomorrow"}, {"tomorow", "tomorrow"}, {"tommorow", "tomorrow"}, {"tommorrow", "tomorrow"},
+	{"النهارده", "today"}, {"النهاردة", "today"}, {"انهارده", "today"}, {"ennaharda", "today"}, {"enharda", "today"}, {"el naharda", "today"}, {"elnaharda", "today"}, {"naharda", "today"},
+	{"الليلة", "tonight"}, {"الليله", "tonight"}, {"el leila", "tonight"}, {"elleila", "tonight"}, {"tonite", "tonight"},
+	{"this morning", "today morning"}, {"this afternoon", "today afternoon"}, {"this evening", "today evening"},
+	{"بعد الضهر", "afternoon"}, {"بعد الظهر", "afternoon"}, {"ba3d el dohr", "afternoon"}, {"ba3d el zohr", "afternoon"}, {"ba3d eldohr", "afternoon"},
+	{"الصبح", "morning"}, {"الصباح", "morning"}, {"صباحا", "morning"}, {"صباحاً", "morning"}, {"el sob7", "morning"}, {"elsob7", "morning"}, {"sob7", "morning"}, {"el so7", "morning"}, {"saba7an", "morning"},
+	{"الضهر", "noon"}, {"الظهر", "noon"}, {"el dohr", "noon"}, {"eldohr", "noon"}, {"dohr", "noon"}, {"el zohr", "noon"}, {"zohr", "noon"},
+	{"العصر", "afternoon"}, {"el 3asr", "afternoon"}, {"3asr", "afternoon"},
+	{"المغرب", "evening"}, {"مساء", "evening"}, {"مساءا", "evening"}, {"مساءً", "evening"}, {"el maghrib", "evening"}, {"maghrib", "evening"}, {"masa2an", "evening"},
+	{"بالليل", "night"}, {"بليل", "night"}, {"bel leil", "night"}, {"bel lel", "night"}, {"bel leel", "night"}, {"bellel", "night"}, {"belel", "night"}, {"bllel", "night"},
+	{"نص ساعة", "30 minutes"}, {"نص ساعه", "30 minutes"}, {"nos sa3a", "30 minutes"}, {"nus sa3a", "30 minutes"}, {"half an hour", "30 minutes"}, {"half hour", "30 minutes"},
+	{"ربع ساعة", "15 minutes"}, {"ربع ساعه", "15 minutes"}, {"rob3 sa3a", "15 minutes"}, {"ro3 sa3a", "15 minutes"},
+	{"an hour and a half", "90 minutes"}, {"hour and a half", "90 minutes"}, {"sa3a w nos", "90 minutes"},
+	{"ساعتين", "2 hours"}, {"sa3teen", "2 hours"}, {"sa3tein", "2 hours"}, {"a couple of hours", "2 hours"},
+	{"بعد ساعة", "in 1 hour"}, {"بعد ساعه", "in 1 hour"}, {"ba3d sa3a", "in 1 hour"}, {"an hour", "1 hour"}, {"one hour", "1 hour"}, {"a hour", "1 hour"},
+	{"الساعة", "at"}, {"الساعه", "at"}, {"el sa3a", "at"}, {"elsa3a", "at"},
+	{"ساعات", "hours"}, {"sa3at", "hours"}, {"ساعة", "hour"}, {"ساعه", "hour"}, {"sa3a", "hour"}, {"hrs", "hours"}, {"hr", "hour"},
+	{"دقيقة", "minutes"}, {"دقيقه", "minutes"}, {"دقايق", "minutes"}, {"دقائق", "minutes"}, {"da2ee2a", "minutes"}, {"d2i2a", "minutes"}, {"da2aye2", "minutes"}, {"d2aye2", "minutes"}, {"mins", "minutes"}, {"min", "minutes"},
+	{"ونص", "and half"}, {"w nos", "and half"}, {"we nos", "and half"}, {"wi nos", "and half"}, {"wnos", "and half"},
+	{"وربع", "and quarter"}, {"w rob3", "and quarter"}, {"we rob3", "and quarter"}, {"wi rob3", "and quarter"},
+	{"الا ربع", "less quarter"}, {"إلا ربع", "less quarter"}, {"ella rob3", "less quarter"}, {"ela rob3", "less quarter"}, {"illa rob3", "less quarter"},
+	{"الأسبوع الجاي", "next week"}, {"الاسبوع الجاي", "next week"}, {"el esbo3 el gay", "next week"}, {"elesbo3 elgay", "next week"}, {"esbo3 el gay", "next week"},
+	{"الجاي", "next"}, {"الجاية", "next"}, {"الجايه", "next"}, {"el gay", "next"}, {"elgay", "next"}, {"el gaya", "next"}, {"elgaya", "next"},
+	{"الأحد", "sunday"}, {"الاحد", "sunday"}, {"الحد", "sunday"}, {"el 7ad", "sunday"}, {"el had", "sunday"}, {"el ahad", "sunday"}, {"7ad", "sunday"},
+	{"الاثنين", "monday"}, {"الإثنين", "monday"}, {"الاتنين", "monday"}, {"el etnen", "monday"}, {"el itnen", "monday"}, {"el etnein", "monday"}, {"etnen", "monday"}, {"itnein", "monday"},
+	{"الثلاثاء", "tuesday"}, {"التلات", "tuesday"}, {"التلاتاء", "tuesday"}, {"el talat", "tuesday"}, {"el talata", "tuesday"}, {"talat", "tuesday"},
+	{"الأربعاء", "wednesday"}, {"الاربعاء", "wednesday"}, {"الاربع", "wednesday"}, {"el arba3", "wednesday"}, {"el orba3", "wednesday"}, {"arba3", "wednesday"},
+	{"الخميس", "thursday"}, {"el khamis", "thursday"}, {"el 5amis", "thursday"}, {"khamis", "thursday"}, {"5amis", "thursday"},
+	{"الجمعة", "friday"}, {"الجمعه", "friday"}, {"el gom3a", "friday"}, {"el gum3a", "friday"}, {"gom3a", "friday"},
+	{"السبت", "saturday"}, {"el sabt", "saturday"}, {"sabt", "saturday"},
+	{"بعد", "in"}, {"ba3d", "in"}, {"بين", "between"}, {"ben", "between"}, {"bein", "between"}, {"من", "from"}, {"men", "from"},
+	{"و", "and"}, {"w", "and"}, {"we", "and"}, {"لحد", "to"}, {"le", "to"}, {"l7ad", "to"}, {"le7ad", "to"}, {"till", "to"}, {"until", "to"},
+	{"o'clock", ""}, {"oclock", ""}, {"el", ""}, {"ya", ""}, {"يوم", ""}, {"on", ""}, {"the", ""},
+}
+
+var arabicDigits = strings.NewReplacer("٠", "0", "١", "1", "٢", "2", "٣", "3", "٤", "4", "٥", "5", "٦", "6", "٧", "7", "٨", "8", "٩", "9", "a.m.", "am", "p.m.", "pm", "a.m", "am", "p.m", "pm", "–", "-", "—", "-", "±", " give or take ", "+/-", " give or take ", "+-", " give or take ")
+
+var (
+	punctuation = regexp.MustCompile(`[,،?؟!;]|\.(\s|$)`)
+	clock       = `(\d{1,2})(?:[:.](\d{1,2}))?\s*(am|pm)?`
+	margin      = regexp.MustCompile(`\b(?:give\s+or\s+take|plus\s+or\s+minus)\s+(\d+)\s*(hours?|minutes?)?`)
+	relative    = regexp.MustCompile(`\bin\s+(\d+(?:\.\d+)?)\s*(hours?|minutes?)\b`)
+	isoDate     = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	slashDate   = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})(?:/(\d{2}|\d{4}))?\b`)
+	monthDay    = regexp.MustCompile(`\b(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?\s+(\d{1,2})(?:st|nd|rd|th)?(?:\s+(\d{4}))?\b`)
+	dayMonth    = regexp.MustCompile(`\b(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?(?:\s+(\d{4}))?\b`)
+	weekday     = regexp.MustCompile(`\b(next\s+week\s+)?(next\s+)?(sun(?:day)?|mon(?:day)?|tue(?:s|sday)?|wed(?:nesday)?|thu(?:rs|rsday)?|fri(?:day)?|sat(?:urday)?)\b(\s+next\s+week)?`)
+	fractions   = regexp.MustCompile(`\b(\d{1,2})\s+and\s+(half|quarter)\b|\b(\d{1,2})\s+less\s+quarter\b|\bhalf\s+past\s+(\d{1,2})\b|\bquarter\s+(past|to)\s+(\d{1,2})\b`)
+	window      = regexp.MustCompile(`(?:\b(?:between|from)\s+)?(?:\bat\s+)?\b` + clock + `\s*(?:\band\b|\bto\b|-)\s*` + clock + `\b`)
+	moment      = regexp.MustCompile(`\b` + clock + `\b`)
+	dayWords    = regexp.MustCompile(`\b(today|tonight|tomorrow|overmorrow|next\s+week)\b`)
+	partOfDay   = regexp.MustCompile(`\b(morning|noon|afternoon|evening|night|tonight)\b`)
+	firstWord   = regexp.MustCompile(`^(?:\d{1,2}(?:[:.]\d{1,2})?(?:am|pm)?\b|\d{4}-|\d{1,2}/|(?:today|tonight|tomorrow|overmorrow|next|this|at|in|between|anytime|around|morning|noon|afternoon|evening|night|half|quarter|give|plus|sun(?:day)?|mon(?:day)?|tue(?:s|sday)?|wed(?:nesday)?|thu(?:rs|rsday)?|fri(?:day)?|sat(?:urday)?)\b|(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?\s+\d)`)
+)
+
+var months = map[string]time.Month{"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April, "may": time.May, "jun": time.June, "jul": time.July, "aug": time.August, "sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December}
+
+var weekdays = map[string]time.Weekday{"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday}
+
+// The time a part of the day stands for when the student did not write the hour (eg. "tomorrow morning").
+var partTimes = map[string]int{"morning": 8 * 60, "noon": 12 * 60, "afternoon": 15 * 60, "evening": 18 * 60, "night": 20 * 60}
+
+// A time of day as the student wrote it (eg. "7:45pm").
+type clockTime struct {
+	hour, minute int
+	meridiem     string
+	// exact is set when the hour can only be read one way, like "19:30" or "08:30".
+	exact bool
+}
+
+// Parse : reads the time of a ride the student wrote, relative to now (eg. "tomorrow 8am", "sunday at 7:45", "in 2 hours", "next week monday morning", "bokra 8 el sob7", "between 7:30 and 8:15" or "8am give or take 15 minutes"). The times are at the GUC.
+func Parse(text string, now time.Time) (Expression, error) {
+	normalized := normalize(text)
+	// "give or take 15 minutes" makes a window around the time.
+	var shift time.Duration
+	if parts := margin.FindStringSubmatch(normalized); parts != nil {
+		amount, _ := strconv.Atoi(parts[1])
+		shift = time.Duration(amount) * time.Minute
+		if strings.HasPrefix(parts[2], "hour") {
+			shift = time.Duration(amount) * time.Hour
+		}
+		normalized = strings.Replace(normalized, parts[0], " ", 1)
+	}
+	expression, err := parse(text, normalized, now.In(Timezone.Location))
+	if err != nil || shift == 0 {
+		return expression, err
+	}
+	expression.End = expression.Latest().Add(shift)
+	expression.Start = expression.Start.Add(-shift)
+	return expression, nil
+}
+
+// Function that reads the normalized time the student wrote, without the margin around it.
+func parse(text string, normalized string, now time.Time) (Expression, error) {
+	if parts := relative.FindStringSubmatch(normalized); parts != nil {
+		amount, _ := strconv.ParseFloat(parts[1], 64)
+		unit := time.Minute
+		if strings.HasPrefix(parts[2], "hour") {
+			unit = time.Hour
+		}
+		return Expression{Start: now.Add(time.Duration(amount * float64(unit))).Truncate(time.Minute)}, nil
+	}
+
+	day, err := findDay(&normalized, now)
+	if err != nil {
+		return Expression{}, err
+	}
+	part := partOfDay.FindString(normalized)
+	if part == "tonight" {
+		part = "night"
+	}
+	normalized = fractions.ReplaceAllStringFunc(normalized, readFraction)
+
+	var start, end clockTime
+	var hasStart, hasEnd bool
+	if parts := window.FindStringSubmatch(normalized); parts != nil {
+		start, hasStart = readClock(parts[1], parts[2], parts[3], day.numeric)
+		end, hasEnd = readClock(parts[4], parts[5], parts[6], day.numeric)
+	} else if parts := moment.FindStringSubmatch(normalized); parts != nil {
+		start, hasStart = readClock(parts[1], parts[2], parts[3], day.numeric)
+	}
+
+	if !hasStart && part == "" {
+		if day.found {
+			return Expression{}, Problem.New("problem.timeOfDay", nil, "I need the time of day too (ex. 'tomorrow at 8am' or 'bokra 8 el sob7')")
+		}
+		// Nothing was understood, so the time may be written in one of the older formats (eg. "2017-11-05 08:30").
+		t, err := Timezone.Parse(text)
+		if err != nil {
+			return Expression{}, Problem.New("problem.when", nil, "I couldn't understand when that is (ex. 'tomorrow 8am', 'sunday at 7:45', 'in 2 hours' or 'bokra 8 el sob7')")
+		}
+		return Expression{Start: t}, nil
+	}
+
+	expression := Expression{Ambiguous: day.guessed}
+	var startMinutes, endMinutes int
+	if !hasStart {
+		startMinutes = partTimes[part]
+		expression.Ambiguous = expression.Ambiguous || part != "noon"
+	} else {
+		if hasEnd && start.meridiem == "" && end.meridiem != "" && !start.exact {
+			start.meridiem = end.meridiem
+			if minutesOf(start, "") > minutesOf(end, "") {
+				start.meridiem = "am"
+			}
+		}
+		guessed := false
+		startMinutes, guessed, err = resolve(start, part)
+		if err != nil {
+			return Expression{}, err
+		}
+		expression.Ambiguous = expression.Ambiguous || guessed
+		if hasEnd {
+			endMinutes, _, err = resolve(end, part)
+			if err != nil {
+				return Expression{}, err
+			}
+			// "between 11 and 1" ends in the afternoon.
+			if end.meridiem == "" && !end.exact && endMinutes <= startMinutes && endMinutes < 12*60 {
+				endMinutes += 12 * 60
+			}
+			if endMinutes <= startMinutes {
+				return Expression{}, Problem.New("problem.endBeforeStart", nil, "the end of the time has to be after its start")
+			}
+		}
+	}
+
+	date := day.date
+	if !day.found {
+		date = now
+	}
+	expression.Start = at(date, startMinutes)
+	if hasEnd {
+		expression.End = at(date, endMinutes)
+	}
+	// A time without a day, or a day of the week, is the next one that didn't pass yet.
+	last := expression.Start
+	if hasEnd {
+		last = expression.End
+	}
+	if !last.After(now) && (!day.found || day.anyDay) {
+		days := 1
+		if day.anyDay {
+			days = 7
+		}
+		expression.Start = at(date.AddDate(0, 0, days), startMinutes)
+		if hasEnd {
+			expression.End = at(date.AddDate(0, 0, days), endMinutes)
+		}
+	}
+	return expression, nil
+}
+
+// Starts : checks the text starts with a time (eg. "tomorrow at 8", "8am", "nov 5" or "bokra el sob7"), so the words before it are not part of the time.
+func Starts(text string) bool {
+	return firstWord.MatchString(normalize(text))
+}
+
+// Function that reads the student's words as English, with Arabic digits and Egyptian Arabic words replaced.
+func normalize(text string) string {
+	text = arabicDigits.Replace(strings.ToLower(text))
+	text = punctuation.ReplaceAllString(text, " $1")
+	padded := " " + strings.Join(strings.Fields(text), " ") + " "
+	for _, phrase := range phrases {
+		// Replacing twice catches the phrases right next to each other, which share a space.
+		for i := 0; i < 2; i++ {
+			padded = strings.Replace(padded, " "+phrase[0]+" ", " "+phrase[1]+" ", -1)
+		}
+	}
+	return strings.Join(strings.Fields(padded), " ")
+}
+
+// The day of a ride as the student wrote it.
+type writtenDay struct {
+	date  time.Time
+	found bool
+	// guessed is set for "next week", which doesn't say the day.
+	guessed bool
+	// anyDay is set for a day of the week, which can be any of the next ones.
+	anyDay bool
+	// numeric is set for dates written in numbers (eg. "2026-11-05"), whose times are on the 24 hour clock.
+	numeric bool
+}
+
+// Function that finds the day in the text and takes it out, so its numbers aren't read as the time.
+func findDay(text *string, now time.Time) (writtenDay, error) {
+	today := at(now, 0)
+	take := func(re *regexp.Regexp) []string {
+		parts := re.FindStringSubmatch(*text)
+		if parts != nil {
+			*text = strings.Replace(*text, parts[0], " ", 1)
+		}
+		return parts
+	}
+	if parts := take(isoDate); parts != nil {
+		return dateOf(parts[1], parts[2], parts[3], true, now)
+	}
+	if parts := take(slashDate); parts != nil {
+		return dateOf(parts[3], parts[2], parts[1], true, now)
+	}
+	if parts := take(monthDay); parts != nil {
+		return dateOf(parts[3], strconv.Itoa(int(months[parts[1]])), parts[2], false, now)
+	}
+	if parts := take(dayMonth); parts != nil {
+		return dateOf(parts[3], strconv.Itoa(int(months[parts[2]])), parts[1], false, now)
+	}
+	if parts := take(weekday); parts != nil {
+		day := weekdays[parts[3][:3]]
+		if parts[1] != "" || parts[4] != "" {
+			// The week starts on Sunday in Egypt.
+			sunday := today.AddDate(0, 0, -int(today.Weekday()))
+			return writtenDay{date: sunday.AddDate(0, 0, 7+int(day)), found: true}, nil
+		}
+		ahead := (int(day) - int(today.Weekday()) + 7) % 7
+		if parts[2] != "" && ahead == 0 {
+			ahead = 7
+		}
+		return writtenDay{date: today.AddDate(0, 0, ahead), found: true, anyDay: parts[2] == ""}, nil
+	}
+	switch word := dayWords.FindString(*text); {
+	case word == "today" || word == "tonight":
+		return writtenDay{date: today, found: true}, nil
+	case word == "tomorrow":
+		return writtenDay{date: today.AddDate(0, 0, 1), found: true}, nil
+	case word == "overmorrow":
+		return writtenDay{date: today.AddDate(0, 0, 2), found: true}, nil
+	case word != "":
+		// "next week" without a day is the same day next week.
+		return writtenDay{date: today.AddDate(0, 0, 7), found: true, guessed: true}, nil
+	}
+	return writtenDay{}, nil
+}
+
+// Function that makes the date of the year, month and day the student wrote. A date without a year is the next one.
+func dateOf(year string, month string, day string, numeric bool, now time.Time) (writtenDay, error) {
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		y = now.Year()
+	} else if y < 100 {
+		y += 2000
+	}
+	date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, Timezone.Location)
+	if m < 1 || m > 12 || date.Day() != d {
+		return writtenDay{}, Problem.New("problem.noDay", map[string]interface{}{"Day": day + "/" + month}, "there is no day "+day+"/"+month)
+	}
+	if year == "" && date.Before(at(now, 0)) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return writtenDay{date: date, found: true, numeric: numeric}, nil
+}
+
+// Function that writes "8 and half", "half past 8", "8 less quarter" and the like as the time they are.
+func readFraction(text string) string {
+	parts := fractions.FindStringSubmatch(text)
+	switch {
+	case parts[1] != "" && parts[2] == "half":
+		return parts[1] + ":30"
+	case parts[1] != "":
+		return parts[1] + ":15"
+	case parts[3] != "":
+		return quarterTo(parts[3])
+	case parts[4] != "":
+		return parts[4] + ":30"
+	case parts[5] == "past":
+		return parts[6] + ":15"
+	}
+	return quarterTo(parts[6])
+}
+
+// Function that writes the time a quarter of an hour before the hour (eg. "8" is "7:45").
+func quarterTo(hour string) string {
+	h, _ := strconv.Atoi(hour)
+	if h <= 1 {
+		h += 12
+	}
+	return strconv.Itoa(h-1) + ":45"
+}
+
+// Function that reads the hour, minutes and am or pm of a time. After a date in numbers, the time is on the 24 hour clock.
+func readClock(hour string, minute string, meridiem string, twentyFour bool) (clockTime, bool) {
+	if hour == "" {
+		return clockTime{}, false
+	}
+	c := clockTime{meridiem: meridiem}
+	c.hour, _ = strconv.Atoi(hour)
+	if minute != "" {
+		c.minute, _ = strconv.Atoi(minute)
+	}
+	c.exact = (twentyFour && meridiem == "") || c.hour == 0 || c.hour > 12 || (len(hour) == 2 && hour[0] == '0')
+	return c, true
+}
+
+// Function that returns the minutes after midnight of the time, reading it as the part of the day says. guessed is set when it could have been 12 hours later or earlier.
+func resolve(c clockTime, part string) (minutes int, guessed bool, err error) {
+	if c.hour > 23 || c.minute > 59 || (c.meridiem != "" && (c.hour == 0 || c.hour > 12)) {
+		return 0, false, Problem.New("problem.noTime", map[string]interface{}{"Time": strconv.Itoa(c.hour) + ":" + strconv.Itoa(c.minute)}, "there is no time "+strconv.Itoa(c.hour)+":"+strconv.Itoa(c.minute))
+	}
+	meridiem := c.meridiem
+	if meridiem == "" && !c.exact {
+		switch part {
+		case "morning":
+			meridiem = "am"
+		case "noon":
+			if c.hour <= 5 {
+				meridiem = "pm"
+			} else {
+				meridiem = "am"
+			}
+		case "afternoon", "evening", "night":
+			meridiem = "pm"
+		default:
+			// Rides leave from early in the morning until the evening.
+			guessed = true
+			meridiem = "pm"
+			if c.hour >= 7 && c.hour < 12 {
+				meridiem = "am"
+			}
+		}
+	}
+	return minutesOf(c, meridiem), guessed, nil
+}
+
+// Function that returns the minutes after midnight of the time, in the morning or the afternoon.
+func minutesOf(c clockTime, meridiem string) int {
+	if meridiem == "" {
+		meridiem = c.meridiem
+	}
+	hour := c.hour
+	if meridiem == "am" && hour == 12 {
+		hour = 0
+	} else if meridiem == "pm" && hour < 12 {
+		hour += 12
+	}
+	return hour*60 + c.minute
+}
+
+// Function that returns the time of the day, at the GUC.
+func at(day time.Time, minutes int) time.Time {
+	day = day.In(Timezone.Location)
+	return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, Timezone.Location)
+}
+
+// ExpressionToString : describes the time (eg. "Sunday, Oct 25, 2026 at 7:45am (EET)", or "Sunday, Oct 25, 2026 between 7:30am and 8:15am (EET)").
+func ExpressionToString(e Expression) string {
+	start := e.Start.In(Timezone.Location)
+	if !e.Window() {
+		return start.Weekday().String() + ", " + Timezone.Format(start)
+	}
+	return start.Weekday().String() + ", " + start.Format("Jan 2, 2006") + " between " + start.Format("3:04pm") + " and " + e.End.In(Timezone.Location).Format("3:04pm (MST)")
+}
+
+// Question : asks the student to confirm the time that was guessed.
+func Question(e Expression) string {
+	return "Did you mean " + ExpressionToString(e) + "? Type 'yes' if that's right, or tell me the time again (ex. 'sunday at 7:45pm')."
+}
+
+// Confirms : checks the student said yes, in English or in Egyptian Arabic.
+func Confirms(message string) bool {
+	switch strings.Trim(strings.ToLower(strings.TrimSpace(message)), ".!") {
+	case "yes", "y", "yeah", "yep", "yup", "sure", "ok", "okay", "correct", "right", "aywa", "aiwa", "aywah", "ah", "tamam", "akeed", "أيوه", "ايوه", "أيوة", "ايوة", "نعم", "تمام", "اه", "آه", "أكيد", "اكيد":
+		return true
+	}
+	return false
+}
+
+// Refuses : checks the student said no, in English or in Egyptian Arabic.
+func Refuses(message string) bool {
+	switch strings.Trim(strings.ToLower(strings.TrimSpace(message)), ".!") {
+	case "no", "n", "nope", "nah", "cancel", "la", "la2", "laa", "لا", "لأ", "لاء":
+		return true
+	}
+	return false
+}
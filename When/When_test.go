@@ -0,0 +1,119 @@
+package When
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+// Wednesday Oct 21 2026 at noon in Cairo.
+var now = time.Date(2026, 10, 21, 12, 0, 0, 0, Timezone.Location)
+
+func cairo(month time.Month, day int, hour int, minute int) time.Time {
+	return time.Date(2026, month, day, hour, minute, 0, 0, Timezone.Location)
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		text      string
+		start     time.Time
+		end       time.Time
+		ambiguous bool
+	}{
+		{"tomorrow 8am", cairo(10, 22, 8, 0), time.Time{}, false},
+		{"Tomorrow at 8:30 PM", cairo(10, 22, 20, 30), time.Time{}, false},
+		{"sunday at 7:45", cairo(10, 25, 7, 45), time.Time{}, true},
+		{"sunday at 7:45pm", cairo(10, 25, 19, 45), time.Time{}, false},
+		{"wednesday 9am", cairo(10, 28, 9, 0), time.Time{}, false},
+		{"next wednesday 3pm", cairo(10, 28, 15, 0), time.Time{}, false},
+		{"in 2 hours", cairo(10, 21, 14, 0), time.Time{}, false},
+		{"in half an hour", cairo(10, 21, 12, 30), time.Time{}, false},
+		{"next week monday morning", cairo(10, 26, 8, 0), time.Time{}, true},
+		{"tomorrow afternoon", cairo(10, 22, 15, 0), time.Time{}, true},
+		{"2pm", cairo(10, 21, 14, 0), time.Time{}, false},
+		{"9:00", cairo(10, 22, 9, 0), time.Time{}, true},
+		{"19:30", cairo(10, 21, 19, 30), time.Time{}, false},
+		{"half past 8 tomorrow morning", cairo(10, 22, 8, 30), time.Time{}, false},
+		{"bokra 8 el sob7", cairo(10, 22, 8, 0), time.Time{}, false},
+		{"bokra 8 w nos el sob7", cairo(10, 22, 8, 30), time.Time{}, false},
+		{"ba3d bokra 5 el 3asr", cairo(10, 23, 17, 0), time.Time{}, false},
+		{"el 7ad el sa3a 9 ella rob3 el sob7", cairo(10, 25, 8, 45), time.Time{}, false},
+		{"ba3d sa3teen", cairo(10, 21, 14, 0), time.Time{}, false},
+		{"بكرة الساعة ٨ الصبح", cairo(10, 22, 8, 0), time.Time{}, false},
+		{"بعد نص ساعة", cairo(10, 21, 12, 30), time.Time{}, false},
+		{"between 7:30 and 8:15", cairo(10, 22, 7, 30), cairo(10, 22, 8, 15), true},
+		{"tomorrow between 7:30 and 8:15am", cairo(10, 22, 7, 30), cairo(10, 22, 8, 15), false},
+		{"sunday from 11 to 1pm", cairo(10, 25, 11, 0), cairo(10, 25, 13, 0), false},
+		{"bokra ben 7 w 8 el sob7", cairo(10, 22, 7, 0), cairo(10, 22, 8, 0), false},
+		{"2026-11-05 08:30", cairo(11, 5, 8, 30), time.Time{}, false},
+		{"5/11 17:00", cairo(11, 5, 17, 0), time.Time{}, false},
+		{"2026-11-5 9:5", cairo(11, 5, 9, 5), time.Time{}, false},
+		{"nov 5,2026 at 8.30", cairo(11, 5, 8, 30), time.Time{}, true},
+		{"5th of november at 8:30am", cairo(11, 5, 8, 30), time.Time{}, false},
+		{"anytime tomorrow 7:30–8:30am", cairo(10, 22, 7, 30), cairo(10, 22, 8, 30), false},
+		{"tomorrow 8am give or take 15 minutes", cairo(10, 22, 7, 45), cairo(10, 22, 8, 15), false},
+		{"tomorrow 8am ±20", cairo(10, 22, 7, 40), cairo(10, 22, 8, 20), false},
+		{"sunday 5pm +- half an hour", cairo(10, 25, 16, 30), cairo(10, 25, 17, 30), false},
+		{"bokra 8 el sob7 plus or minus an hour", cairo(10, 22, 7, 0), cairo(10, 22, 9, 0), false},
+	}
+	for _, c := range cases {
+		expression, err := Parse(c.text, now)
+		if err != nil {
+			t.Error(c.text+": ", err)
+			continue
+		}
+		if !expression.Start.Equal(c.start) || !expression.End.Equal(c.end) || expression.Ambiguous != c.ambiguous {
+			t.Error(c.text+": got "+ExpressionToString(expression)+", ambiguous:", expression.Ambiguous)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{"whenever", "tomorrow", "between 9am and 8am", "31/2 8am", "25:00"} {
+		if expression, err := Parse(text, now); err == nil {
+			t.Error("understood '" + text + "' as " + ExpressionToString(expression))
+		}
+	}
+}
+
+func TestParseAcrossDaylightSaving(t *testing.T) {
+	// The clocks go back in Cairo in the night after Thursday Oct 29 2026.
+	thursday := time.Date(2026, 10, 29, 22, 0, 0, 0, Timezone.Location)
+	expression, err := Parse("bokra 8am", thursday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expression.Start.Equal(time.Date(2026, 10, 30, 6, 0, 0, 0, time.UTC)) {
+		t.Error("wrong time after the clocks went back", expression.Start.UTC())
+	}
+	if expression, _ = Parse("in 2 hours", thursday); expression.Start.Sub(thursday) != 2*time.Hour {
+		t.Error("two hours later are not two hours away", expression.Start)
+	}
+}
+
+func TestStarts(t *testing.T) {
+	for _, text := range []string{"tomorrow 8am", "Sunday", "at 8", "8am", "7:30", "2026-11-05", "nov 5", "bokra", "بكرة الساعة ٨", "ba3d sa3a"} {
+		if !Starts(text) {
+			t.Error(text + " doesn't start a time")
+		}
+	}
+	for _, text := range []string{"Rehab tomorrow", "Maadi", "el rehab", "6th of october", "october bokra", "city", "intercontinental", "sunset"} {
+		if Starts(text) {
+			t.Error(text + " starts a time")
+		}
+	}
+}
+
+func TestQuestion(t *testing.T) {
+	expression, _ := Parse("sunday at 7:45", now)
+	if got := Question(expression); got != "Did you mean Sunday, Oct 25, 2026 at 7:45am (EEST)? Type 'yes' if that's right, or tell me the time again (ex. 'sunday at 7:45pm')." {
+		t.Error("wrong question: " + got)
+	}
+	if !Confirms(" Yes! ") || !Confirms("aywa") || !Confirms("أيوه") || Confirms("no") || Confirms("sunday at 7:45pm") {
+		t.Error("wrong confirmations")
+	}
+	if !Refuses("No.") || !Refuses("la2") || !Refuses("لا") || Refuses("yes") || Refuses("not at 8") {
+		t.Error("wrong refusals")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Recurring"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+var closeCommand = regexp.MustCompile(`(?i)^close\s+(?:the\s+)?campus\s+(?:on\s+|from\s+)?([0-9]{4}-[0-9]{1,2}-[0-9]{1,2})(?:\s+(?:until|to)\s+([0-9]{4}-[0-9]{1,2}-[0-9]{1,2}))?(?:\s+(?:because|for)\s+(.+))?$`)
+
+// Function that checks the student is one of the admins, whose GUC IDs are in ADMINS (eg. "34-1234,34-5678").
+func isAdmin(gucID string) bool {
+	for _, admin := range strings.Split(os.Getenv("ADMINS"), ",") {
+		if strings.TrimSpace(admin) != "" && strings.EqualFold(strings.TrimSpace(admin), gucID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Function that checks the GUC is open on the day of the time, so a carpool can take place then.
+func (s *server) checkCampusOpen(session Session, startTime time.Time) error {
+	year, err := Calendar.Load(s.calendar)
+	if err != nil {
+		return errors.New(say(session, "calendar.error", nil))
+	}
+	if entry, closed := year.Closed(startTime); closed {
+		return errors.New(say(session, "campus.closed", Messages.Params{"Day": sayDay(session, startTime), "Reason": entry.Name}))
+	}
+	return nil
+}
+
+// Function that lets an admin close the campus for some days (eg. 'close campus on 2026-11-02 until 2026-11-03 because of the storm'). The carpools on those days are cancelled.
+func (s *server) closeCampus(res http.ResponseWriter, session Session, message string) {
+	gucID := session["gucID"].(string)
+	if !isAdmin(gucID) {
+		writeJSON(res, JSON{
+			"message": say(session, "closure.adminsOnly", nil),
+		})
+		return
+	}
+	parts := closeCommand.FindStringSubmatch(strings.TrimSpace(message))
+	if parts == nil {
+		writeJSON(res, JSON{
+			"message": say(session, "closure.usage", nil),
+		})
+		return
+	}
+	from, err := Recurring.ParseDate(parts[1])
+	to := from
+	if err == nil && parts[2] != "" {
+		to, err = Recurring.ParseDate(parts[2])
+	}
+	var closure DB.CalendarEntry
+	if err == nil {
+		closure, err = Calendar.NewClosure(from, to, parts[3], gucID)
+	}
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "sorry", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	err = s.calendar.Add(&closure)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "closure.saveError", nil),
+		})
+		return
+	}
+
+	carpoolRequests, err := DB.GetPostsBetween(from, to.AddDate(0, 0, 1))
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "closure.findError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	cancelled := 0
+	for _, carpoolRequest := range carpoolRequests {
+		err := closeCarpool(carpoolRequest, closure)
+		if err != nil {
+			log.Printf("could not cancel carpool %d for the closure: %s\n", carpoolRequest.PostID, err.Error())
+			continue
+		}
+		cancelled++
+	}
+	writeJSON(res, JSON{
+		"message": say(session, "closure.done", Messages.Params{"Closure": Calendar.EntryToString(closure), "Count": cancelled}),
+	})
+}
+
+// Function that cancels a carpool on a day the GUC is closed, and emails its driver and the passengers that asked to join it.
+func closeCarpool(carpoolRequest DB.CarpoolRequest, closure DB.CalendarEntry) error {
+	passengerRequests, err := DB.GetPassengerRequestsByPostID(carpoolRequest.PostID)
+	if err != nil {
+		return err
+	}
+	err = DB.DeleteDB(carpoolRequest.PostID)
+	if err != nil {
+		return err
+	}
+	cancelReminders(carpoolRequest.PostID)
+	data := Notifier.Data{"PostID": carpoolRequest.PostID, "Date": Timezone.FormatDay(carpoolRequest.StartTime), "Reason": closure.Name}
+	sendEmail(carpoolRequest.GUCID, carpoolRequest.Name, Notifier.CampusClosed, data)
+	for _, passengerRequest := range passengerRequests {
+		if passengerRequest.Notify == 1 || passengerRequest.Notify == 2 {
+			sendEmail(passengerRequest.Passenger.GUCID, passengerRequest.Passenger.Name, Notifier.CampusClosed, data)
+		}
+	}
+	return nil
+}
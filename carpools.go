@@ -0,0 +1,335 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/When"
+)
+
+// How long a ride is taken to last, when making sure two rides of a student don't overlap.
+const rideLength = 2 * time.Hour
+
+// The session keys of the carpool a driver is creating or editing. Carpools that are done being created live in the database only.
+var draftKeys = []string{"fromGUC", "latitude", "longitude", "time", "vehiclePlate", "capacity", "availableSeats", "currentPassengers", "possiblePassengers", "createComplete", "postID", "returnOf", "requestOrCreate", "timeGuess", "timeGuessEnd", "shift", "place", "oneShot", "confirming"}
+
+// Function that forgets the carpool the driver is creating or editing.
+func forgetDraft(session Session) {
+	for _, key := range draftKeys {
+		delete(session, key)
+	}
+}
+
+// The session keys of the request of a passenger. The carpool they chose is kept.
+var requestKeys = []string{"fromGUCreq", "latitudereq", "longitudereq", "timereq", "timereqEnd", "timeGuess", "timeGuessEnd", "requestComplete", "placereq", "oneShot", "confirming"}
+
+// Function that forgets the request of the passenger.
+func forgetRequest(session Session) {
+	for _, key := range requestKeys {
+		delete(session, key)
+	}
+}
+
+var (
+	gucIDFormat  = regexp.MustCompile(`[0-9]+-[0-9]+`)
+	postIDFormat = regexp.MustCompile(`\b[0-9]+\b`)
+)
+
+// Function that finds the post ID in a command (eg. 'edit carpool 12'), ignoring the GUC IDs in it.
+func postIDIn(comparable string) (uint64, bool) {
+	postID, err := strconv.ParseUint(postIDFormat.FindString(gucIDFormat.ReplaceAllString(comparable, " ")), 10, 64)
+	return postID, err == nil
+}
+
+// Function that returns the carpools of a driver that are not over yet.
+func driverCarpools(gucID string) ([]DB.CarpoolRequest, error) {
+	carpoolRequests, err := DB.GetPostsByGUCID(gucID)
+	if err != nil {
+		return nil, err
+	}
+	active := []DB.CarpoolRequest{}
+	for _, carpoolRequest := range carpoolRequests {
+		status := carpoolRequest.CurrentStatus()
+		if status != DB.StatusCompleted && status != DB.StatusCancelled {
+			active = append(active, carpoolRequest)
+		}
+	}
+	return active, nil
+}
+
+// Function that picks the carpool a command is about: the one with the post ID in the command, or the only one there is.
+func chooseCarpool(session Session, carpoolRequests []DB.CarpoolRequest, comparable string) (DB.CarpoolRequest, error) {
+	if postID, found := postIDIn(comparable); found {
+		for _, carpoolRequest := range carpoolRequests {
+			if carpoolRequest.PostID == postID {
+				return carpoolRequest, nil
+			}
+		}
+		return DB.CarpoolRequest{}, errors.New(say(session, "choose.notYours", Messages.Params{"PostID": strconv.FormatUint(postID, 10)}))
+	}
+	switch len(carpoolRequests) {
+	case 0:
+		return DB.CarpoolRequest{}, errors.New(say(session, "choose.noCarpool", nil))
+	case 1:
+		return carpoolRequests[0], nil
+	}
+	list := ""
+	for _, carpoolRequest := range carpoolRequests {
+		list += "\n" + strconv.FormatUint(carpoolRequest.PostID, 10) + ": " + sayTime(session, carpoolRequest.StartTime)
+	}
+	return DB.CarpoolRequest{}, errors.New(say(session, "choose.which", Messages.Params{"Count": len(carpoolRequests), "PostID": strconv.FormatUint(carpoolRequests[0].PostID, 10)}) + list)
+}
+
+// Function that picks the carpool of the driver the passenger asked to join, or the one with the post ID in the command. A passenger that asked to join both ways of a round trip is in both.
+func passengerCarpools(session Session, carpoolRequests []DB.CarpoolRequest, comparable string, passengerID string) ([]DB.CarpoolRequest, error) {
+	if _, found := postIDIn(comparable); found {
+		carpoolRequest, err := chooseCarpool(session, carpoolRequests, comparable)
+		return []DB.CarpoolRequest{carpoolRequest}, err
+	}
+	withPassenger := []DB.CarpoolRequest{}
+	for _, carpoolRequest := range carpoolRequests {
+		for _, gucID := range append(append([]string{}, carpoolRequest.PossiblePassengers...), carpoolRequest.CurrentPassengers...) {
+			if gucID == passengerID {
+				withPassenger = append(withPassenger, carpoolRequest)
+				break
+			}
+		}
+	}
+	if len(withPassenger) == 0 {
+		return nil, errors.New(say(session, "accept.notAsked", Messages.Params{"Passenger": passengerID}))
+	}
+	if len(withPassenger) == 2 && withPassenger[0].LinkedPostID == withPassenger[1].PostID {
+		return withPassenger, nil
+	}
+	carpoolRequest, err := chooseCarpool(session, withPassenger, comparable)
+	return []DB.CarpoolRequest{carpoolRequest}, err
+}
+
+// Function that reads the time of a ride the student wrote. When a part of it was guessed, the guess is kept in the session and the question to confirm it is returned instead, until they answer.
+func readRideTime(session Session, message string) (When.Expression, string, error) {
+	if guess, guessed := session["timeGuess"].(time.Time); guessed {
+		end, _ := session["timeGuessEnd"].(time.Time)
+		delete(session, "timeGuess")
+		delete(session, "timeGuessEnd")
+		if When.Confirms(message) {
+			return When.Expression{Start: guess, End: end}, "", nil
+		}
+	}
+	expression, err := When.Parse(message, Timezone.Now())
+	if err != nil {
+		return When.Expression{}, "", err
+	}
+	if expression.Ambiguous {
+		session["timeGuess"] = expression.Start
+		if expression.Window() {
+			session["timeGuessEnd"] = expression.End
+		}
+		return When.Expression{}, sayQuestion(session, expression), nil
+	}
+	return expression, "", nil
+}
+
+// Function that reads the time a driver wrote as the start time of their carpool, and how many minutes they can leave before or after it. A window (eg. "between 7:30 and 8:30") starts in its middle.
+func carpoolTime(session Session, expression When.Expression) (time.Time, int, error) {
+	if !expression.Window() {
+		return expression.Start, 0, nil
+	}
+	shift := int(expression.End.Sub(expression.Start)/time.Minute) / 2
+	if shift > DB.MaxShift {
+		return time.Time{}, 0, errors.New(say(session, "shift.tooLong", Messages.Params{"Minutes": DB.MaxShift}))
+	}
+	return expression.Start.Add(time.Duration(shift) * time.Minute), shift, nil
+}
+
+// Function that checks the driver can drive a carpool at the time they wrote, and returns its start time and how many minutes they can leave before or after it.
+func (s *server) checkCarpoolTime(session Session, expression When.Expression) (time.Time, int, error) {
+	startTime, shift, err := carpoolTime(session, expression)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	if !expression.Start.After(time.Now()) {
+		return time.Time{}, 0, errors.New(say(session, "create.pastTime", nil))
+	}
+	err = s.checkCampusOpen(session, startTime)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	err = checkDrivingConflict(session, expression.Start, expression.Latest())
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	err = checkRidingConflict(session, expression.Start, expression.Latest())
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return startTime, shift, nil
+}
+
+// Function that checks the passenger can ride at the time they wrote.
+func (s *server) checkRequestTime(session Session, expression When.Expression) error {
+	if !expression.Start.After(time.Now()) {
+		return errors.New(say(session, "request.pastTime", nil))
+	}
+	err := s.checkCampusOpen(session, expression.Start)
+	if err != nil {
+		return err
+	}
+	return checkDrivingConflict(session, expression.Start, expression.Latest())
+}
+
+// Function that describes how much the driver can leave before or after the time, if they can.
+func shiftToString(session Session, shift int) string {
+	if shift == 0 {
+		return ""
+	}
+	return say(session, "shift", Messages.Params{"Minutes": shift})
+}
+
+// Function that checks a ride leaving between earliest and latest could still be going on when the carpool leaves, or the other way around.
+func ridesOverlap(carpoolRequest DB.CarpoolRequest, earliest time.Time, latest time.Time) bool {
+	return carpoolRequest.Earliest().Before(latest.Add(rideLength)) && earliest.Before(carpoolRequest.Latest().Add(rideLength))
+}
+
+// Function that checks the student isn't driving another carpool that could overlap a ride leaving between earliest and latest. The carpool being edited doesn't count.
+func checkDrivingConflict(session Session, earliest time.Time, latest time.Time) error {
+	except, _ := session["postID"].(uint64)
+	margin := rideLength + DB.MaxShift*time.Minute
+	carpoolRequests, err := DB.GetPostsAround(session["gucID"].(string), earliest.Add(-margin), latest.Add(margin), except)
+	if err != nil {
+		return errors.New(say(session, "conflict.error", nil))
+	}
+	for _, carpoolRequest := range carpoolRequests {
+		if ridesOverlap(carpoolRequest, earliest, latest) {
+			return errors.New(say(session, "conflict.driving", Messages.Params{"PostID": strconv.FormatUint(carpoolRequest.PostID, 10), "Time": sayTime(session, carpoolRequest.StartTime) + shiftToString(session, carpoolRequest.Shift)}))
+		}
+	}
+	return nil
+}
+
+// Function that checks the driver isn't riding in the carpools they chose around a ride leaving between earliest and latest.
+func checkRidingConflict(session Session, earliest time.Time, latest time.Time) error {
+	for _, myChoice := range chosenCarpools(session) {
+		chosen, err := DB.GetPostByID(myChoice)
+		if err != nil {
+			return errors.New(say(session, "conflict.error", nil))
+		}
+		if len(chosen) > 0 && ridesOverlap(chosen[0], earliest, latest) {
+			return errors.New(say(session, "conflict.riding", Messages.Params{"PostID": strconv.FormatUint(myChoice, 10)}))
+		}
+	}
+	return nil
+}
+
+// Function that finds the carpool of the driver a command is about, and writes out why if there isn't one.
+func ownCarpool(res http.ResponseWriter, session Session, comparable string) (DB.CarpoolRequest, bool) {
+	carpoolRequests, err := driverCarpools(session["gucID"].(string))
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return DB.CarpoolRequest{}, false
+	}
+	carpoolRequest, err := chooseCarpool(session, carpoolRequests, comparable)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": sayError(session, err),
+		})
+		return DB.CarpoolRequest{}, false
+	}
+	return carpoolRequest, true
+}
+
+// Function that deletes a carpool, and lets the passengers that asked to join it know. The passengers that also asked to join the other way of a round trip are told they are still in it.
+func cancelCarpool(driverName string, carpoolRequest DB.CarpoolRequest) error {
+	postID := carpoolRequest.PostID
+	passengerRequests, err := DB.GetPassengerRequestsByPostID(postID)
+	if err != nil {
+		return err
+	}
+	inLinked := map[string]bool{}
+	if carpoolRequest.LinkedPostID != 0 {
+		linkedRequests, err := DB.GetPassengerRequestsByPostID(carpoolRequest.LinkedPostID)
+		if err != nil {
+			return err
+		}
+		for _, passengerRequest := range linkedRequests {
+			if passengerRequest.Notify == 1 || passengerRequest.Notify == 2 {
+				inLinked[passengerRequest.Passenger.GUCID] = true
+			}
+		}
+	}
+	err = DB.DeleteDB(postID)
+	if err != nil {
+		return err
+	}
+	cancelReminders(postID)
+	for _, passengerRequest := range passengerRequests {
+		if passengerRequest.Notify == 1 || passengerRequest.Notify == 2 {
+			data := Notifier.Data{"DriverName": driverName, "PostID": postID}
+			if inLinked[passengerRequest.Passenger.GUCID] {
+				data["LinkedPostID"] = carpoolRequest.LinkedPostID
+			}
+			sendEmail(passengerRequest.Passenger.GUCID, passengerRequest.Passenger.Name, Notifier.CarpoolDeleted, data)
+		}
+	}
+	return nil
+}
+
+// Function that deletes a carpool of the driver, who can undo it for a short while.
+func (s *server) deleteCarpool(res http.ResponseWriter, session Session, carpoolRequest DB.CarpoolRequest) {
+	postID := carpoolRequest.PostID
+	if draftID, editing := session["postID"]; editing && draftID == postID {
+		forgetDraft(session)
+	}
+	err := cancelCarpool(session["name"].(string), carpoolRequest)
+	if err != nil {
+		//	res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": say(session, "delete.error", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	err = s.skipOccurrence(carpoolRequest, true)
+	if err != nil {
+		log.Printf("could not skip carpool %d in schedule %d: %s\n", postID, carpoolRequest.ScheduleID, err.Error())
+	}
+	rememberUndo(session, undoDelete, []uint64{postID}, nil, "", nil)
+	// The other way of a round trip stays, but the driver is asked if they want to delete it too.
+	if carpoolRequest.LinkedPostID != 0 {
+		linkedID := strconv.FormatUint(carpoolRequest.LinkedPostID, 10)
+		err := DB.SetLinkedCarpool(carpoolRequest.LinkedPostID, 0)
+		if err != nil {
+			log.Printf("could not unlink carpool %s: %s\n", linkedID, err.Error())
+		}
+		session["confirmDelete"] = carpoolRequest.LinkedPostID
+		writeJSON(res, JSON{
+			"message": say(session, "delete.oneWay", Messages.Params{"PostID": strconv.FormatUint(postID, 10), "LinkedPostID": linkedID}) + undoHint(session),
+		})
+		return
+	}
+	writeJSON(res, JSON{
+		"message": say(session, "delete.done", Messages.Params{"PostID": strconv.FormatUint(postID, 10)}) + undoHint(session),
+	})
+}
+
+// Function that returns the time the passenger asked to leave at, or the window of time they can leave in.
+func requestWindow(session Session) When.Expression {
+	start, _ := session["timereq"].(time.Time)
+	end, _ := session["timereqEnd"].(time.Time)
+	return When.Expression{Start: start, End: end}
+}
+
+// Function that checks the carpool goes the way the passenger asked for, and the driver can leave at a time the passenger can.
+func fitsRequest(session Session, carpoolRequest DB.CarpoolRequest) bool {
+	fromGUC, _ := session["fromGUCreq"].(bool)
+	window := requestWindow(session)
+	return carpoolRequest.FromGUC == fromGUC && carpoolRequest.Overlaps(window.Start, window.Latest())
+}
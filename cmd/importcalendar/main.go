@@ -0,0 +1,58 @@
+// Command importcalendar imports the academic calendar (semesters, holidays and exams) from an iCalendar file, or from a CSV file with the columns kind, name, first day and last day.
+//
+// Run it again with a newer calendar to replace it. The closures the admins added from the chat are kept.
+//
+//	importcalendar [-dry-run] calendar.ics|calendar.csv
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only check the file, don't change the calendar")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: importcalendar [-dry-run] calendar.ics|calendar.csv")
+		os.Exit(2)
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	var entries []DB.CalendarEntry
+	var skipped []string
+	if strings.HasSuffix(strings.ToLower(flag.Arg(0)), ".ics") {
+		entries, skipped, err = Calendar.ParseICS(file)
+	} else {
+		entries, err = Calendar.ParseCSV(file)
+	}
+	if err != nil {
+		log.Fatal("the calendar was not imported: " + err.Error())
+	}
+	for _, entry := range entries {
+		fmt.Println(Calendar.EntryToString(entry))
+	}
+	for _, summary := range skipped {
+		fmt.Printf("left out %q, it is not a semester, a holiday or exams. Give it one of those categories to import it.\n", summary)
+	}
+	if *dryRun {
+		fmt.Printf("%d entries are on the calendar, nothing was imported.\n", len(entries))
+		return
+	}
+
+	err = Calendar.DBStore{}.Replace(entries)
+	if err != nil {
+		log.Fatal("the import stopped half way, run it again: " + err.Error())
+	}
+	fmt.Printf("%d entries imported.\n", len(entries))
+}
@@ -0,0 +1,45 @@
+// Command importroster imports the roster of the students from a CSV file with the columns ID, full name, email, faculty and gender.
+//
+// Run it again with a newer roster to update it: students that are no longer on it (eg. graduated) are deactivated and can't log in anymore.
+//
+//	importroster [-dry-run] roster.csv
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Roster"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only check the file, don't change the roster")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: importroster [-dry-run] roster.csv")
+		os.Exit(2)
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	students, err := Roster.Parse(file)
+	if err != nil {
+		log.Fatal("the roster was not imported: " + err.Error())
+	}
+	if *dryRun {
+		fmt.Printf("%d students are on the roster, nothing was imported.\n", len(students))
+		return
+	}
+
+	result, err := Roster.Import(Roster.DBStore{}, students, time.Now())
+	if err != nil {
+		log.Fatal("the import stopped half way, run it again: " + err.Error())
+	}
+	fmt.Printf("%d students added, %d updated, %d deactivated.\n", result.Added, result.Updated, result.Deactivated)
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Command"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DirectionsAPI"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/When"
+)
+
+var (
+	coordinates = regexp.MustCompile(`[0-9]+[\.]?[0-9]*`)
+	number      = regexp.MustCompile(`\b[0-9]+\b`)
+	backCommand = regexp.MustCompile(`^\s*(go\s+)?back\s*$`)
+)
+
+// Function that starts a carpool or a request written in one message (eg. "create to guc from Rehab tomorrow 8am 3 seats"), fills in the details it has and asks for the rest.
+func (s *server) startCommand(session Session, command Command.Command) (string, error) {
+	if command.Action == Command.Create {
+		forgetDraft(session)
+	} else {
+		forgetRequest(session)
+	}
+	session["requestOrCreate"] = command.Action
+	session["oneShot"] = true
+	return s.nextDetail(session, s.fillCommand(session, command))
+}
+
+// Function that handles the answers of a student finishing a carpool or a request they started in one message: a missing detail, a change, or whether the summary is right.
+func (s *server) commandChat(session Session, message string) (string, error) {
+	command := Command.Parse(message)
+	// The student may start over with another one.
+	if command.Action != "" && (command.Details() || command.Time != "") {
+		return s.startCommand(session, command)
+	}
+	if _, guessed := session["timeGuess"]; guessed {
+		return s.nextDetail(session, s.fillTime(session, message))
+	}
+	_, confirming := session["confirming"]
+	delete(session, "confirming")
+	comparable := strings.ToLower(strings.TrimSpace(message))
+	if confirming && When.Confirms(message) {
+		delete(session, "oneShot")
+		if session["requestOrCreate"] == Command.Create {
+			return s.saveCarpool(session)
+		}
+		return completeRequest(session), nil
+	}
+	if confirming && When.Refuses(message) {
+		if session["requestOrCreate"] == Command.Create {
+			forgetDraft(session)
+			return say(session, "command.notCreated", nil), nil
+		}
+		forgetRequest(session)
+		delete(session, "requestOrCreate")
+		return say(session, "command.notSaved", nil), nil
+	}
+
+	if command.Details() {
+		return s.nextDetail(session, s.fillCommand(session, command))
+	}
+	// Anything else answers the question about the first missing detail.
+	note := ""
+	switch missingDetail(session) {
+	case "direction":
+		note = say(session, "command.unanswered", nil) + " "
+	case "place":
+		if strings.Contains(comparable, "latitude") && strings.Contains(comparable, "longitude") && len(coordinates.FindAllString(comparable, -1)) >= 2 {
+			latitude, _ := strconv.ParseFloat(coordinates.FindAllString(comparable, -1)[0], 64)
+			longitude, _ := strconv.ParseFloat(coordinates.FindAllString(comparable, -1)[1], 64)
+			setPlace(session, "", latitude, longitude)
+		} else {
+			note = s.fillPlace(session, strings.TrimSpace(message))
+		}
+	case "time":
+		note = s.fillTime(session, message)
+	case "vehicle":
+		reply, err := s.chooseVehicle(session, message)
+		if err != nil {
+			return "", err
+		}
+		// The reply says what was wrong with the car, and asks for it again.
+		if _, chosen := session["vehiclePlate"]; !chosen {
+			return reply, nil
+		}
+	case "seats":
+		seats, err := strconv.Atoi(number.FindString(comparable))
+		if err != nil {
+			note = say(session, "seats.notNumber", nil) + " "
+		} else {
+			session["availableSeats"] = seats
+		}
+	default:
+		if _, err := When.Parse(message, Timezone.Now()); err != nil {
+			note = say(session, "command.unclear", nil) + " "
+		} else {
+			note = s.fillTime(session, message)
+		}
+	}
+	return s.nextDetail(session, note)
+}
+
+// Function that fills in the details written in the message, and returns what was wrong with the ones that couldn't be.
+func (s *server) fillCommand(session Session, command Command.Command) string {
+	notes := ""
+	if command.Direction {
+		if session["requestOrCreate"] == Command.Create {
+			session["fromGUC"] = command.FromGUC
+		} else {
+			session["fromGUCreq"] = command.FromGUC
+		}
+	}
+	if command.Place != "" {
+		notes += s.fillPlace(session, command.Place)
+	}
+	if command.Time != "" {
+		notes += s.fillTime(session, command.Time)
+	}
+	if command.Seats > 0 && session["requestOrCreate"] == Command.Create {
+		session["availableSeats"] = command.Seats
+	}
+	return notes
+}
+
+// Function that finds the place on the map, and takes it as the pickup or drop off point of the ride.
+func (s *server) fillPlace(session Session, place string) string {
+	latitude, longitude, err := DirectionsAPI.GetLocation(place)
+	if err != nil {
+		return say(session, "place.notFound", Messages.Params{"Place": place}) + " "
+	}
+	setPlace(session, place, latitude, longitude)
+	return ""
+}
+
+// Function that remembers the pickup or drop off point of the ride, and the name the student gave it.
+func setPlace(session Session, name string, latitude float64, longitude float64) {
+	if session["requestOrCreate"] == Command.Create {
+		session["place"] = name
+		session["latitude"] = latitude
+		session["longitude"] = longitude
+		return
+	}
+	session["placereq"] = name
+	session["latitudereq"] = strconv.FormatFloat(latitude, 'f', -1, 64)
+	session["longitudereq"] = strconv.FormatFloat(longitude, 'f', -1, 64)
+}
+
+// Function that reads the time of the ride, and takes it if the student can ride then. A guessed time is confirmed first.
+func (s *server) fillTime(session Session, text string) string {
+	expression, question, err := readRideTime(session, text)
+	if err != nil {
+		return say(session, "time.notValid", Messages.Params{"Error": sayError(session, err)}) + " "
+	}
+	if question != "" {
+		return ""
+	}
+	if session["requestOrCreate"] == Command.Create {
+		startTime, shift, err := s.checkCarpoolTime(session, expression)
+		if err != nil {
+			return sayError(session, err) + ". "
+		}
+		session["time"] = startTime
+		session["shift"] = shift
+		return ""
+	}
+	err = s.checkRequestTime(session, expression)
+	if err != nil {
+		return sayError(session, err) + ". "
+	}
+	session["timereq"] = expression.Start
+	delete(session, "timereqEnd")
+	if expression.Window() {
+		session["timereqEnd"] = expression.End
+	}
+	return ""
+}
+
+// A step of creating or requesting a carpool: the detail it asks for, and the session keys the answer fills in. The first key is there once the step is done.
+type step struct {
+	detail string
+	keys   []string
+}
+
+var (
+	createSteps = []step{
+		{"direction", []string{"fromGUC"}},
+		{"place", []string{"latitude", "longitude", "place"}},
+		{"time", []string{"time", "shift"}},
+		{"vehicle", []string{"vehiclePlate", "capacity"}},
+		{"seats", []string{"availableSeats"}},
+	}
+	requestSteps = []step{
+		{"direction", []string{"fromGUCreq"}},
+		{"place", []string{"latitudereq", "longitudereq", "placereq"}},
+		{"time", []string{"timereq", "timereqEnd"}},
+	}
+)
+
+// Function that returns the steps of what the student is doing, and the index of the first one they didn't answer yet. It is the number of steps once they answered them all.
+func currentStep(session Session) ([]step, int) {
+	steps := requestSteps
+	if session["requestOrCreate"] == Command.Create {
+		steps = createSteps
+	}
+	for i, step := range steps {
+		if _, found := session[step.keys[0]]; !found {
+			return steps, i
+		}
+	}
+	return steps, len(steps)
+}
+
+// Function that returns the first detail the carpool or the request is missing, or nothing if it has them all.
+func missingDetail(session Session) string {
+	steps, current := currentStep(session)
+	if current == len(steps) {
+		return ""
+	}
+	return steps[current].detail
+}
+
+// Function that takes the student back to the step before the one they are at, and asks it again. From there, the missing details are asked for one at a time, and shown to be confirmed at the end.
+func (s *server) stepBack(session Session) (string, error) {
+	if _, started := session["requestOrCreate"]; !started {
+		return "", errors.New(say(session, "back.nothing", nil))
+	}
+	if outboundID, created := session["returnOf"].(uint64); created {
+		carpoolID := strconv.FormatUint(outboundID, 10)
+		return say(session, "back.created", Messages.Params{"PostID": carpoolID}) + " " + returnQuestion(session), nil
+	}
+	delete(session, "timeGuess")
+	delete(session, "timeGuessEnd")
+	delete(session, "confirming")
+	steps, current := currentStep(session)
+	if current == 0 {
+		if session["requestOrCreate"] == Command.Create {
+			forgetDraft(session)
+		} else {
+			forgetRequest(session)
+			delete(session, "requestOrCreate")
+		}
+		return say(session, "back.start", nil), nil
+	}
+	previous := current - 1
+	// A driver with one car isn't asked which car they drive, so they go back to the time.
+	if steps[previous].detail == "vehicle" && s.vehicleCount(session) == 1 {
+		previous--
+	}
+	for _, step := range steps[previous:current] {
+		for _, key := range step.keys {
+			delete(session, key)
+		}
+	}
+	session["oneShot"] = true
+	return s.nextDetail(session, say(session, "back.done", nil)+" ")
+}
+
+// Function that returns how many cars the driver has in their profile.
+func (s *server) vehicleCount(session Session) int {
+	user, _, err := s.users.Get(session["gucID"].(string))
+	if err != nil {
+		return 0
+	}
+	return len(user.Vehicles)
+}
+
+// Function that asks for the next missing detail after the note, or shows the details to confirm them once there are all.
+func (s *server) nextDetail(session Session, note string) (string, error) {
+	create := session["requestOrCreate"] == Command.Create
+	if guess, guessed := session["timeGuess"].(time.Time); guessed {
+		end, _ := session["timeGuessEnd"].(time.Time)
+		return note + sayQuestion(session, When.Expression{Start: guess, End: end}), nil
+	}
+	switch missingDetail(session) {
+	case "direction":
+		return note + say(session, "command.direction", nil), nil
+	case "place":
+		question := say(session, "request.wherePickUp", nil)
+		switch {
+		case create && session["fromGUC"].(bool):
+			question = say(session, "create.whereGoing", nil)
+		case create:
+			question = say(session, "create.wherePickUp", nil)
+		case session["fromGUCreq"].(bool):
+			question = say(session, "request.whereGo", nil)
+		}
+		return note + say(session, "command.place", Messages.Params{"Question": question}), nil
+	case "time":
+		return note + say(session, "command.time", nil), nil
+	case "":
+		if !create {
+			session["confirming"] = true
+			return note + say(session, "command.confirmRequest", Messages.Params{"Details": getDetails(session)}), nil
+		}
+	}
+
+	if _, found := session["vehiclePlate"]; !found {
+		question, err := s.askVehicle(session)
+		if err != nil {
+			return "", err
+		}
+		// A driver with one car drives it.
+		if _, chosen := session["vehiclePlate"]; !chosen {
+			return note + question, nil
+		}
+	}
+	currentPassengers, _ := session["currentPassengers"].([]string)
+	seatsLeft := session["capacity"].(int) - len(currentPassengers)
+	if seatsLeft < 1 {
+		return "", errors.New(say(session, "seats.full", nil))
+	}
+	seats, found := session["availableSeats"].(int)
+	if !found || seats < 1 || seats > seatsLeft {
+		delete(session, "availableSeats")
+		if found {
+			note += say(session, "seats.tooMany", Messages.Params{"Seats": seatsLeft}) + " "
+		}
+		return note + say(session, "command.seats", Messages.Params{"Seats": seatsLeft}), nil
+	}
+	session["confirming"] = true
+	return note + say(session, "command.confirmCarpool", Messages.Params{"Details": s.draftToString(session)}), nil
+}
+
+// Function that describes the carpool the driver is creating.
+func (s *server) draftToString(session Session) string {
+	place, _ := session["place"].(string)
+	if place == "" {
+		place = say(session, "draft.location", Messages.Params{"Latitude": strconv.FormatFloat(session["latitude"].(float64), 'f', -1, 64), "Longitude": strconv.FormatFloat(session["longitude"].(float64), 'f', -1, 64)})
+	}
+	key := "draft.toGUC"
+	if session["fromGUC"].(bool) {
+		key = "draft.fromGUC"
+	}
+	shift, _ := session["shift"].(int)
+	vehicle := s.selectedVehicle(session)
+	return say(session, key, Messages.Params{"Place": place, "Time": sayTime(session, session["time"].(time.Time)) + shiftToString(session, shift), "Vehicle": vehicle.VehicleToString(), "Seats": session["availableSeats"].(int)})
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DirectionsAPI"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Sessions"
+)
+
+// Function that returns the address the server is reached at, from PUBLIC_URL or else from the request (eg. "https://carpool.guc.edu.eg").
+func publicURL(req *http.Request) string {
+	if url := os.Getenv("PUBLIC_URL"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	scheme := "http"
+	if req.TLS != nil || req.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + req.Host
+}
+
+// Function that gives the student the secret address of their calendar feed, or a new one if they ask for it.
+func (s *server) calendarLinkHandler(res http.ResponseWriter, session Session, baseURL string, comparable string) {
+	gucID := session["gucID"].(string)
+	user, _, err := s.users.Get(gucID)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	if user.GUCID == "" {
+		user = DB.User{GUCID: gucID, Name: session["name"].(string), Language: DB.LanguageEnglish}
+	}
+	renew := strings.Contains(comparable, "new") || strings.Contains(comparable, "reset")
+	if user.CalendarToken == "" || renew {
+		token, err := Sessions.NewToken()
+		if err == nil {
+			user.CalendarToken = token
+			err = s.users.Save(&user, DB.UserCalendarToken)
+		}
+		if err != nil {
+			writeJSON(res, JSON{
+				"message": say(session, "feed.linkError", nil),
+			})
+			return
+		}
+	}
+	reply := say(session, "feed.link", Messages.Params{"URL": baseURL + "/calendar/" + user.CalendarToken + ".ics"})
+	if renew {
+		reply = say(session, "feed.renewed", Messages.Params{"Link": reply})
+	} else {
+		reply += say(session, "feed.shareHint", nil)
+	}
+	writeJSON(res, JSON{
+		"message": reply,
+	})
+}
+
+// Function to handle the calendar route, serves the rides of the student with the token in the address as an iCalendar feed.
+func (s *server) serveCalendar(res http.ResponseWriter, req *http.Request) {
+	// Only listen to GET requests
+	if req.Method != http.MethodGet {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		writeJSON(res, JSON{
+			"message": "I'm sorry, but I can only listen to GET requests on this route.",
+		})
+		return
+	}
+	token := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/calendar/"), ".ics")
+	user, found, err := s.users.ByCalendarToken(token)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": "I couldn't find your calendar right now. Please try again in a moment.",
+		})
+		return
+	}
+	if token == "" || !found {
+		res.WriteHeader(http.StatusNotFound)
+		writeJSON(res, JSON{
+			"message": "There is no calendar at this address. You can get yours by typing 'calendar link' in the chat.",
+		})
+		return
+	}
+	events, err := rideEvents(user)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": Messages.Get(user.Language, "feed.ridesError", nil),
+		})
+		return
+	}
+	res.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	Calendar.WriteFeed(res, "GUC Carpool", events, time.Now())
+}
+
+// Function that lists the rides of the student that are not over yet, as the driver and as an accepted passenger, in their language.
+func rideEvents(user DB.User) ([]Calendar.FeedEvent, error) {
+	driving, err := driverCarpools(user.GUCID)
+	if err != nil {
+		return nil, err
+	}
+	riding, err := DB.GetPostsByPassenger(user.GUCID)
+	if err != nil {
+		return nil, err
+	}
+	events := []Calendar.FeedEvent{}
+	for _, carpoolRequest := range append(driving, riding...) {
+		events = append(events, rideEvent(user.Language, carpoolRequest, carpoolRequest.GUCID == user.GUCID))
+	}
+	return events, nil
+}
+
+// Function that describes a carpool as an event of the calendar feed: where it picks up, who drives, who rides and in which car.
+func rideEvent(language string, carpoolRequest DB.CarpoolRequest, driving bool) Calendar.FeedEvent {
+	postID := strconv.FormatUint(carpoolRequest.PostID, 10)
+	direction := Messages.Get(language, "feed.toGUC", nil)
+	if carpoolRequest.FromGUC {
+		direction = Messages.Get(language, "feed.fromGUC", nil)
+	}
+	driverName := DB.DisplayName(carpoolRequest.GUCID, carpoolRequest.Name)
+	summary := Messages.Get(language, "feed.riding", Messages.Params{"PostID": postID, "Driver": driverName, "Direction": direction})
+	if driving {
+		summary = Messages.Get(language, "feed.driving", Messages.Params{"PostID": postID, "Direction": direction})
+	}
+
+	place, err := DirectionsAPI.GetAddress(carpoolRequest.Latitude, carpoolRequest.Longitude)
+	if err != nil || place == "" {
+		place = Messages.Get(language, "feed.location", Messages.Params{"Latitude": strconv.FormatFloat(carpoolRequest.Latitude, 'f', -1, 64), "Longitude": strconv.FormatFloat(carpoolRequest.Longitude, 'f', -1, 64)})
+	}
+	location := place
+	description := Messages.Get(language, "feed.pickup", Messages.Params{"Place": place}) + "\n"
+	if carpoolRequest.FromGUC {
+		location = "German University in Cairo"
+		description = Messages.Get(language, "feed.pickupGUC", Messages.Params{"Place": place}) + "\n"
+	}
+	description += Messages.Get(language, "feed.driver", Messages.Params{"Driver": driverName}) + "\n"
+	if len(carpoolRequest.CurrentPassengers) > 0 {
+		names, _ := DB.GetUserNames(carpoolRequest.CurrentPassengers)
+		passengers := []string{}
+		for _, gucID := range carpoolRequest.CurrentPassengers {
+			if names[gucID] != "" {
+				passengers = append(passengers, names[gucID])
+			} else {
+				passengers = append(passengers, gucID)
+			}
+		}
+		description += Messages.Get(language, "feed.passengers", Messages.Params{"Passengers": strings.Join(passengers, ", ")}) + "\n"
+	}
+	if carpoolRequest.Vehicle.Plate != "" {
+		description += Messages.Get(language, "feed.car", Messages.Params{"Vehicle": carpoolRequest.Vehicle.VehicleToString()}) + "\n"
+	}
+
+	return Calendar.FeedEvent{
+		UID:         "carpool-" + postID + "@guc-carpool",
+		Start:       carpoolRequest.StartTime,
+		End:         carpoolRequest.StartTime.Add(rideLength),
+		Summary:     summary,
+		Location:    location,
+		Description: strings.TrimSuffix(description, "\n"),
+	}
+}
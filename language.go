@@ -0,0 +1,101 @@
+package main
+
+import (
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/When"
+)
+
+// Function that remembers the language the student chose in their profile, so every answer is written in it.
+func (s *server) loadLanguage(session Session) {
+	if _, loaded := session["language"]; loaded {
+		return
+	}
+	user, found, err := s.users.Get(session["gucID"].(string))
+	if err != nil {
+		return
+	}
+	session["language"] = DB.LanguageEnglish
+	if found && user.Language != "" {
+		session["language"] = user.Language
+	}
+}
+
+// Function that writes the message with the key in the language of the student.
+func say(session Session, key string, params Messages.Params) string {
+	language, _ := session["language"].(string)
+	return Messages.Get(language, key, params)
+}
+
+// Function that explains what went wrong in the language of the student.
+func sayError(session Session, err error) string {
+	language, _ := session["language"].(string)
+	return Messages.Error(language, err)
+}
+
+// Function that writes the time in the language of the student.
+func sayTime(session Session, t time.Time) string {
+	language, _ := session["language"].(string)
+	return Messages.Time(language, t)
+}
+
+// Function that writes the time or the window of time in the language of the student.
+func sayExpression(session Session, e When.Expression) string {
+	language, _ := session["language"].(string)
+	return Messages.Expression(language, e)
+}
+
+// Function that describes the carpool in the language of the student.
+func sayCarpool(session Session, carpoolRequest DB.CarpoolRequest) string {
+	language, _ := session["language"].(string)
+	return Messages.CarpoolToString(language, &carpoolRequest)
+}
+
+// Function that asks the student to confirm the time that was guessed from what they wrote, in their language.
+func sayQuestion(session Session, e When.Expression) string {
+	return say(session, "time.confirm", Messages.Params{"Time": sayExpression(session, e)})
+}
+
+// Function that returns the average rating of a student to show next to them, in the language of the student reading it.
+func sayRating(session Session, average DB.AverageRating) string {
+	language, _ := session["language"].(string)
+	return Messages.RatingToString(language, average)
+}
+
+// Function that writes the day of the time in the language of the student.
+func sayDay(session Session, t time.Time) string {
+	language, _ := session["language"].(string)
+	return Messages.Day(language, t)
+}
+
+// Function that describes the weekly schedule in the language of the student.
+func saySchedule(session Session, schedule DB.Schedule) string {
+	language, _ := session["language"].(string)
+	return Messages.ScheduleToString(language, schedule)
+}
+
+// Function that describes a ride the timetable of the student needs, in their language.
+func saySuggestion(session Session, suggestion DB.RideSubscription) string {
+	language, _ := session["language"].(string)
+	return Messages.SuggestionToString(language, suggestion)
+}
+
+// Function that describes the timetable of the student in their language.
+func sayClasses(session Session, classes []DB.Class) string {
+	language, _ := session["language"].(string)
+	return Messages.ClassesToString(language, classes)
+}
+
+// Function that describes the profile of the student in their language.
+func sayProfile(session Session, user *DB.User) string {
+	language, _ := session["language"].(string)
+	return Messages.UserToString(language, user)
+}
+
+// Function that writes the day of the week in the language of the student.
+func sayWeekday(session Session, day time.Weekday) string {
+	language, _ := session["language"].(string)
+	return Messages.Weekday(language, day)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+)
+
+// Function that archives the carpools that started more than ARCHIVE_AFTER minutes ago (default 60).
+func expireCarpools() error {
+	minutes, err := strconv.Atoi(os.Getenv("ARCHIVE_AFTER"))
+	if err != nil {
+		minutes = 60
+	}
+	expired, err := DB.ExpireCarpools(time.Now().Add(-time.Duration(minutes) * time.Minute))
+	if len(expired) > 0 {
+		log.Printf("archived %d carpools\n", len(expired))
+	}
+	for _, carpoolRequest := range expired {
+		if carpoolRequest.Status == DB.StatusCompleted {
+			askForRatings(carpoolRequest)
+		}
+	}
+	return err
+}
+
+// Function that emails the passengers that checked in to a ride that is over, asking them to rate the driver.
+func askForRatings(carpoolRequest DB.CarpoolRequest) {
+	driverName := DB.DisplayName(carpoolRequest.GUCID, carpoolRequest.Name)
+	for _, gucID := range carpoolRequest.CheckedIn {
+		sendEmail(gucID, "", Notifier.RateRide, Notifier.Data{"DriverName": driverName, "DriverID": carpoolRequest.GUCID, "PostID": carpoolRequest.PostID})
+	}
+}
+
+// Function that forgets the carpool a driver is editing, or the ones a passenger chose, once they are completed or cancelled.
+func forgetFinishedCarpools(session Session) error {
+	postID, postIDExists := session["postID"].(uint64)
+	if postIDExists {
+		finished, err := carpoolFinished(postID)
+		if err != nil {
+			return err
+		}
+		if finished {
+			forgetDraft(session)
+		}
+	}
+	for _, myChoice := range chosenCarpools(session) {
+		finished, err := carpoolFinished(myChoice)
+		if err != nil {
+			return err
+		}
+		if finished {
+			leaveCarpool(session, myChoice)
+		}
+	}
+	return nil
+}
+
+// Function that checks if a carpool is gone from the database, or is over.
+func carpoolFinished(postID uint64) (bool, error) {
+	carpoolRequests, err := DB.GetPostByID(postID)
+	if err != nil {
+		return false, err
+	}
+	if len(carpoolRequests) == 0 {
+		return true, nil
+	}
+	status := carpoolRequests[0].CurrentStatus()
+	return status == DB.StatusCompleted || status == DB.StatusCancelled, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Roster"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Verification"
+)
+
+// codeFormat : what a verification code looks like.
+var codeFormat = regexp.MustCompile(`^[0-9]{6}$`)
+
+// Function that handles the messages of a user that is not logged in. "GUCID:Name" emails a code to the student, and sending that code back logs them in.
+func (s *server) logInHandler(res http.ResponseWriter, uuid string, session Session, message string) {
+	if _, pending := session["pendingGucID"]; pending && codeFormat.MatchString(strings.TrimSpace(message)) {
+		s.verifyHandler(res, uuid, session, strings.TrimSpace(message))
+		return
+	}
+
+	// Separate gucID and name
+	login := strings.Split(message, ":")
+	if len(login) < 2 {
+		//res.WriteHeader(http.StatusUnauthorized)
+		writeJSON(res, JSON{
+			"message": say(session, "login.both", nil),
+		})
+		return
+	}
+	gucID := strings.TrimSpace(login[0])
+	name := strings.TrimSpace(login[1])
+	// Check if gucID and name are empty
+	if gucID == "" || name == "" {
+		//res.WriteHeader(http.StatusUnauthorized)
+		writeJSON(res, JSON{
+			"message": say(session, "login.empty", nil),
+		})
+		return
+	}
+	// Check if gucId is in a valid format (eg. 13-2456)
+	if !Roster.ValidGUCID(gucID) {
+		//	res.WriteHeader(http.StatusUnauthorized)
+		writeJSON(res, JSON{
+			"message": say(session, "login.invalidID", nil),
+		})
+		return
+	}
+	// Check that the student is on the roster, and call them by their official name.
+	student, ok := s.lookupStudent(res, session, gucID)
+	if !ok {
+		return
+	}
+	name = student.Name
+
+	// Send one code of a student at a time, so requests from many sessions can't get past the limit of codes.
+	unlock, err := s.locks.Lock("verify:" + strings.ToLower(gucID))
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "login.busy", nil),
+		})
+		return
+	}
+	defer unlock()
+
+	// Make sure the user is the student with this GUC ID before giving them anything of theirs.
+	address, err := s.verifier.SendCode(gucID)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "login.sendError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	session["pendingGucID"] = gucID
+	session["pendingName"] = name
+	writeJSON(res, JSON{
+		"message": say(session, "login.codeSent", Messages.Params{"Name": name, "Address": address}),
+	})
+}
+
+// Function that checks the code the user sent back, and logs them in if it is correct.
+func (s *server) verifyHandler(res http.ResponseWriter, uuid string, session Session, code string) {
+	gucID := session["pendingGucID"].(string)
+	name := session["pendingName"].(string)
+
+	// Check one code of a student at a time, so guesses from many sessions can't get past the attempt limit.
+	unlock, err := s.locks.Lock("verify:" + strings.ToLower(gucID))
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "login.busy", nil),
+		})
+		return
+	}
+	defer unlock()
+
+	err = s.verifier.Check(gucID, code)
+	if err == Verification.ErrWrongCode {
+		writeJSON(res, JSON{
+			"message": say(session, "login.wrongCode", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	if err != nil {
+		// The code can't be used anymore, the user has to start over.
+		delete(session, "pendingGucID")
+		delete(session, "pendingName")
+		writeJSON(res, JSON{
+			"message": say(session, "login.failed", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	delete(session, "pendingGucID")
+	delete(session, "pendingName")
+	s.logIn(res, uuid, session, gucID, name)
+}
+
+// Function that finds the active student with the GUC ID on the roster. If they are not there, it tells the user and returns false.
+func (s *server) lookupStudent(res http.ResponseWriter, session Session, gucID string) (DB.Student, bool) {
+	student, err := Roster.Lookup(s.roster, gucID)
+	if err == Roster.ErrNotFound || err == Roster.ErrInactive {
+		writeJSON(res, JSON{
+			"message": say(session, "login.notStudent", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return student, false
+	}
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "db.getError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return student, false
+	}
+	return student, true
+}
+
+// Function that logs in a verified student and welcomes them.
+func (s *server) logIn(res http.ResponseWriter, uuid string, session Session, gucID string, name string) {
+	err := s.restoreUser(uuid, session, gucID, name)
+	if err != nil {
+		//	res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": say(session, "db.getError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	// The student is greeted in the language of their profile.
+	s.loadLanguage(session)
+	writeJSON(res, JSON{
+		"message": say(session, "login.welcome", Messages.Params{"Name": name}),
+	})
+}
+
+// Function that fills the session of a verified student, bringing back their old session or their carpools from the database.
+func (s *server) restoreUser(uuid string, session Session, gucID string, name string) error {
+	// Find if an old session has the same user. If found, migrate the information from the old session to the new one, and delete the old one.
+	oldUUID, _, oldSession, err := s.sessions.FindByGUCID(gucID, uuid)
+	if err != nil {
+		return err
+	}
+	if oldSession {
+		oldSession, err = s.takeOverSession(oldUUID, session)
+		if err != nil {
+			return err
+		}
+	}
+	session.Touch(time.Now(), sessionTTL())
+	// The language is loaded from the profile again, it may have changed since the old session.
+	delete(session, "language")
+	session["gucID"] = gucID
+	session["name"] = name
+	session["verified"] = true
+	err = s.users.Ensure(gucID, name)
+	if err != nil {
+		return err
+	}
+	if oldSession {
+		return nil
+	}
+	// If no old session is found, check if user chose a carpool before, or both ways of a round trip. The carpools they drive are in the database.
+	passengerRequests, err := DB.QueryAllPassengerRequests()
+	if err != nil {
+		return err
+	}
+	chosen := []uint64{}
+	for i := 0; i < len(passengerRequests); i++ {
+		currentPassenger := passengerRequests[i]
+		if strings.EqualFold(currentPassenger.Passenger.GUCID, gucID) && currentPassenger.Notify != 0 && currentPassenger.Notify != 3 {
+			chosen = append(chosen, currentPassenger.PostID)
+		}
+	}
+	// The return trip is created after the first way, so it has the bigger ID.
+	sort.Slice(chosen, func(i, j int) bool { return chosen[i] < chosen[j] })
+	if len(chosen) > 0 {
+		session["myChoice"] = chosen[0]
+	}
+	if len(chosen) > 1 {
+		session["myLinkedChoice"] = chosen[1]
+	}
+	return nil
+}
+
+// Function that copies an old session of the user into the new one and deletes it. The old session is locked first, so a message it is handling can't save it back. Old sessions are logged in, so they never wait for the lock of the new one. Returns false if the old session was gone by then.
+func (s *server) takeOverSession(oldUUID string, session Session) (bool, error) {
+	unlock, err := s.locks.Lock("session:" + oldUUID)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	previous, found, err := s.sessions.Get(oldUUID)
+	if err != nil || !found {
+		return false, err
+	}
+	for key, val := range previous {
+		session[key] = val
+	}
+	return true, s.sessions.Delete(oldUUID)
+}
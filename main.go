@@ -1,12 +1,9 @@
 package main
 
 import (
-	"crypto/md5"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
-	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -15,10 +12,16 @@ import (
 	"strings"
 	"time"
 
-	"github.com/jinzhu/now"
-
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Command"
 	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
 	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DirectionsAPI"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Roster"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Scheduler"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Sessions"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/When"
 	cors "github.com/heppu/simple-cors"
 )
 
@@ -26,11 +29,18 @@ type (
 	// JSON models a json for sending and recieving in requests and responses
 	JSON map[string]interface{}
 	// Session models the session of a user
-	Session map[string]interface{}
+	Session = Sessions.Session
+)
+
+var (
+	notifier  = Notifier.New(Notifier.NewMailerFromEnv(), Roster.Address(Roster.DBStore{}), DB.IsEmailOptedOut)
+	reminders *Scheduler.Scheduler // set up in main, so a wrong REMINDER_OFFSETS stops the server with a clear error
 )
 
 var (
-	sessions = map[string]Session{}
+	greeting       = regexp.MustCompile(`\b(hi|hello)\b`)
+	checkInCommand = regexp.MustCompile(`\b(i'?m in|check in)\b`)
+	rateCommand    = regexp.MustCompile(`(?i)^rate\s+([0-9]+-[0-9]+)\s+([1-5])\b\s*(.*)$`)
 )
 
 // Main function to start the server and handle all incoming routes.
@@ -39,26 +49,51 @@ func main() {
 	if port == "" {
 		port = "8080"
 	}
+	srv := newServer(newSessionStore())
+	var err error
+	reminders, err = newReminderScheduler(srv.calendar)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = DB.EnsureIndexes()
+	if err != nil {
+		log.Fatal("could not create the database indexes: " + err.Error())
+	}
 	fmt.Println("GUC-Carpool server listening on port " + port)
 
-	now.TimeFormats = append(now.TimeFormats, "02 Jan 2006 15:04", "5/11/2017 8.30", "nov 5,2017 at 8.30")
-
-	mux := http.NewServeMux()
-	mux.HandleFunc("/welcome", serveAndLog(startSession))
-	mux.HandleFunc("/chat", serveAndLog(handleChat))
-	mux.HandleFunc("/", serveAndLog(serve))
+	// Send the departure reminders in the background
+	go reminders.Run(nil)
+	// Archive the carpools that already took place
+	go Scheduler.Every(5*time.Minute, nil, expireCarpools)
+	// Forget the sessions that expired
+	go Scheduler.Every(time.Minute, nil, srv.removeExpiredSessions)
+	// Create the carpools of the weekly schedules ahead of time
+	go Scheduler.Every(time.Hour, nil, srv.materializeSchedules)
 
 	// Start the server
-	log.Fatal(http.ListenAndServe(":"+port, cors.CORS(mux)))
+	log.Fatal(http.ListenAndServe(":"+port, cors.CORS(srv.routes())))
 
 }
 
+// Function that sets up the routes of a server instance.
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/welcome", serveAndLog(s.startSession))
+	mux.HandleFunc("/chat", serveAndLog(s.handleChat))
+	mux.HandleFunc("/logout", serveAndLog(s.endSession))
+	mux.HandleFunc("/login", serveAndLog(s.startSSO))
+	mux.HandleFunc("/callback", serveAndLog(s.finishSSO))
+	mux.HandleFunc("/calendar/", serveAndLog(s.serveCalendar))
+	mux.HandleFunc("/", serveAndLog(serve))
+	return mux
+}
+
 // Intermediary function that logs the current request and the status code attached to the response.
 func serveAndLog(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		res := httptest.NewRecorder()
 		handler(res, req)
-		log.Printf("[%d] %-4s %s\n", res.Code, req.Method, req.URL.Path)
+		log.Printf("[%d] %-4s %s\n", res.Code, req.Method, loggedPath(req.URL.Path))
 
 		for k, v := range res.HeaderMap {
 			w.Header()[k] = v
@@ -68,15 +103,23 @@ func serveAndLog(handler http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// Function that hides the secret token of a calendar feed, so whoever reads the logs can't subscribe to the rides of a student.
+func loggedPath(path string) string {
+	if strings.HasPrefix(path, "/calendar/") {
+		return "/calendar/…"
+	}
+	return path
+}
+
 // Default route handler.
 func serve(res http.ResponseWriter, req *http.Request) {
 	writeJSON(res, JSON{
-		"message": "Please use the route '/welcome' to log in, and the route '/chat' to talk.",
+		"message": "Please use the route '/welcome' or '/login' to log in, the route '/chat' to talk, and the route '/logout' to log out.",
 	})
 }
 
 // Function that creates the session variable and attaches a uuid (Unique user ID) to it.
-func startSession(res http.ResponseWriter, req *http.Request) {
+func (s *server) startSession(res http.ResponseWriter, req *http.Request) {
 	// Only listen to GET requests.
 	if req.Method != http.MethodGet {
 		res.WriteHeader(http.StatusMethodNotAllowed)
@@ -87,20 +130,57 @@ func startSession(res http.ResponseWriter, req *http.Request) {
 	}
 
 	// Create a new uuid
-	hasher := md5.New()
-	hasher.Write([]byte(strconv.FormatInt(time.Now().Unix(), 10)))
-	uuid := hex.EncodeToString(hasher.Sum(nil))
+	uuid, err := Sessions.NewToken()
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": "I couldn't start a session for you right now. Please try again in a moment.",
+		})
+		return
+	}
 
 	// Create a new session mapped to the new uuid and reply to the user.
-	sessions[uuid] = Session{}
+	session := Session{}
+	session.Touch(time.Now(), sessionTTL())
+	err = s.sessions.Save(uuid, session)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": "I couldn't start a session for you right now. Please try again in a moment.",
+		})
+		return
+	}
 	writeJSON(res, JSON{
 		"uuid":    uuid,
 		"message": "Welcome to GUC Carpool! Please tell me your GUC-ID and name",
 	})
 }
 
+// Function that logs the user out by removing their session.
+func (s *server) endSession(res http.ResponseWriter, req *http.Request) {
+	// Only listen to POST requests
+	if req.Method != http.MethodPost {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		writeJSON(res, JSON{
+			"message": "I'm sorry, but you didn't send any proper data with that " + req.Method + " request. I can only listen to POST requests on this route.",
+		})
+		return
+	}
+	err := s.sessions.Delete(req.Header.Get("Authorization"))
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": "I couldn't log you out right now. Please try again in a moment.",
+		})
+		return
+	}
+	writeJSON(res, JSON{
+		"message": "You're logged out. See you soon!",
+	})
+}
+
 // Function to handle the chat route
-func handleChat(res http.ResponseWriter, req *http.Request) {
+func (s *server) handleChat(res http.ResponseWriter, req *http.Request) {
 	// Only listen to POST requests
 	if req.Method != http.MethodPost {
 		res.WriteHeader(http.StatusMethodNotAllowed)
@@ -120,19 +200,63 @@ func handleChat(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	// Make sure the user's session is active.
-	session, sessionFound := sessions[uuid]
+	// Handle one message of a user at a time, even if they reach different server instances.
+	unlock, err := s.locks.Lock("session:" + uuid)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": "I'm still working on your last message. Please try again in a moment.",
+		})
+		return
+	}
+	defer unlock()
+
+	// Make sure the user's session exists and is active.
+	session, sessionFound, err := s.sessions.Get(uuid)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": "I couldn't find your session right now. Please try again in a moment.",
+		})
+		return
+	}
 	if !sessionFound {
+		//res.WriteHeader(http.StatusUnauthorized)
+		writeJSON(res, JSON{
+			"message": "I'm sorry, but I don't know this session. Please log in and try again.",
+		})
+		return
+	}
+	if session.Expired(time.Now()) {
+		err = s.sessions.Delete(uuid)
+		if err != nil {
+			res.WriteHeader(http.StatusInternalServerError)
+			writeJSON(res, JSON{
+				"message": "Your session has expired, but I couldn't end it right now. Please try again in a moment.",
+			})
+			return
+		}
 		//res.WriteHeader(http.StatusUnauthorized)
 		writeJSON(res, JSON{
 			"message": "I'm sorry, but your session has expired. Please log in and try again.",
 		})
 		return
 	}
+	session.Touch(time.Now(), sessionTTL())
+	// The session is a copy, save it once the message is handled.
+	loggedOut := false
+	defer func() {
+		if loggedOut {
+			return
+		}
+		err := s.sessions.Save(uuid, session)
+		if err != nil {
+			log.Printf("could not save session: %s\n", err.Error())
+		}
+	}()
 
 	// Make sure the data sent is in a JSON format.
 	data := JSON{}
-	err := json.NewDecoder(req.Body).Decode(&data)
+	err = json.NewDecoder(req.Body).Decode(&data)
 	if err != nil {
 		//res.WriteHeader(http.StatusBadRequest)
 		writeJSON(res, JSON{
@@ -152,104 +276,122 @@ func handleChat(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	// If user is not logged in, log them in.
-	_, loggedIn := session["gucID"]
-	if !loggedIn {
-		// Separate gucID and name
-		login := strings.Split(messageRecieved.(string), ":")
-		if len(login) < 2 {
-			//res.WriteHeader(http.StatusUnauthorized)
-			writeJSON(res, JSON{
-				"message": "Something went wrong. You have to give me both your name and your GUC-ID in order to successfully start your session.  It is so easy you just write them :D",
-			})
-			return
-		}
-		gucID := login[0]
-		name := login[1]
-		// Check if gucID and name are empty
-		if gucID == "" || name == "" {
-			//res.WriteHeader(http.StatusUnauthorized)
-			writeJSON(res, JSON{
-				"message": "Something went wrong. You have to give me both your name and your GUC-ID in order to successfully start your session. Please try again. I can not infer this Info but when I grow up I may.",
-			})
-			return
-		}
-		// Check if gucId is in a valid format (eg. 13-2456)
-		exp := regexp.MustCompile(`[0-9]+-[0-9]+`)
-		match := exp.MatchString(gucID)
-		if !match {
-			//	res.WriteHeader(http.StatusUnauthorized)
+	// Log the user out if they ask to.
+	if strings.EqualFold(strings.TrimSpace(messageRecieved.(string)), "logout") || strings.EqualFold(strings.TrimSpace(messageRecieved.(string)), "log out") {
+		loggedOut = true
+		err = s.sessions.Delete(uuid)
+		if err != nil {
+			res.WriteHeader(http.StatusInternalServerError)
 			writeJSON(res, JSON{
-				"message": "Your GUC ID is invalid. Are you sure you entered it correctly? type it correctly or I will keep anoying you with this message. (ex. '12-3456')",
+				"message": "I couldn't log you out right now. Please try again in a moment.",
 			})
 			return
 		}
-		oldSession := false
-		// Find if an old session has the same user. If found, migrate the information from the old session to the new one, and delete the old one.
-		for key1, val1 := range sessions {
-			currentGucID, currentIDFound := val1["gucID"]
-			if currentIDFound && strings.EqualFold(currentGucID.(string), gucID) && uuid != key1 {
-				for key2, val2 := range val1 {
-					session[key2] = val2
-				}
-				delete(sessions, key1)
-				oldSession = true
-				break
-			}
-		}
-		session["gucID"] = gucID
-		session["name"] = name
-		// If no old session is found, check if user has a previous carpool
-		if !oldSession {
-			carpoolRequests, err := DB.QueryAll()
-			passengerRequests, err2 := DB.QueryAllPassengerRequests()
-			if err != nil || err2 != nil {
-				//	res.WriteHeader(http.StatusInternalServerError)
-				writeJSON(res, JSON{
-					"message": "There was an error in getting your data from the database. Error: " + err.Error(),
-				})
-				return
-			}
-			for i := 0; i < len(carpoolRequests); i++ {
-				currentCarpool := carpoolRequests[i]
-				if strings.EqualFold(currentCarpool.GUCID, gucID) {
-					session["fromGUC"] = currentCarpool.FromGUC
-					session["latitude"] = currentCarpool.Latitude
-					session["longitude"] = currentCarpool.Longitude
-					session["time"] = currentCarpool.Time
-					session["availableSeats"] = currentCarpool.AvailableSeats
-					session["createComplete"] = true
-					session["postID"] = currentCarpool.PostID
-				}
-			}
-			for i := 0; i < len(passengerRequests); i++ {
-				currentPassenger := passengerRequests[i]
-				if strings.EqualFold(currentPassenger.Passenger.GUCID, gucID) && currentPassenger.Notify != 0 && currentPassenger.Notify != 3 {
-					session["myChoice"] = currentPassenger.PostID
-				}
+		writeJSON(res, JSON{
+			"message": "You're logged out. See you soon!",
+		})
+		return
+	}
+
+	// Sessions from before students were verified have to log in again, so nobody keeps acting as someone else.
+	if _, loggedIn := session["gucID"]; loggedIn && session["verified"] != true {
+		for key := range session {
+			if key != Sessions.ExpiresAtKey {
+				delete(session, key)
 			}
 		}
+	}
 
-		responseMessage := "Hello " + name + ". You can view all available carpools by typing 'view all', or 'view carpool' to view one you already have, cancel your request by typing 'cancel request', edit your request by typing 'edit request' or choose an available carpool by typing 'choose ID' where ID is the postID of the carpool of your choice. You can also choose to offer other people a ride by creating a carpool by typing 'create', or specify the details of a carpool you wish to request by typing 'request'. or view notifications for  your carpool or request by typing notify"
-		// responseNotify, err := getNotifications(session)
-		// if err != nil {
-		// 	responseNotify = "can not retrive your notifications right now  "
-		// }
+	// If user is not logged in, log them in.
+	_, loggedIn := session["gucID"]
+	if !loggedIn {
+		s.logInHandler(res, uuid, session, messageRecieved.(string))
+		return
+	}
+
+	// Answer in the language the student chose.
+	s.loadLanguage(session)
+
+	// Forget the carpools of the user that already took place.
+	err = forgetFinishedCarpools(session)
+	if err != nil {
 		writeJSON(res, JSON{
-			"message": responseMessage,
+			"message": say(session, "db.getError", Messages.Params{"Error": sayError(session, err)}),
 		})
 		return
 	}
 
+	// Commands written in Egyptian Arabic or in Franco-Arabic are read as English.
+	messageRecieved = Command.Translate(messageRecieved.(string))
+	data["message"] = messageRecieved
+
 	// See if the user wishes to interact with data from the database or edit his session.
 	comparable := strings.ToLower(messageRecieved.(string))
+	// A driver deleting a carpool with passengers in it was asked if they are sure.
+	if postID, confirming := session["confirmDelete"].(uint64); confirming {
+		delete(session, "confirmDelete")
+		if When.Confirms(comparable) {
+			carpoolRequest, ok := ownCarpool(res, session, strconv.FormatUint(postID, 10))
+			if ok {
+				s.deleteCarpool(res, session, carpoolRequest)
+			}
+			return
+		}
+		if When.Refuses(comparable) {
+			writeJSON(res, JSON{
+				"message": say(session, "delete.kept", Messages.Params{"PostID": strconv.FormatUint(postID, 10)}),
+			})
+			return
+		}
+	}
+
+	if undoCommand.MatchString(comparable) {
+		s.undoHandler(res, session)
+		return
+	}
+
 	//_, carpoolRequestFound := session["postID"]
 	//_, passengerRequestFound := session["myChoice"]
+	// Let the user see or edit their profile.
+	if _, editingProfile := session["profileStep"]; editingProfile || strings.Contains(comparable, "profile") {
+		s.profileHandler(res, session, messageRecieved.(string))
+		return
+	}
+
+	// Let the students upload their timetable, and request the rides it needs.
+	if strings.Contains(comparable, "timetable") || strings.Contains(comparable, "suggestion") {
+		s.timetableHandler(res, session, messageRecieved.(string))
+		return
+	}
+
+	// Give the students the secret link to the calendar feed of their rides.
+	if strings.Contains(comparable, "calendar link") {
+		s.calendarLinkHandler(res, session, publicURL(req), comparable)
+		return
+	}
+
+	// Let the admins close the campus.
+	if strings.HasPrefix(strings.TrimSpace(comparable), "close campus") || strings.HasPrefix(strings.TrimSpace(comparable), "close the campus") {
+		s.closeCampus(res, session, messageRecieved.(string))
+		return
+	}
+
+	// Let drivers repeat their carpools every week.
+	if strings.Contains(comparable, "schedule") || strings.HasPrefix(strings.TrimSpace(comparable), "repeat ") {
+		s.scheduleHandler(res, session, messageRecieved.(string))
+		return
+	}
+
+	if strings.HasPrefix(comparable, "rate ") {
+		rateHandler(res, session, messageRecieved.(string))
+		return
+	}
+
 	if /*(carpoolRequestFound || passengerRequestFound) &&*/ strings.Contains(comparable, "notifications") || strings.Contains(comparable, "notify") {
 		notifications, err := getNotifications(session)
 		if err != nil {
 			writeJSON(res, JSON{
-				"message": "I could not retrieve your notifications at the moment, please try again later.",
+				"message": say(session, "notifications.error", nil),
 			})
 			return
 		}
@@ -259,24 +401,57 @@ func handleChat(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if strings.Contains(comparable, "what can you do?") || strings.Contains(comparable, "hi") || strings.Contains(comparable, "hello") {
+	if strings.Contains(comparable, "stop emails") || strings.Contains(comparable, "start emails") {
+		optOut := strings.Contains(comparable, "stop emails")
+		err := DB.SetEmailOptOut(session["gucID"].(string), optOut)
+		if err != nil {
+			writeJSON(res, JSON{
+				"message": say(session, "emails.error", nil),
+			})
+			return
+		}
+		if optOut {
+			writeJSON(res, JSON{
+				"message": say(session, "emails.stopped", nil),
+			})
+			return
+		}
+		address, err := notifier.AddressOf(session["gucID"].(string), session["name"].(string))
+		if err != nil {
+			writeJSON(res, JSON{
+				"message": say(session, "emails.startedNoAddress", nil),
+			})
+			return
+		}
+		writeJSON(res, JSON{
+			"message": say(session, "emails.started", Messages.Params{"Address": address}),
+		})
+		return
+	}
+
+	if strings.Contains(comparable, "what can you do?") || greeting.MatchString(comparable) {
 		writeJSON(res, JSON{
-			"message": " You can view all available carpools by typing 'view all', or 'view carpool' to view one you already have, cancel your request by typing 'cancel request', edit your request by typing 'edit request' or choose an available carpool by typing 'choose ID' where ID is the postID of the carpool of your choice. You can also choose to offer other people a ride by creating a carpool by typing 'create', or specify the details of a carpool you wish to request by typing 'request'. or view notifications for  your carpool or request by typing notify.",
+			"message": say(session, "help", nil),
 		})
 		return
 	}
 
+	if strings.Contains(comparable, "start ride") || strings.Contains(comparable, "end ride") || checkInCommand.MatchString(comparable) {
+		rideHandler(res, session, comparable)
+		return
+	}
+
 	if strings.Contains(comparable, "edit") || strings.Contains(comparable, "cancel") || strings.Contains(comparable, "choose") || (strings.Contains(comparable, "view") && (strings.Contains(comparable, "all") || strings.Contains(comparable, "carpool"))) || strings.Contains(comparable, "delete") || strings.Contains(comparable, "reject") || strings.Contains(comparable, "accept") || strings.Contains(comparable, "directions") {
-		postRequestHandler(res, session, data)
+		s.postRequestHandler(res, session, data)
 		return
 	}
 
 	// See if user wishes to request or create a carpool.
-	finalResponse, err := processMessage(session, data["message"].(string))
+	finalResponse, err := s.processMessage(session, data["message"].(string))
 	if err != nil {
 		//res.WriteHeader(http.StatusUnprocessableEntity)
 		writeJSON(res, JSON{
-			"message": string(err.Error()),
+			"message": sayError(session, err),
 		})
 		return
 	}
@@ -287,48 +462,60 @@ func handleChat(res http.ResponseWriter, req *http.Request) {
 }
 
 // This function is used when the user is trying to create or request a carpool. It checks the proper variables, and calls one of two helper functions
-func processMessage(session Session, message string) (string, error) {
+func (s *server) processMessage(session Session, message string) (string, error) {
 	requestOrCreate, requestOrCreateFound := session["requestOrCreate"]
 	comparable := strings.ToLower(message)
+	if backCommand.MatchString(comparable) {
+		return s.stepBack(session)
+	}
 	if !requestOrCreateFound {
+		// A carpool or a request can be written in one message, then only the missing details are asked for.
+		if command := Command.Parse(message); command.Action != "" && (command.Details() || command.Time != "") {
+			return s.startCommand(session, command)
+		}
 		if strings.Contains(comparable, "create") || (strings.Contains(comparable, "offer")) {
-			_, postFound := session["postID"]
-			if postFound {
-				return "You already have a carpool! I have a very good memory, I can remember things you know :P. ", nil
-			}
+			forgetDraft(session)
 			session["requestOrCreate"] = "create"
-			return "You've chosen to create a carpool. Are you going to the GUC, or are you leaving the GUC?", nil
+			return say(session, "create.start", nil), nil
 		} else if strings.Contains(comparable, "request") || strings.Contains(comparable, "find") || strings.Contains(comparable, "join") {
+			forgetDraft(session)
 			session["requestOrCreate"] = "request"
-			return "You've chosen to request a carpool. Are you going to the GUC, or are you leaving the GUC?", nil
+			return say(session, "request.start", nil), nil
 		} else {
-			return "", fmt.Errorf("I'm sorry, but you didn't answer my question! Are you offering a ride? Or are you requesting One? I am not busy I can do this all day. type 'create' to make a carpool or 'request' to make a request")
+			return "", fmt.Errorf("%s", say(session, "start.unanswered", nil))
 		}
 	} else {
+		if _, oneShot := session["oneShot"]; oneShot {
+			return s.commandChat(session, message)
+		}
 		if requestOrCreate == "create" {
-			return createCarpoolChat(session, message)
+			return s.createCarpoolChat(session, message)
 		} else if requestOrCreate == "request" {
-			return requestCarpoolChat(session, message)
+			return s.requestCarpoolChat(session, message)
 		} else {
-			return "", fmt.Errorf("I don't seem to understand what your message '" + comparable + "' means. Can you please clarify?")
+			return "", fmt.Errorf("%s", say(session, "message.unclear", Messages.Params{"Message": comparable}))
 		}
 	}
 }
 
-func createCarpoolChat(session Session, message string) (string, error) {
+func (s *server) createCarpoolChat(session Session, message string) (string, error) {
 	comparable := strings.ToLower(message)
+	//offer to make the carpool he just created a round trip
+	if outboundID, askingReturn := session["returnOf"].(uint64); askingReturn {
+		return s.returnLeg(session, outboundID, message)
+	}
 	FromGUC, fromGUCFound := session["fromGUC"]
 
 	//check if he's going to the guc or leaving the guc
 	if !fromGUCFound {
 		if strings.Contains(comparable, "to guc") || strings.Contains(comparable, "to the guc") || strings.Contains(comparable, "going") {
 			session["fromGUC"] = false
-			return "You've chosen to create a carpool that's going to the GUC. Where can you pick up people?  Please tell me your desired location", nil
+			return say(session, "create.toGUC", nil), nil
 		} else if strings.Contains(comparable, "from guc") || strings.Contains(comparable, "from the guc") || strings.Contains(comparable, "leaving") {
 			session["fromGUC"] = true
-			return "You've chosen to create a carpool that's leaving the GUC. Where are you going? Please tell me your desired location", nil
+			return say(session, "create.fromGUC", nil), nil
 		} else {
-			return "I'm sorry you didn't answer my question. Are you going to the GUC or leaving the GUC? (ex. if you're leaving you can type 'from guc' or if you're going to campus you can type 'to guc'.", nil
+			return say(session, "direction.unanswered", nil), nil
 		}
 	}
 
@@ -342,84 +529,121 @@ func createCarpoolChat(session Session, message string) (string, error) {
 			session["longitude"], _ = strconv.ParseFloat(exp.FindAllString(comparable, -1)[1], 64)
 			address, err := DirectionsAPI.GetAddress(session["latitude"].(float64), session["longitude"].(float64))
 			if err != nil || address == "" {
-				return "You chose the location with the latitude " + strconv.FormatFloat(session["latitude"].(float64), 'f', -1, 64) + ", and the longitude " + strconv.FormatFloat(session["longitude"].(float64), 'f', -1, 64) + ". What time would you like to your ride to be?", nil
+				return say(session, "location.coordinates", Messages.Params{"Latitude": strconv.FormatFloat(session["latitude"].(float64), 'f', -1, 64), "Longitude": strconv.FormatFloat(session["longitude"].(float64), 'f', -1, 64)}), nil
 			}
-			return "You chose the location with the address " + address + " . What time would you like to your ride to be?", nil
+			return say(session, "location.address", Messages.Params{"Address": address}), nil
 		}
-		var response string
+		question := say(session, "create.wherePickUp", nil)
 		if FromGUC.(bool) {
-			response = "Where are you going?"
-		} else {
-			response = "Where can you pick up people?"
+			question = say(session, "create.whereGoing", nil)
 		}
-		return "", fmt.Errorf("I'm sorry, but you didn't answer my question! " + response + " Please tell me your desired location")
+		return "", fmt.Errorf("%s", say(session, "location.unanswered", Messages.Params{"Question": question}))
 	}
 
 	//take his start time
-	requestTime, requestTimeFound := session["timereq"]
-	stTime, timeFound := session["time"]
+	_, timeFound := session["time"]
 	if !timeFound && fromGUCFound && latitudeFound && longitudeFound {
-		stTime, err := now.Parse(message)
+		expression, question, err := readRideTime(session, message)
 		if err != nil {
-			return "", fmt.Errorf("This is not a valid time format. Can you please tell me again when you want your ride to be? ")
+			return "", fmt.Errorf("%s", say(session, "time.invalid", Messages.Params{"Error": sayError(session, err)}))
 		}
-		if requestTimeFound {
-			duration := stTime.Sub(requestTime.(time.Time))
-			if math.Abs(duration.Hours()) <= 4 {
-
-				return "", fmt.Errorf("You already have a carpool around that same time! Did you forget? Please choose a different time")
-			}
+		if question != "" {
+			return question, nil
 		}
-		now := time.Now()
-		valid := stTime.After(now)
-		if !valid {
-			return "", fmt.Errorf("This time doesn't make sense! You need to choose a time in the future. I am not that dumb you know")
+		stTime, shift, err := s.checkCarpoolTime(session, expression)
+		if err != nil {
+			return "", err
 		}
 		session["time"] = stTime
-		return "You want your ride to take place around " + (session["time"].(time.Time)).Format("Jan 2, 2006 at 3:04pm (EET)") + ". How many passengers can you take with you?", nil
+		session["shift"] = shift
+		vehicleQuestion, err := s.askVehicle(session)
+		if err != nil {
+			return "", err
+		}
+		return say(session, "create.time", Messages.Params{"Time": sayTime(session, session["time"].(time.Time)) + shiftToString(session, shift), "Question": vehicleQuestion}), nil
+	}
+
+	//take the car he's driving
+	_, vehicleFound := session["vehiclePlate"]
+	if !vehicleFound && timeFound && latitudeFound && longitudeFound && fromGUCFound {
+		return s.chooseVehicle(session, message)
 	}
 
 	//take how many available seats
 	_, availableSeatsFound := session["availableSeats"]
-	if !availableSeatsFound && timeFound && latitudeFound && longitudeFound && fromGUCFound {
-		if !(strings.Contains(comparable, "4")) && !(strings.Contains(comparable, "3")) && !(strings.Contains(comparable, "2")) && !(strings.Contains(comparable, "1")) {
-			return "you can only have 1-4 passengers, not including yourself. Please enter a valid number!", nil
-		}
-		exp := regexp.MustCompile(`[1-4]`)
-		number0, _ := strconv.ParseInt(exp.FindAllString(comparable, -1)[0], 10, 64)
-		number := int(number0)
-		session["availableSeats"] = number
-
-		_, postFound := session["postID"]
-		if !postFound {
-			C, err := DB.NewCarpool(session["gucID"].(string), session["longitude"].(float64), session["latitude"].(float64), session["name"].(string), FromGUC.(bool), session["availableSeats"].(int), stTime.(time.Time).Format("Jan 2, 2006 at 3:04pm (EET)"))
-			if err != nil {
-				return "", fmt.Errorf("An error occured when creating your carpool. Please try again later")
-			}
-			//insert that new carpool into the database
-			err = DB.InsertDB(&C)
-			if err != nil {
-				return "", fmt.Errorf("An error occured while inserting into the database. Error: " + err.Error())
-			}
-			session["createComplete"] = true
-			session["postID"] = C.PostID
-			session["currentPassengers"] = C.CurrentPassengers
-			session["possiblePassengers"] = C.PossiblePassengers
-		} else {
-			err := DB.UpdateDB(session["postID"].(uint64), session["longitude"].(float64), session["latitude"].(float64), session["fromGUC"].(bool), session["availableSeats"].(int), session["currentPassengers"].([]string), session["possiblePassengers"].([]string), stTime.(time.Time))
-			if err != nil {
-				return "", fmt.Errorf("An error occured when creating your carpool. Please try again later Error: " + err.Error())
-			}
+	if !availableSeatsFound && timeFound && vehicleFound && latitudeFound && longitudeFound && fromGUCFound {
+		// The seats can't be more than the car has left after the passengers already accepted.
+		currentPassengers, _ := session["currentPassengers"].([]string)
+		seatsLeft := session["capacity"].(int) - len(currentPassengers)
+		if seatsLeft < 1 {
+			return "", fmt.Errorf("%s", say(session, "seats.full", nil))
 		}
-		delete(session, "requestOrCreate")
-		return "You've chosen to take up to " + strconv.FormatInt(number0, 10) + " more passengers. Your carpool is now complete! You can see it by typing 'view carpool'.", nil
+		number0, err := strconv.ParseInt(number.FindString(comparable), 10, 64)
+		if err != nil || number0 < 1 || int(number0) > seatsLeft {
+			return say(session, "seats.invalid", Messages.Params{"Seats": seatsLeft}), nil
+		}
+		session["availableSeats"] = int(number0)
+		return s.saveCarpool(session)
 	}
 
-	return "I did not understand what you said. I am only a computer after all.", nil
+	return say(session, "create.unclear", nil), nil
+}
+
+// Function that creates the carpool the driver finished writing, or saves the changes to the one they are editing.
+func (s *server) saveCarpool(session Session) (string, error) {
+	stTime := session["time"].(time.Time)
+	seats := strconv.Itoa(session["availableSeats"].(int))
+	vehicle := s.selectedVehicle(session)
+	shift, _ := session["shift"].(int)
+
+	postID, postFound := session["postID"].(uint64)
+	if !postFound {
+		C, err := DB.NewCarpool(session["gucID"].(string), session["longitude"].(float64), session["latitude"].(float64), session["name"].(string), session["fromGUC"].(bool), session["availableSeats"].(int), stTime)
+		if err != nil {
+			return "", fmt.Errorf("%s", say(session, "create.error", nil))
+		}
+		C.Vehicle = vehicle
+		C.Shift = shift
+		//insert that new carpool into the database
+		err = DB.InsertDB(&C)
+		if err != nil {
+			return "", fmt.Errorf("%s", say(session, "create.insertError", Messages.Params{"Error": sayError(session, err)}))
+		}
+		scheduleReminders(C.PostID, C.StartTime)
+		s.notifySubscribers(C)
+		session["returnOf"] = C.PostID
+		carpoolID := strconv.FormatUint(C.PostID, 10)
+		return say(session, "create.complete", Messages.Params{"Seats": seats, "PostID": carpoolID}) + " " + returnQuestion(session), nil
+	}
+	// The passengers may have changed while the driver was editing, so take them from the database.
+	carpoolRequests, err := DB.GetPostByID(postID)
+	if err != nil {
+		return "", fmt.Errorf("%s", say(session, "create.updateError", Messages.Params{"Error": sayError(session, err)}))
+	}
+	if len(carpoolRequests) == 0 {
+		forgetDraft(session)
+		return "", fmt.Errorf("%s", say(session, "carpool.gone", nil))
+	}
+	err = DB.UpdateDB(postID, session["longitude"].(float64), session["latitude"].(float64), session["fromGUC"].(bool), session["availableSeats"].(int), carpoolRequests[0].CurrentPassengers, carpoolRequests[0].PossiblePassengers, stTime)
+	if err != nil {
+		return "", fmt.Errorf("%s", say(session, "create.updateError", Messages.Params{"Error": sayError(session, err)}))
+	}
+	err = DB.SetCarpoolVehicle(postID, vehicle)
+	if err != nil {
+		return "", fmt.Errorf("%s", say(session, "create.updateError", Messages.Params{"Error": sayError(session, err)}))
+	}
+	err = DB.SetCarpoolShift(postID, shift)
+	if err != nil {
+		return "", fmt.Errorf("%s", say(session, "create.updateError", Messages.Params{"Error": sayError(session, err)}))
+	}
+	scheduleReminders(postID, stTime)
+	forgetDraft(session)
+	carpoolID := strconv.FormatUint(postID, 10)
+	return say(session, "create.complete", Messages.Params{"Seats": seats, "PostID": carpoolID}), nil
 }
 
 // Function to handle the specifics that the user wants in the carpool he requested.
-func requestCarpoolChat(session Session, message string) (string, error) {
+func (s *server) requestCarpoolChat(session Session, message string) (string, error) {
 	// Check if user is going to or leaving the GUC.
 	fromGUC, fromGUCFound := session["fromGUCreq"]
 	comparable := strings.ToLower(message)
@@ -427,12 +651,12 @@ func requestCarpoolChat(session Session, message string) (string, error) {
 	if !fromGUCFound {
 		if strings.Contains(comparable, "going to") || strings.Contains(comparable, "to guc") {
 			session["fromGUCreq"] = false
-			return "You've chosen to find a carpool going to the GUC! Where would you like to be picked up from? Please tell me your desired location.", nil
+			return say(session, "request.toGUC", nil), nil
 		} else if strings.Contains(comparable, "leaving") || strings.Contains(comparable, "from guc") {
 			session["fromGUCreq"] = true
-			return "You chose to leave the campus. Where would you like to go? Please tell me your desired location", nil
+			return say(session, "request.fromGUC", nil), nil
 		} else {
-			return "I'm sorry you didn't answer my question. Are you going to the GUC or leaving the GUC? (ex. if you're leaving you can type 'from guc' or if you're going to campus you can type 'to guc'.", nil
+			return say(session, "direction.unanswered", nil), nil
 		}
 	}
 
@@ -445,111 +669,136 @@ func requestCarpoolChat(session Session, message string) (string, error) {
 			exp := regexp.MustCompile(`[0-9]+[\.]?[0-9]*`)
 			session["latitudereq"] = exp.FindAllString(message, -1)[0]
 			session["longitudereq"] = exp.FindAllString(message, -1)[1]
+			// The time was already taken from the timetable, so the request is complete.
+			if _, timeChosen := session["timereq"]; timeChosen {
+				return completeRequest(session), nil
+			}
 			lat, _ := strconv.ParseFloat(session["latitudereq"].(string), 64)
 			lon, _ := strconv.ParseFloat(session["longitudereq"].(string), 64)
 			address, err := DirectionsAPI.GetAddress(lat, lon)
 			if err != nil || address == "" {
-				return "You chose the location with the latitude " + session["latitudereq"].(string) + ", and the longitude " + session["longitudereq"].(string) + ". What time would you like to your ride to be?", nil
+				return say(session, "location.coordinates", Messages.Params{"Latitude": session["latitudereq"].(string), "Longitude": session["longitudereq"].(string)}), nil
 			}
-			return "You chose the location with the address " + address + " . What time would you like to your ride to be?", nil
+			return say(session, "location.address", Messages.Params{"Address": address}), nil
 		}
-		var ret string
+		question := say(session, "request.wherePickUp", nil)
 		if fromGUC.(bool) {
-			ret = "Where would you like to go?"
-		} else {
-			ret = "Where would you like to be picked up from?"
+			question = say(session, "request.whereGo", nil)
 		}
-		return "", fmt.Errorf("I'm sorry, but you didn't answer my question! " + ret + " Please tell me your desired location.")
+		return "", fmt.Errorf("%s", say(session, "location.unanswered", Messages.Params{"Question": question}))
 	}
 
 	// Get the time the user wants to leave.
-	createTime, createTimeFound := session["time"]
 	_, timeFound := session["timereq"]
 	if !timeFound && fromGUCFound && latitudeFound && longitudeFound {
-		stTime, err := now.Parse(message)
+		expression, question, err := readRideTime(session, message)
 		if err != nil {
-			return "", fmt.Errorf("This is not a valid time format. Can you please tell me again when you want your ride to be? One possible format you can use is 'yyyy-mm-dd hh:mm'")
+			return "", fmt.Errorf("%s", say(session, "time.invalid", Messages.Params{"Error": sayError(session, err)}))
 		}
-		if createTimeFound {
-			duration := stTime.Sub(createTime.(time.Time))
-			if math.Abs(duration.Hours()) <= 4 {
-				return "", fmt.Errorf("You already have a carpool around that same time! You can't be in two places at once! Please choose a different time")
-			}
+		if question != "" {
+			return question, nil
 		}
-		now := time.Now()
-		valid := stTime.After(now)
-		if !valid {
-			return "", fmt.Errorf("This time doesn't make sense! You need to choose a time in the future! I do not have a time machine")
+		err = s.checkRequestTime(session, expression)
+		if err != nil {
+			return "", err
+		}
+		session["timereq"] = expression.Start
+		if expression.Window() {
+			session["timereqEnd"] = expression.End
 		}
-		session["timereq"] = stTime
 	}
 
 	// The user's request is complete. Set and delete the proper session variables.
 	_, timeFound = session["timereq"]
 	if timeFound && fromGUCFound && latitudeFound && longitudeFound {
-		details := getDetails(session)
-		session["requestComplete"] = true
-		delete(session, "requestOrCreate")
-		return "Your request is complete! Here are the details: " + details + " You can now view all carpools, cancel your request, edit your request or choose one of the available carpools. So, what do you want to do?", nil
+		return completeRequest(session), nil
 	}
 
-	return "Looks like you have a carpool already. You can edit it if you want.", nil
+	return say(session, "request.unclear", nil), nil
 }
 
-func postRequestHandler(res http.ResponseWriter, session Session, data JSON) {
+// Function that completes the request of the passenger once it has all its details.
+func completeRequest(session Session) string {
+	details := getDetails(session)
+	session["requestComplete"] = true
+	delete(session, "requestOrCreate")
+	return say(session, "request.complete", Messages.Params{"Details": details})
+}
+
+func (s *server) postRequestHandler(res http.ResponseWriter, session Session, data JSON) {
 	_, requestExists := session["requestComplete"]
-	_, createFound := session["createComplete"]
-	postID, postIDExists := session["postID"]
 	comparable := strings.ToLower(data["message"].(string))
 	if strings.Contains(comparable, "view all") {
 		allRequests, err := DB.QueryAll()
 		if err != nil {
 			//res.WriteHeader(http.StatusInternalServerError)
 			writeJSON(res, JSON{
-				"message": "There was an error while retrieving the data from our database. Error: " + err.Error(),
+				"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
 			})
 			return
 		}
+		// Carpools on the days the GUC is closed won't take place, so they are left out.
+		year, err := Calendar.Load(s.calendar)
+		if err != nil {
+			log.Printf("could not load the academic calendar: %s\n", err.Error())
+		}
+		// The carpools that fit the request of the passenger come first.
 		cpString := ""
-		for i := 1; i < len(allRequests); i++ {
-			cpString += allRequests[i].CarpoolToString() + ",\n"
+		fitting := ""
+		open := 0
+		for i := 0; i < len(allRequests); i++ {
+			if _, closed := year.Closed(allRequests[i].StartTime); closed {
+				continue
+			}
+			if requestExists && fitsRequest(session, allRequests[i]) {
+				fitting += sayCarpool(session, allRequests[i]) + ",\n"
+			} else {
+				cpString += sayCarpool(session, allRequests[i]) + ",\n"
+			}
+			open++
 		}
-		if len(allRequests) < 2 {
+		if open == 0 {
 			writeJSON(res, JSON{
-				"message": "Oops no current carpool offers available looks like you'll be walking. :P",
+				"message": say(session, "viewAll.none", nil),
 			})
 			return
 		}
+		reply := say(session, "viewAll.all", nil) + "\n" + cpString
+		if requestExists && fitting == "" {
+			reply = say(session, "viewAll.noneFit", nil) + "\n" + cpString
+		} else if requestExists {
+			reply = say(session, "viewAll.fitting", nil) + "\n" + fitting
+			if cpString != "" {
+				reply += say(session, "viewAll.others", nil) + "\n" + cpString
+			}
+		}
 		writeJSON(res, JSON{
-			"message": "Here are all the available carpools!\n" + cpString,
+			"message": reply,
 		})
 		return
 	} else if strings.Contains(comparable, "edit") && strings.Contains(comparable, "request") {
-		delete(session, "fromGUCreq")
-		delete(session, "latitudereq")
-		delete(session, "longitudereq")
-		delete(session, "timereq")
+		forgetRequest(session)
 		if !requestExists {
 			//res.WriteHeader(http.StatusUnauthorized)
 			writeJSON(res, JSON{
-				"message": "You can't edit a request if you don't have one. I am starting to doubt your inteligence.",
+				"message": say(session, "request.editNone", nil),
 			})
 			return
 		}
-		delete(session, "requestComplete")
 		session["requestOrCreate"] = "request"
 		writeJSON(res, JSON{
-			"message": "You chose to edit your carpool request. Let's do this piece by piece. Firstly, are you going to the GUC, or are you leaving campus?",
+			"message": say(session, "request.edit", nil),
 		})
 		return
 	} else if strings.Contains(comparable, "cancel") && strings.Contains(comparable, "request") {
-		delete(session, "fromGUCreq")
-		delete(session, "latitudereq")
-		delete(session, "longitudereq")
-		delete(session, "timereq")
-		delete(session, "requestComplete")
+		// The passenger can undo cancelling, so what it forgets is kept.
+		kept := keep(session, cancelledKeys)
+		leftCarpools := []uint64{}
+		acceptedIn := []uint64{}
+		forgetRequest(session)
 		delete(session, "requestOrCreate")
 		previousChoice, myChoiceExists := session["myChoice"]
+		stillInLinked := false
 
 		if myChoiceExists {
 			gucID := session["gucID"].(string)
@@ -557,17 +806,17 @@ func postRequestHandler(res http.ResponseWriter, session Session, data JSON) {
 			if err != nil {
 				//	res.WriteHeader(http.StatusInternalServerError)
 				writeJSON(res, JSON{
-					"message": "There was an error while retrieving the data from our database. Error: " + err.Error(),
+					"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
 				})
 				return
 			}
-			delete(session, "myChoice")
+			stillInLinked = leaveCarpool(session, previousChoice.(uint64))
 
 			wasCurrent := false
 			if len(carpoolRequests) == 0 {
 				//	res.WriteHeader(http.StatusInternalServerError)
 				writeJSON(res, JSON{
-					"message": "No posts exist with this ID. It must have been deleted!",
+					"message": say(session, "cancel.gone", nil),
 				})
 				return
 			}
@@ -578,16 +827,21 @@ func postRequestHandler(res http.ResponseWriter, session Session, data JSON) {
 			for idx, val := range possiblePassengers {
 				if strings.EqualFold(val, gucID) {
 					possiblePassengers = append(possiblePassengers[:idx], possiblePassengers[idx+1:]...)
-					wasCurrent = true
 					break
 				}
 			}
+			// Only an accepted passenger had a seat to give back.
 			for idx, val := range currentPassengers {
 				if strings.EqualFold(val, gucID) {
 					currentPassengers = append(currentPassengers[:idx], currentPassengers[idx+1:]...)
+					wasCurrent = true
 					break
 				}
 			}
+			leftCarpools = append(leftCarpools, previousChoice.(uint64))
+			if wasCurrent {
+				acceptedIn = append(acceptedIn, previousChoice.(uint64))
+			}
 			availableSeats := carpoolRequest.AvailableSeats
 			if wasCurrent {
 				availableSeats++
@@ -596,7 +850,7 @@ func postRequestHandler(res http.ResponseWriter, session Session, data JSON) {
 			if err != nil {
 				//	res.WriteHeader(http.StatusInternalServerError)
 				writeJSON(res, JSON{
-					"message": "There was an error removing you from the carpool. Error: " + err.Error(),
+					"message": say(session, "cancel.leaveError", Messages.Params{"Error": sayError(session, err)}),
 				})
 				return
 			}
@@ -604,14 +858,14 @@ func postRequestHandler(res http.ResponseWriter, session Session, data JSON) {
 			if err != nil {
 				//	res.WriteHeader(http.StatusInternalServerError)
 				writeJSON(res, JSON{
-					"message": "There was an error while retrieving the data from our database. Error: " + err.Error(),
+					"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
 				})
 				return
 			}
 			if len(passengerRequests) == 0 {
 				//res.WriteHeader(http.StatusUnauthorized)
 				writeJSON(res, JSON{
-					"message": "I can't find your carpool request. Please try again in a moment",
+					"message": say(session, "cancel.notFound", nil),
 				})
 				return
 			}
@@ -623,15 +877,22 @@ func postRequestHandler(res http.ResponseWriter, session Session, data JSON) {
 					if err != nil {
 						//	res.WriteHeader(http.StatusInternalServerError)
 						writeJSON(res, JSON{
-							"message": "I can't update your information in our database. Error: " + err.Error(),
+							"message": say(session, "cancel.updateError", Messages.Params{"Error": sayError(session, err)}),
 						})
 						return
 					}
 				}
 			}
 		}
+		rememberUndo(session, undoCancel, leftCarpools, acceptedIn, "", kept)
+		if stillInLinked {
+			writeJSON(res, JSON{
+				"message": say(session, "cancel.leftOneWay", Messages.Params{"PostID": strconv.FormatUint(previousChoice.(uint64), 10), "LinkedPostID": strconv.FormatUint(session["myChoice"].(uint64), 10)}) + undoHint(session),
+			})
+			return
+		}
 		writeJSON(res, JSON{
-			"message": "Your carpool request has been cancelled successfully. You can now start over. Do you want to request a carpool, or are you offering one?",
+			"message": say(session, "cancel.done", nil) + undoHint(session) + " " + say(session, "cancel.startOver", nil),
 		})
 		return
 	} else if strings.Contains(comparable, "choose") {
@@ -639,17 +900,15 @@ func postRequestHandler(res http.ResponseWriter, session Session, data JSON) {
 		if myChoiceExists {
 			//	res.WriteHeader(http.StatusForbidden)
 			writeJSON(res, JSON{
-				"message": "You already chose a carpool. Please cancel before choosing a new one. You can only be in one carpool at a time.",
+				"message": say(session, "choose.already", nil),
 			})
 			return
 		}
-		exp := regexp.MustCompile(`[0-9]+`)
-		postID := exp.FindString(comparable)
-		postIDint, err := strconv.ParseUint(postID, 10, 64)
-		if err != nil {
+		postIDint, found := postIDIn(comparable)
+		if !found {
 			//	res.WriteHeader(http.StatusUnprocessableEntity)
 			writeJSON(res, JSON{
-				"message": "There was an error when converting the postID from string to int. Please try again.",
+				"message": say(session, "choose.badID", nil),
 			})
 			return
 		}
@@ -658,210 +917,359 @@ func postRequestHandler(res http.ResponseWriter, session Session, data JSON) {
 		if err != nil {
 			//	res.WriteHeader(http.StatusInternalServerError)
 			writeJSON(res, JSON{
-				"message": "There was an error while retrieving the data from our database. Error: " + err.Error(),
+				"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
 			})
 			return
 		}
 		if len(carpoolRequests) < 1 {
 			writeJSON(res, JSON{
-				"message": "not a valid post ID. I will say it was a typo! Try again like this 'choose ID' but replace ID with the post number!",
+				"message": say(session, "choose.invalid", nil),
 			})
 			return
 		}
-		myDetails, err := DB.NewPassengerRequest(session["gucID"].(string), session["name"].(string), postIDint, 1)
-		if err != nil {
-			//	res.WriteHeader(http.StatusInternalServerError)
+		// Join both ways of a round trip if the passenger asks to.
+		if strings.Contains(comparable, "both") || strings.Contains(comparable, "round trip") {
+			if carpoolRequests[0].LinkedPostID == 0 {
+				writeJSON(res, JSON{
+					"message": say(session, "choose.oneWay", Messages.Params{"PostID": strconv.FormatUint(postIDint, 10)}),
+				})
+				return
+			}
+			linked, err := DB.GetPostByID(carpoolRequests[0].LinkedPostID)
+			if err != nil {
+				writeJSON(res, JSON{
+					"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
+				})
+				return
+			}
+			// The other way was deleted in the meantime.
+			if len(linked) == 0 {
+				writeJSON(res, JSON{
+					"message": say(session, "choose.oneWay", Messages.Params{"PostID": strconv.FormatUint(postIDint, 10)}),
+				})
+				return
+			}
+			carpoolRequests = append(carpoolRequests, linked[0])
+			// The leg that starts first is the one to check in to first.
+			if carpoolRequests[1].StartTime.Before(carpoolRequests[0].StartTime) {
+				carpoolRequests[0], carpoolRequests[1] = carpoolRequests[1], carpoolRequests[0]
+			}
+		}
+		// Both ways are checked before joining any, so the passenger doesn't end up in only one of them.
+		for _, carpoolRequest := range carpoolRequests {
+			err := s.canJoin(session, carpoolRequest)
+			if err != nil {
+				writeJSON(res, JSON{
+					"message": sayError(session, err),
+				})
+				return
+			}
+		}
+		for i, carpoolRequest := range carpoolRequests {
+			err := s.joinCarpool(session, carpoolRequest)
+			if err != nil {
+				if i > 0 {
+					undoJoin(session, carpoolRequests[0])
+					delete(session, "myChoice")
+				}
+				writeJSON(res, JSON{
+					"message": sayError(session, err),
+				})
+				return
+			}
+			if i == 0 {
+				session["myChoice"] = carpoolRequest.PostID
+			} else {
+				session["myLinkedChoice"] = carpoolRequest.PostID
+			}
+		}
+		if len(carpoolRequests) > 1 {
 			writeJSON(res, JSON{
-				"message": "There was an error while creating your information. Error: " + err.Error(),
+				"message": say(session, "choose.both", Messages.Params{"PostID": strconv.FormatUint(carpoolRequests[0].PostID, 10), "LinkedPostID": strconv.FormatUint(carpoolRequests[1].PostID, 10)}),
 			})
 			return
 		}
-
-		carpoolRequest := carpoolRequests[0]
-		if strings.EqualFold(carpoolRequest.GUCID, session["gucID"].(string)) {
-			//	res.WriteHeader(http.StatusForbidden)
+		writeJSON(res, JSON{
+			"message": say(session, "choose.done", nil),
+		})
+		return
+	} else if strings.Contains(comparable, "delete") && strings.Contains(comparable, "carpool") {
+		carpoolRequest, ok := ownCarpool(res, session, comparable)
+		if !ok {
+			return
+		}
+		// Deleting a carpool lets down the passengers already accepted in it, so the driver is asked to be sure.
+		if len(carpoolRequest.CurrentPassengers) > 0 {
+			session["confirmDelete"] = carpoolRequest.PostID
 			writeJSON(res, JSON{
-				"message": "You can't join your own carpool! I mean ... why would you even do that?",
+				"message": say(session, "delete.confirm", Messages.Params{"Count": len(carpoolRequest.CurrentPassengers), "PostID": strconv.FormatUint(carpoolRequest.PostID, 10), "Passengers": strings.Join(carpoolRequest.CurrentPassengers, ", ")}),
 			})
 			return
 		}
-		//insert after check
-		err = DB.InsertPassengerRequest(&myDetails)
+		s.deleteCarpool(res, session, carpoolRequest)
+		return
+	} else if strings.Contains(comparable, "edit") && strings.Contains(comparable, "carpool") {
+		carpoolRequest, ok := ownCarpool(res, session, comparable)
+		if !ok {
+			return
+		}
+		forgetDraft(session)
+		session["postID"] = carpoolRequest.PostID
+		session["currentPassengers"] = carpoolRequest.CurrentPassengers
+		session["requestOrCreate"] = "create"
+		writeJSON(res, JSON{
+			"message": say(session, "edit.start", Messages.Params{"PostID": strconv.FormatUint(carpoolRequest.PostID, 10)}),
+		})
+		return
+	} else if strings.Contains(comparable, "view") && strings.Contains(comparable, "carpool") {
+		carpoolRequests, err := driverCarpools(session["gucID"].(string))
 		if err != nil {
 			//	res.WriteHeader(http.StatusInternalServerError)
 			writeJSON(res, JSON{
-				"message": "There was an error while saving your information. Error: " + err.Error(),
+				"message": say(session, "view.error", Messages.Params{"Error": sayError(session, err)}),
 			})
 			return
 		}
-
-		possiblePassengers := carpoolRequest.PossiblePassengers
-		possiblePassengers = append(possiblePassengers, session["gucID"].(string))
-		err = DB.UpdateDB(postIDint, carpoolRequest.Longitude, carpoolRequest.Latitude, carpoolRequest.FromGUC, carpoolRequest.AvailableSeats, carpoolRequest.CurrentPassengers, possiblePassengers, carpoolRequest.StartTime)
-		if err != nil {
-			//res.WriteHeader(http.StatusInternalServerError)
+		if len(carpoolRequests) == 0 {
+			//	res.WriteHeader(http.StatusUnauthorized)
 			writeJSON(res, JSON{
-				"message": "There was an error updating. Error: " + err.Error(),
+				"message": say(session, "view.none", nil),
 			})
 			return
 		}
-		session["myChoice"] = postIDint
-		writeJSON(res, JSON{
-			"message": "You've successfully chosen a carpool! Now you have to wait for the original poster to accept your request. He may cancel the carpool, or choose to accept others, so plesae stay alert to your notifications. You can view them by typing 'notify' or 'notifications'.",
-		})
-		return
-	} else if strings.Contains(comparable, "delete") && strings.Contains(comparable, "carpool") {
-		delete(session, "fromGUC")
-		delete(session, "latitude")
-		delete(session, "longitude")
-		delete(session, "time")
-		delete(session, "availableSeats")
-		delete(session, "createComplete")
-		delete(session, "requestOrCreate")
-		delete(session, "postID")
-		if !createFound || !postIDExists {
-			//	res.WriteHeader(http.StatusUnauthorized)
+		// Without a post ID, show all of them.
+		if _, found := postIDIn(comparable); !found {
+			cpString := ""
+			for i := 0; i < len(carpoolRequests); i++ {
+				cpString += sayCarpool(session, carpoolRequests[i]) + ",\n"
+			}
 			writeJSON(res, JSON{
-				"message": "You cannot delete a carpool if you've never created one. I can pretend that I deleted one that doesn't exist though!",
+				"message": say(session, "view.details", nil) + "\n" + cpString,
 			})
 			return
 		}
-		err := DB.DeleteDB(postID.(uint64))
+		carpoolRequest, err := chooseCarpool(session, carpoolRequests, comparable)
 		if err != nil {
-			//	res.WriteHeader(http.StatusInternalServerError)
 			writeJSON(res, JSON{
-				"message": "There was an error deleting the carpool from our database. Error: " + err.Error(),
+				"message": sayError(session, err),
 			})
 			return
 		}
 		writeJSON(res, JSON{
-			"message": "You chose to delete your carpool. Now you can create a new one in its place if you wish. You can also request ne or view all the available ones.",
-		})
-		return
-	} else if strings.Contains(comparable, "edit") && strings.Contains(comparable, "carpool") {
-		delete(session, "postID")
-		delete(session, "fromGUC")
-		delete(session, "latitude")
-		delete(session, "longitude")
-		delete(session, "time")
-		delete(session, "availableSeats")
-		delete(session, "createComplete")
-		session["requestOrCreate"] = "create"
-		writeJSON(res, JSON{
-			"message": "You chose to edit your carpool. Let's do this piece by piece. Firstly, are you going to the GUC, or are you leaving campus?	",
+			"message": say(session, "view.details", nil) + "\n" + sayCarpool(session, carpoolRequest),
 		})
 		return
-	} else if strings.Contains(comparable, "view") && strings.Contains(comparable, "carpool") {
-		if !postIDExists {
-			//	res.WriteHeader(http.StatusUnauthorized)
+	} else if strings.Contains(comparable, "reject") || strings.Contains(comparable, "accept") {
+		accept := strings.Contains(comparable, "accept")
+		passengerID := gucIDFormat.FindString(comparable)
+		if passengerID == "" {
 			writeJSON(res, JSON{
-				"message": "You can't view your carpool because you didn't make one yet. Go make one if you really want to do that. Just type something like 'create'.",
+				"message": say(session, "accept.who", nil),
 			})
 			return
 		}
-		myRequest, err := DB.GetPostByID(postID.(uint64))
+		carpoolRequests, err := driverCarpools(session["gucID"].(string))
+		if err != nil {
+			writeJSON(res, JSON{
+				"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
+			})
+			return
+		}
+		chosen, err := passengerCarpools(session, carpoolRequests, comparable, passengerID)
 		if err != nil {
-			//	res.WriteHeader(http.StatusInternalServerError)
 			writeJSON(res, JSON{
-				"message": "Could not get the carpool request. Error: " + err.Error(),
+				"message": sayError(session, err),
 			})
 			return
 		}
-		if len(myRequest) == 0 {
-			//	res.WriteHeader(http.StatusNotFound)
+		postIDs := []string{}
+		// The driver can undo a rejection, so the carpools the passenger was accepted in are kept.
+		rejectedFrom := []uint64{}
+		acceptedIn := []uint64{}
+		for _, carpoolRequest := range chosen {
+			if !accept {
+				rejectedFrom = append(rejectedFrom, carpoolRequest.PostID)
+				for _, gucID := range carpoolRequest.CurrentPassengers {
+					if gucID == passengerID {
+						acceptedIn = append(acceptedIn, carpoolRequest.PostID)
+					}
+				}
+				err := DB.RejectPassenger(passengerID, carpoolRequest.PostID)
+				if err != nil {
+					//	res.WriteHeader(http.StatusUnprocessableEntity)
+					writeJSON(res, JSON{
+						"message": say(session, "reject.error", Messages.Params{"Error": sayError(session, err)}),
+					})
+					return
+				}
+				emailPassenger(passengerID, carpoolRequest.PostID, Notifier.RequestRejected, session)
+			} else {
+				err := DB.AcceptPassenger(passengerID, carpoolRequest.PostID)
+				if err != nil {
+					//	res.WriteHeader(http.StatusUnprocessableEntity)
+					writeJSON(res, JSON{
+						"message": say(session, "accept.error", Messages.Params{"Error": sayError(session, err)}),
+					})
+					return
+				}
+				emailPassenger(passengerID, carpoolRequest.PostID, Notifier.RequestAccepted, session)
+			}
+			postIDs = append(postIDs, strconv.FormatUint(carpoolRequest.PostID, 10))
+		}
+		if !accept {
+			rememberUndo(session, undoReject, rejectedFrom, acceptedIn, passengerID, nil)
 			writeJSON(res, JSON{
-				"message": "There seem to be no requests in our databse matching yours.",
+				"message": say(session, "reject.done", Messages.Params{"Passenger": passengerID, "PostIDs": strings.Join(postIDs, say(session, "and", nil))}) + undoHint(session) + " " + say(session, "whatElse", nil),
 			})
 			return
 		}
 		writeJSON(res, JSON{
-			"message": "Here are your carpool details!\n" + myRequest[0].CarpoolToString(),
+			"message": say(session, "accept.done", Messages.Params{"Passenger": passengerID, "PostIDs": strings.Join(postIDs, say(session, "and", nil))}),
 		})
 		return
-	} else if strings.Contains(comparable, "reject") {
-		if !postIDExists || !createFound {
-			//	res.WriteHeader(http.StatusUnauthorized)
+	} else if strings.Contains(comparable, "directions") {
+		carpoolRequest, ok := ownCarpool(res, session, comparable)
+		if !ok {
+			return
+		}
+		location := strconv.FormatFloat(carpoolRequest.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(carpoolRequest.Longitude, 'f', -1, 64)
+		if carpoolRequest.FromGUC {
+			directions, err := DirectionsAPI.GetRoute("German University IN cairo", location)
+			if err != nil {
+				//	res.WriteHeader(http.StatusInternalServerError)
+				writeJSON(res, JSON{
+					"message": say(session, "directions.error", Messages.Params{"Error": sayError(session, err)}),
+				})
+				return
+			}
 			writeJSON(res, JSON{
-				"message": "You can't reject passengers from your carpool because you didn't make one yet. What are you even rejecting them for?",
+				"message": directions,
 			})
 			return
 		}
-		exp := regexp.MustCompile("[0-9]+-[0-9]+")
-		passengerID := exp.FindString(comparable)
-		err := DB.RejectPassenger(passengerID, postID.(uint64))
+		directions, err := DirectionsAPI.GetRoute(location, "German University IN cairo")
 		if err != nil {
-			//	res.WriteHeader(http.StatusUnprocessableEntity)
+			//	res.WriteHeader(http.StatusInternalServerError)
 			writeJSON(res, JSON{
-				"message": "There was an error in rejecting this passenger. Error: " + err.Error(),
+				"message": say(session, "directions.notFound", nil),
 			})
 			return
 		}
 		writeJSON(res, JSON{
-			"message": "You successfully rejected the passenger with ID " + passengerID + ". What else would you like to do?",
+			"message": directions,
 		})
 		return
-	} else if strings.Contains(comparable, "accept") {
-		if !createFound || !postIDExists {
-			//	res.WriteHeader(http.StatusUnauthorized)
+	}
+	//res.WriteHeader(http.StatusUnprocessableEntity)
+	writeJSON(res, JSON{
+		"message": say(session, "postRequest.unclear", nil),
+	})
+	return
+}
+
+// Function that saves the rating a student gives someone they rode with (eg. 'rate 34-1234 5 great driver').
+func rateHandler(res http.ResponseWriter, session Session, message string) {
+	parts := rateCommand.FindStringSubmatch(strings.TrimSpace(message))
+	if parts == nil {
+		writeJSON(res, JSON{
+			"message": say(session, "rate.usage", nil),
+		})
+		return
+	}
+	stars, _ := strconv.Atoi(parts[2])
+	_, err := DB.RateUser(session["gucID"].(string), parts[1], stars, parts[3])
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "rate.error", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	writeJSON(res, JSON{
+		"message": say(session, "rate.done", Messages.Params{"GUCID": parts[1], "Stars": parts[2]}),
+	})
+}
+
+// Function that moves a carpool through its ride: the driver starts and ends it, and the passengers check in.
+func rideHandler(res http.ResponseWriter, session Session, comparable string) {
+	if strings.Contains(comparable, "start ride") || strings.Contains(comparable, "end ride") {
+		carpoolRequests, err := driverCarpools(session["gucID"].(string))
+		if err != nil {
 			writeJSON(res, JSON{
-				"message": "You cannot accept a passenger to your carpool if you haven't made one. That's just weird!",
+				"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
 			})
 			return
 		}
-		exp := regexp.MustCompile("[0-9]+-[0-9]+")
-		passengerID := exp.FindString(comparable)
-		err := DB.AcceptPassenger(passengerID, postID.(uint64))
-		if err != nil {
-			//	res.WriteHeader(http.StatusUnprocessableEntity)
+		if len(carpoolRequests) == 0 {
 			writeJSON(res, JSON{
-				"message": "There was an error in accepting this passenger. Error: " + err.Error(),
+				"message": say(session, "ride.none", nil),
 			})
 			return
 		}
-		writeJSON(res, JSON{
-			"message": "You successfully accepted the passenger with ID " + passengerID + ". What else would you like to do?",
-		})
-		return
-	} else if strings.Contains(comparable, "directions") {
-		if !createFound || !postIDExists {
-			//res.WriteHeader(http.StatusUnauthorized)
+		// Only the carpools on the road can be ended.
+		if strings.Contains(comparable, "end ride") {
+			departed := []DB.CarpoolRequest{}
+			for _, carpoolRequest := range carpoolRequests {
+				if carpoolRequest.CurrentStatus() == DB.StatusDeparted {
+					departed = append(departed, carpoolRequest)
+				}
+			}
+			if len(departed) > 0 {
+				carpoolRequests = departed
+			}
+		}
+		carpoolRequest, err := chooseCarpool(session, carpoolRequests, comparable)
+		if err != nil {
 			writeJSON(res, JSON{
-				"message": "I can't give you the directions because you don't have a carpool created.",
+				"message": sayError(session, err),
 			})
 			return
 		}
-		if session["fromGUC"].(bool) {
-			directions, err := DirectionsAPI.GetRoute("German University IN cairo", strconv.FormatFloat(session["latitude"].(float64), 'f', -1, 64)+","+strconv.FormatFloat(session["longitude"].(float64), 'f', -1, 64))
+		postID := carpoolRequest.PostID
+		if strings.Contains(comparable, "start ride") {
+			err := DB.StartRide(postID)
 			if err != nil {
-				//	res.WriteHeader(http.StatusInternalServerError)
 				writeJSON(res, JSON{
-					"message": "An error occured while recieving the directions. Error: " + err.Error(),
+					"message": say(session, "ride.startError", Messages.Params{"Error": sayError(session, err)}),
 				})
 				return
 			}
 			writeJSON(res, JSON{
-				"message": directions,
+				"message": say(session, "ride.started", nil),
 			})
 			return
 		}
-		directions, err := DirectionsAPI.GetRoute(strconv.FormatFloat(session["latitude"].(float64), 'f', -1, 64)+","+strconv.FormatFloat(session["longitude"].(float64), 'f', -1, 64), "German University IN cairo")
+		err = DB.EndRide(postID)
 		if err != nil {
-			//	res.WriteHeader(http.StatusInternalServerError)
 			writeJSON(res, JSON{
-				"message": "An error occured while recieving the directions. the location you entered can not be found",
+				"message": say(session, "ride.endError", Messages.Params{"Error": sayError(session, err)}),
 			})
 			return
 		}
+		askForRatings(carpoolRequest)
+		forgetFinishedCarpools(session)
 		writeJSON(res, JSON{
-			"message": directions,
+			"message": say(session, "ride.ended", nil),
+		})
+		return
+	}
+
+	myChoice, myChoiceExists := session["myChoice"]
+	if !myChoiceExists {
+		writeJSON(res, JSON{
+			"message": say(session, "checkIn.none", nil),
+		})
+		return
+	}
+	err := DB.CheckIn(session["gucID"].(string), myChoice.(uint64))
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "checkIn.error", Messages.Params{"Error": sayError(session, err)}),
 		})
 		return
 	}
-	//res.WriteHeader(http.StatusUnprocessableEntity)
 	writeJSON(res, JSON{
-		"message": "I did not understand what you said. Would you like to view all the available carpools,  cancel your request, edit your request or choose an available carpool?",
+		"message": say(session, "checkIn.done", nil),
 	})
-	return
 }
 
 // Function to get notification from databse.
@@ -876,20 +1284,30 @@ func getNotifications(session Session) (string, error) {
 	for index := 0; index < len(passengerRequests); index++ {
 		passengerRequest := passengerRequests[index]
 		if passengerRequest.Notify == 0 { //Rejected
-			notificationString += "-I'm sorry, but your last carpool request couldn't be made. You can join another one.-"
+			notificationString += "-" + say(session, "notify.rejected", nil) + "-"
 			// remove from session with this guc mail and DB
 			err = DB.DeletePassengerRequest(passengerRequest.PostID, session["gucID"].(string))
 			if err != nil {
 				return "", fmt.Errorf("error")
 			}
-			delete(session, "myChoice")
+			leaveCarpool(session, passengerRequest.PostID)
 		} else if passengerRequest.Notify == 2 { //Accepted
-			notificationString += "-Your request has been accepted! have fun-"
+			notificationString += "-" + say(session, "notify.accepted", nil)
+			carpoolRequests, err := DB.GetPostByID(passengerRequest.PostID)
+			if err == nil && len(carpoolRequests) > 0 && carpoolRequests[0].Vehicle.Plate != "" {
+				notificationString += say(session, "notify.lookFor", Messages.Params{"Vehicle": carpoolRequests[0].Vehicle.VehicleToString()})
+			}
+			notificationString += "-"
 		}
 	}
-	postID, postIDExists := session["postID"]
-	if postIDExists == true {
-		passengerRequests, err = DB.GetPassengerRequestsByPostID(postID.(uint64))
+	carpoolRequests, err := driverCarpools(session["gucID"].(string))
+	if err != nil {
+		return "", fmt.Errorf("error")
+	}
+	for _, carpoolRequest := range carpoolRequests {
+		postID := carpoolRequest.PostID
+		carpoolID := strconv.FormatUint(postID, 10)
+		passengerRequests, err = DB.GetPassengerRequestsByPostID(postID)
 		if err != nil {
 
 			return "", fmt.Errorf("error")
@@ -897,27 +1315,63 @@ func getNotifications(session Session) (string, error) {
 		for i := 0; i < len(passengerRequests); i++ {
 			currentPassenger := passengerRequests[i]
 			if currentPassenger.Notify == 3 {
-				notificationString += "-The passenger with ID " + currentPassenger.Passenger.GUCID + " and name " + currentPassenger.Passenger.Name + " has cancelled his request. You can accept another one in their place.-"
+				notificationString += "-" + say(session, "notify.cancelled", Messages.Params{"Passenger": currentPassenger.Passenger.GUCID, "Name": DB.DisplayName(currentPassenger.Passenger.GUCID, currentPassenger.Passenger.Name), "PostID": carpoolID}) + "-"
 				//remove him from db
-				DB.DeletePassengerRequest(postID.(uint64), currentPassenger.Passenger.GUCID)
+				DB.DeletePassengerRequest(postID, currentPassenger.Passenger.GUCID)
 			}
 		}
 
-		CarpoolPost, err := DB.GetPostByID(session["postID"].(uint64))
-		if err == nil {
-			//no problem
-			possiblePassengers := CarpoolPost[0].PossiblePassengers
-			for i := 0; i < len(possiblePassengers); i++ {
-				notificationString += "-The passenger with ID " + possiblePassengers[i] + " wants to ride with you-"
-			}
+		possiblePassengers := carpoolRequest.PossiblePassengers
+		names, _ := DB.GetUserNames(possiblePassengers)
+		averages, _ := DB.GetAverageRatings(possiblePassengers)
+		for i := 0; i < len(possiblePassengers); i++ {
+			notificationString += "-" + say(session, "notify.wantsToRide", Messages.Params{"Passenger": possiblePassengers[i], "Name": names[possiblePassengers[i]], "Rating": sayRating(session, averages[possiblePassengers[i]]), "PostID": carpoolID}) + "-"
+		}
+	}
+	pendingRatings, err := DB.GetPendingRatings(session["gucID"].(string))
+	if err == nil {
+		for _, pendingRating := range pendingRatings {
+			notificationString += "-" + say(session, "notify.rate", Messages.Params{"Name": pendingRating.Name, "GUCID": pendingRating.GUCID}) + "-"
 		}
 	}
 	if notificationString == "" {
-		return "-There are no new notifications-", nil
+		return "-" + say(session, "notify.none", nil) + "-", nil
 	}
 	return notificationString, nil
 }
 
+// Function that emails a student in the background, so that a slow mail server doesn't keep the chat waiting.
+func sendEmail(gucID string, name string, template string, data Notifier.Data) {
+	go func() {
+		err := notifier.Notify(gucID, DB.DisplayName(gucID, name), template, data)
+		if err != nil {
+			log.Printf("could not email %s: %s\n", gucID, err.Error())
+		}
+	}()
+}
+
+// Function that emails a passenger of the driver's carpool about their request.
+func emailPassenger(gucID string, postID uint64, template string, session Session) {
+	passengerRequests, err := DB.GetPassengerRequestByGUCIDAndPostID(gucID, postID)
+	if err != nil || len(passengerRequests) == 0 {
+		return
+	}
+	data := Notifier.Data{"DriverName": session["name"], "PostID": postID}
+	if template == Notifier.RideRestored {
+		data["Accepted"] = passengerRequests[0].Notify == 2
+	}
+	if template == Notifier.RequestAccepted {
+		carpoolRequests, err := DB.GetPostByID(postID)
+		if err == nil && len(carpoolRequests) > 0 {
+			data["Details"] = carpoolRequests[0].CarpoolToString()
+			if carpoolRequests[0].Vehicle.Plate != "" {
+				data["Vehicle"] = carpoolRequests[0].Vehicle.VehicleToString()
+			}
+		}
+	}
+	sendEmail(gucID, passengerRequests[0].Passenger.Name, template, data)
+}
+
 // Function to write out a JSON response.
 func writeJSON(res http.ResponseWriter, data JSON) {
 	res.Header().Set("Content-Type", "application/json")
@@ -929,15 +1383,21 @@ func writeJSON(res http.ResponseWriter, data JSON) {
 func getDetails(session Session) string {
 	str := ""
 
+	place, _ := session["placereq"].(string)
+	if place == "" {
+		place = say(session, "details.location", Messages.Params{"Latitude": session["latitudereq"].(string), "Longitude": session["longitudereq"].(string)})
+	}
 	if session["fromGUCreq"].(bool) {
-		str += "You're leaving the GUC, and going to the location with "
+		str += say(session, "details.fromGUC", Messages.Params{"Place": place})
 	} else {
-		str += "You're coming to the GUC, from the location with "
+		str += say(session, "details.toGUC", Messages.Params{"Place": place})
 	}
 
-	str += "latitude " + session["latitudereq"].(string) + " and longitude " + session["longitudereq"].(string) + "."
-
-	str += "You want your ride to take place around " + (session["timereq"].(time.Time)).Format("Jan 2, 2006 at 3:04pm (EET)") + "."
+	if window := requestWindow(session); window.Window() {
+		str += " " + say(session, "details.window", Messages.Params{"Time": sayExpression(session, window)})
+	} else {
+		str += " " + say(session, "details.time", Messages.Params{"Time": sayTime(session, window.Start)})
+	}
 
 	return str
 }
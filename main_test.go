@@ -0,0 +1,1229 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/OIDC"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Recurring"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Roster"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Sessions"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Users"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Verification"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/When"
+)
+
+// The server instance the tests talk to.
+var testServer = newServer(Sessions.NewMemoryStore())
+
+// Function that starts a session through the /welcome route and returns its uuid.
+func welcome(t *testing.T) string {
+	res := httptest.NewRecorder()
+	testServer.startSession(res, httptest.NewRequest(http.MethodGet, "/welcome", nil))
+	data := JSON{}
+	json.NewDecoder(res.Body).Decode(&data)
+	return data["uuid"].(string)
+}
+
+// Function that sends a chat message with the given uuid and returns the reply.
+func chat(t *testing.T, uuid string, message string) string {
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(`{"message": "`+message+`"}`))
+	req.Header.Set("Authorization", uuid)
+	testServer.handleChat(res, req)
+	data := JSON{}
+	json.NewDecoder(res.Body).Decode(&data)
+	return data["message"].(string)
+}
+
+func TestSessionTokensAreRandom(t *testing.T) {
+	first := welcome(t)
+	second := welcome(t)
+	if len(first) != 64 || len(second) != 64 {
+		t.Error("tokens should be 256 bits long", first, second)
+	}
+	if first == second {
+		t.Error("two sessions started in the same second got the same token")
+	}
+}
+
+// Function that changes when a session expires, straight in the store.
+func expireAt(uuid string, expiresAt time.Time) {
+	session, _, _ := testServer.sessions.Get(uuid)
+	session[Sessions.ExpiresAtKey] = expiresAt
+	testServer.sessions.Save(uuid, session)
+}
+
+// Function that checks if the store still has a session.
+func sessionExists(uuid string) bool {
+	_, found, _ := testServer.sessions.Get(uuid)
+	return found
+}
+
+func TestSessionExpires(t *testing.T) {
+	uuid := welcome(t)
+	expireAt(uuid, time.Now().Add(-time.Second))
+	// The sweeper keeps it for a while, so the student is told why they have to log in again.
+	testServer.removeExpiredSessions()
+	if reply := chat(t, uuid, "hi"); !strings.Contains(reply, "expired") {
+		t.Error("expected the session to be expired, got: " + reply)
+	}
+	if sessionExists(uuid) {
+		t.Error("expired session was not removed")
+	}
+	if reply := chat(t, "not-a-real-token", "hi"); strings.Contains(reply, "expired") {
+		t.Error("unknown token should not be reported as expired")
+	}
+
+	// Sessions that expired long ago are removed.
+	uuid = welcome(t)
+	expireAt(uuid, time.Now().Add(-expiredGrace-time.Minute))
+	testServer.removeExpiredSessions()
+	if sessionExists(uuid) {
+		t.Error("old expired session was not removed")
+	}
+}
+
+func TestSessionTTLSlides(t *testing.T) {
+	uuid := welcome(t)
+	expireAt(uuid, time.Now().Add(time.Second))
+	chat(t, uuid, "hello")
+	session, _, _ := testServer.sessions.Get(uuid)
+	if session.Expired(time.Now().Add(time.Minute)) {
+		t.Error("talking did not extend the session")
+	}
+}
+
+func TestLogout(t *testing.T) {
+	uuid := welcome(t)
+	chat(t, uuid, "logout")
+	if sessionExists(uuid) {
+		t.Error("session was not removed on logout")
+	}
+
+	uuid = welcome(t)
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.Header.Set("Authorization", uuid)
+	testServer.endSession(httptest.NewRecorder(), req)
+	if sessionExists(uuid) {
+		t.Error("session was not removed by the logout route")
+	}
+}
+
+// Run with -race: many users start sessions, talk and log out at the same time.
+func TestConcurrentWelcomeAndChat(t *testing.T) {
+	var wait sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			uuid := welcome(t)
+			chat(t, uuid, "hello")
+			chat(t, uuid, "no colon here")
+			testServer.removeExpiredSessions()
+			chat(t, uuid, "logout")
+		}()
+	}
+	wait.Wait()
+}
+
+// Run with -race: the same session talks from many requests at the same time.
+func TestConcurrentChatOnOneSession(t *testing.T) {
+	uuid := welcome(t)
+	var wait sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			chat(t, uuid, "hello")
+		}()
+	}
+	wait.Wait()
+	if !sessionExists(uuid) {
+		t.Error("session got lost")
+	}
+}
+
+// Function that sends a chat message to a running server over HTTP and returns the reply.
+func chatOver(t *testing.T, serverURL string, uuid string, message string) string {
+	req, _ := http.NewRequest(http.MethodPost, serverURL+"/chat", strings.NewReader(`{"message": "`+message+`"}`))
+	req.Header.Set("Authorization", uuid)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data := JSON{}
+	json.NewDecoder(res.Body).Decode(&data)
+	return data["message"].(string)
+}
+
+// Two server instances behind a load balancer, sharing only the session store, the locks and the profiles.
+func TestMultipleInstances(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	locks := Sessions.NewMemoryLocker()
+	users := Users.NewMemoryStore()
+	first := httptest.NewServer((&server{sessions: store, locks: locks, users: users}).routes())
+	defer first.Close()
+	second := httptest.NewServer((&server{sessions: store, locks: locks, users: users}).routes())
+	defer second.Close()
+
+	// A session started on one instance is known to the other.
+	res, err := http.Get(first.URL + "/welcome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := JSON{}
+	json.NewDecoder(res.Body).Decode(&data)
+	res.Body.Close()
+	uuid := data["uuid"].(string)
+	if reply := chatOver(t, second.URL, uuid, "hello"); strings.Contains(reply, "don't know this session") {
+		t.Fatal("second instance does not know the session started on the first")
+	}
+
+	// A conversation can move between instances from one step to the next.
+	session, _, _ := store.Get(uuid)
+	session["gucID"] = "34-1234"
+	session["name"] = "Ahmed"
+	session["verified"] = true
+	store.Save(uuid, session)
+	chatOver(t, first.URL, uuid, "create")
+	reply := chatOver(t, second.URL, uuid, "to guc")
+	if !strings.Contains(reply, "going to the GUC") {
+		t.Error("second instance lost the conversation, got: " + reply)
+	}
+
+	// A user logging out on one instance is logged out on both.
+	chatOver(t, second.URL, uuid, "logout")
+	if reply := chatOver(t, first.URL, uuid, "hello"); !strings.Contains(reply, "don't know this session") {
+		t.Error("first instance still knows the logged out session, got: " + reply)
+	}
+}
+
+// slowStore : a store that is slow to save sessions in which the user did not choose to create or request yet, so that without locking such a save lands last.
+type slowStore struct {
+	*Sessions.MemoryStore
+}
+
+func (s slowStore) Save(uuid string, session Session) error {
+	if _, found := session["requestOrCreate"]; !found {
+		time.Sleep(20 * time.Millisecond)
+	}
+	return s.MemoryStore.Save(uuid, session)
+}
+
+// Two messages of the same user reaching two instances at the same time are handled one after the other, so neither overwrites the other's changes.
+func TestMultipleInstancesSerializeMessages(t *testing.T) {
+	store := slowStore{Sessions.NewMemoryStore()}
+	locks := Sessions.NewMemoryLocker()
+	users := Users.NewMemoryStore()
+	first := httptest.NewServer((&server{sessions: store, locks: locks, users: users}).routes())
+	defer first.Close()
+	second := httptest.NewServer((&server{sessions: store, locks: locks, users: users}).routes())
+	defer second.Close()
+
+	for i := 0; i < 10; i++ {
+		uuid, _ := Sessions.NewToken()
+		session := Session{"gucID": "34-1234", "name": "Ahmed", "verified": true}
+		session.Touch(time.Now(), time.Hour)
+		store.MemoryStore.Save(uuid, session)
+
+		var wait sync.WaitGroup
+		wait.Add(2)
+		go func() {
+			defer wait.Done()
+			chatOver(t, first.URL, uuid, "create")
+		}()
+		go func() {
+			defer wait.Done()
+			chatOver(t, second.URL, uuid, "to guc")
+		}()
+		wait.Wait()
+
+		// Whichever came first, choosing to create must not be lost.
+		session, _, _ = store.Get(uuid)
+		if session["requestOrCreate"] != "create" {
+			t.Fatal("a message overwrote the changes of the other one")
+		}
+	}
+}
+
+// fakeMailer : a mailer that keeps the emails instead of sending them.
+type fakeMailer struct {
+	mutex  sync.Mutex
+	emails map[string]string
+	sent   int
+}
+
+func (m *fakeMailer) Send(to string, subject string, body string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.emails[to] = body
+	m.sent++
+	return nil
+}
+
+// Function that reads the code out of the last email sent to an address.
+func (m *fakeMailer) code(address string) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return regexp.MustCompile(`[0-9]{6}`).FindString(m.emails[address])
+}
+
+func TestLoginNeedsEmailedCode(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	mailer := &fakeMailer{emails: map[string]string{}}
+	roster := Roster.NewMemoryStore(DB.Student{GUCID: "34-1234", Name: "Ahmed Ali"})
+	roster.Upsert(&DB.Student{GUCID: "34-5678", Name: "Sara Omar", Active: false})
+	srv := &server{
+		sessions: store,
+		locks:    Sessions.NewMemoryLocker(),
+		verifier: Verification.New(Verification.NewMemoryStore(), mailer, Roster.Address(roster), []byte("test secret")),
+		roster:   roster,
+		users:    Users.NewMemoryStore(),
+	}
+	ts := httptest.NewServer(srv.routes())
+	defer ts.Close()
+
+	// The real student is already logged in somewhere else, with a carpool.
+	victim, _ := Sessions.NewToken()
+	old := Session{"gucID": "34-1234", "name": "Ahmed", "verified": true, "postID": uint64(7)}
+	old.Touch(time.Now(), time.Hour)
+	store.Save(victim, old)
+
+	// Typing someone's GUC ID only emails them a code.
+	uuid, _ := Sessions.NewToken()
+	fresh := Session{}
+	fresh.Touch(time.Now(), time.Hour)
+	store.Save(uuid, fresh)
+	// Only whole GUC IDs of current students are accepted.
+	if reply := chatOver(t, ts.URL, uuid, "abc34-1234xyz:Ahmed"); !strings.Contains(reply, "invalid") {
+		t.Error("accepted a GUC ID with text around it, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "34-9999:Ahmed"); !strings.Contains(reply, "can't find") {
+		t.Error("accepted a GUC ID that is not on the roster, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "34-5678:Sara"); !strings.Contains(reply, "no longer") {
+		t.Error("accepted a graduate, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "34-1234:Ahmed"); !strings.Contains(reply, "34-1234@student.guc.edu.eg") {
+		t.Fatal("expected the code to be emailed, got: " + reply)
+	}
+	session, _, _ := store.Get(uuid)
+	if _, found := session["gucID"]; found {
+		t.Error("logged in before sending the code")
+	}
+	if _, found, _ := store.Get(victim); !found {
+		t.Error("the old session was taken before sending the code")
+	}
+	if reply := chatOver(t, ts.URL, uuid, "create"); strings.Contains(reply, "going") || strings.Contains(reply, "Hello") {
+		t.Error("could use the chat before sending the code, got: " + reply)
+	}
+
+	// A wrong code is refused.
+	code := mailer.code("34-1234@student.guc.edu.eg")
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	if reply := chatOver(t, ts.URL, uuid, wrong); !strings.Contains(reply, "not correct") {
+		t.Error("wrong code was not refused, got: " + reply)
+	}
+
+	// The emailed code logs the student in and brings back their old session, once the message it is handling is done.
+	unlock, _ := srv.locks.Lock("session:" + victim)
+	replies := make(chan string)
+	go func() { replies <- chatOver(t, ts.URL, uuid, code) }()
+	time.Sleep(50 * time.Millisecond)
+	if _, found, _ := store.Get(victim); !found {
+		t.Error("the old session was taken while it was handling a message")
+	}
+	unlock()
+	if reply := <-replies; !strings.HasPrefix(reply, "Hello Ahmed Ali.") {
+		t.Fatal("correct code did not log in with the official name, got: " + reply)
+	}
+	if _, found, _ := store.Get(victim); found {
+		t.Error("the old session was not removed")
+	}
+	session, _, _ = store.Get(uuid)
+	if session["gucID"] != "34-1234" || session["name"] != "Ahmed Ali" || session["verified"] != true || session["postID"] != uint64(7) {
+		t.Error("not logged in with the old session", session)
+	}
+	if _, found := session["pendingGucID"]; found {
+		t.Error("the pending login was not cleared")
+	}
+}
+
+// slowCodeStore : a code store whose answers take a while to arrive, like a busy database's.
+type slowCodeStore struct {
+	Verification.Store
+}
+
+func (s slowCodeStore) Get(GUCID string) (DB.VerificationCode, bool, error) {
+	code, found, err := s.Store.Get(GUCID)
+	time.Sleep(20 * time.Millisecond)
+	return code, found, err
+}
+
+func TestLoginCodesAreLimitedAcrossSessions(t *testing.T) {
+	mailer := &fakeMailer{emails: map[string]string{}}
+	roster := Roster.NewMemoryStore(DB.Student{GUCID: "34-1234", Name: "Ahmed Ali"})
+	srv := &server{
+		sessions: Sessions.NewMemoryStore(),
+		locks:    Sessions.NewMemoryLocker(),
+		verifier: Verification.New(slowCodeStore{Verification.NewMemoryStore()}, mailer, Roster.Address(roster), []byte("test secret")),
+		roster:   roster,
+		users:    Users.NewMemoryStore(),
+	}
+	ts := httptest.NewServer(srv.routes())
+	defer ts.Close()
+
+	// Many sessions ask for a code of the same student at once.
+	var wait sync.WaitGroup
+	for i := 0; i < 2*srv.verifier.MaxSends; i++ {
+		uuid, _ := Sessions.NewToken()
+		session := Session{}
+		session.Touch(time.Now(), time.Hour)
+		srv.sessions.Save(uuid, session)
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			chatOver(t, ts.URL, uuid, "34-1234:Ahmed")
+		}()
+	}
+	wait.Wait()
+	if mailer.sent != srv.verifier.MaxSends {
+		t.Errorf("sent %d codes, expected %d", mailer.sent, srv.verifier.MaxSends)
+	}
+}
+
+func TestUnverifiedSessionsLogInAgain(t *testing.T) {
+	uuid := welcome(t)
+	session, _, _ := testServer.sessions.Get(uuid)
+	session["gucID"] = "34-1234"
+	session["name"] = "Ahmed"
+	session["postID"] = uint64(7)
+	testServer.sessions.Save(uuid, session)
+
+	chat(t, uuid, "create")
+	session, _, _ = testServer.sessions.Get(uuid)
+	if _, found := session["gucID"]; found {
+		t.Error("a session that was never verified is still logged in")
+	}
+	if _, found := session["postID"]; found {
+		t.Error("a session that was never verified kept its carpool")
+	}
+}
+
+func TestSingleSignOn(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	roster := Roster.NewMemoryStore(DB.Student{GUCID: "34-1234", Name: "Ahmed Ali"})
+	srv := &server{sessions: store, locks: Sessions.NewMemoryLocker(), roster: roster, users: Users.NewMemoryStore()}
+	ts := httptest.NewServer(srv.routes())
+	defer ts.Close()
+	fake := OIDC.NewFakeProvider("carpool", "secret", map[string]interface{}{"guc_id": "34-1234", "name": "Ahmed Ali"})
+	defer fake.Close()
+	provider, err := OIDC.Discover(fake.Config(ts.URL + "/callback"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.sso = &singleSignOn{provider: provider}
+
+	// The student already has a session, in the middle of creating a carpool.
+	old, _ := Sessions.NewToken()
+	previous := Session{"gucID": "34-1234", "name": "Ahmed", "verified": true, "fromGUC": true}
+	previous.Touch(time.Now(), time.Hour)
+	store.Save(old, previous)
+
+	// Logging in at the identity provider ends at /callback with a new session.
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+	res, err := client.Get(ts.URL + "/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := JSON{}
+	json.NewDecoder(res.Body).Decode(&data)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(data["message"].(string), "Hello Ahmed Ali") {
+		t.Fatal("login failed", res.StatusCode, data)
+	}
+	uuid := data["uuid"].(string)
+	session, found, _ := store.Get(uuid)
+	if !found || session["gucID"] != "34-1234" || session["verified"] != true || session["fromGUC"] != true {
+		t.Error("the session is not the one the chat login makes", session)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "create"); !strings.Contains(reply, "going") {
+		t.Error("could not use the chat after logging in, got: " + reply)
+	}
+
+	// A callback that this browser did not start is refused, in the language the browser asks for.
+	req, _ := http.NewRequest("GET", ts.URL+"/callback?code=stolen&state=guess", nil)
+	req.Header.Set("Accept-Language", "ar-EG,ar;q=0.9,en;q=0.8")
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = JSON{}
+	json.NewDecoder(res.Body).Decode(&data)
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Error("callback without the login cookie was accepted", res.StatusCode)
+	}
+	if message, _ := data["message"].(string); !strings.HasPrefix(message, "معرفش الدخول ده") {
+		t.Error("expected the refusal in Arabic, got", data)
+	}
+
+	// A student who chose Arabic in their profile is greeted in Arabic.
+	srv.users.Save(&DB.User{GUCID: "34-1234", Name: "Ahmed Ali", Language: DB.LanguageArabic})
+	res, err = client.Get(ts.URL + "/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = JSON{}
+	json.NewDecoder(res.Body).Decode(&data)
+	res.Body.Close()
+	if message, _ := data["message"].(string); !strings.HasPrefix(message, "أهلاً") {
+		t.Error("expected the greeting in Arabic, got", data)
+	}
+}
+
+func TestSingleSignOnOff(t *testing.T) {
+	res := httptest.NewRecorder()
+	testServer.startSSO(res, httptest.NewRequest(http.MethodGet, "/login", nil))
+	if res.Code != http.StatusNotFound {
+		t.Error("login route works without an identity provider", res.Code)
+	}
+
+	// An identity provider that was down when the server started is reached again later.
+	fake := OIDC.NewFakeProvider("carpool", "secret", map[string]interface{}{"guc_id": "34-1234"})
+	defer fake.Close()
+	down := fake.Config("http://localhost/callback")
+	down.Issuer = "http://127.0.0.1:1"
+	sso := &singleSignOn{config: down}
+	if sso.get() != nil {
+		t.Fatal("found an identity provider that is down")
+	}
+	sso.config = fake.Config("http://localhost/callback")
+	if sso.get() != nil {
+		t.Error("tried the identity provider again right away")
+	}
+	sso.triedAt = time.Now().Add(-ssoRetry)
+	if sso.get() == nil {
+		t.Error("did not try the identity provider again")
+	}
+}
+
+func TestReminderOffsets(t *testing.T) {
+	os.Setenv("REMINDER_OFFSETS", "60,soon")
+	defer os.Unsetenv("REMINDER_OFFSETS")
+	if _, err := newReminderScheduler(Calendar.NewMemoryStore()); err == nil || !strings.Contains(err.Error(), "REMINDER_OFFSETS") {
+		t.Error("accepted wrong reminder offsets", err)
+	}
+	os.Setenv("REMINDER_OFFSETS", "30")
+	if scheduler, err := newReminderScheduler(Calendar.NewMemoryStore()); err != nil || len(scheduler.Offsets) != 1 {
+		t.Error("wrong reminder scheduler", err)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	users := Users.NewMemoryStore()
+	users.Ensure("34-1234", "Ahmed Ali")
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: users}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	if reply := chatOver(t, ts.URL, uuid, "profile"); !strings.Contains(reply, "Ahmed Ali") || !strings.Contains(reply, "Phone: not set") {
+		t.Error("profile was not shown, got: " + reply)
+	}
+
+	// Step by step.
+	chatOver(t, ts.URL, uuid, "edit profile")
+	if reply := chatOver(t, ts.URL, uuid, "my phone"); !strings.Contains(reply, "phone number?") {
+		t.Error("expected to be asked for the phone number, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "hello"); !strings.Contains(reply, "not a phone number") {
+		t.Error("accepted a bad phone number, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "0101 234 5678"); !strings.Contains(reply, "Phone: 01012345678") {
+		t.Error("phone number was not changed, got: " + reply)
+	}
+
+	// All at once, and leaving half way.
+	chatOver(t, ts.URL, uuid, "change the reminders in my profile")
+	chatOver(t, ts.URL, uuid, "off")
+	chatOver(t, ts.URL, uuid, "edit profile")
+	if reply := chatOver(t, ts.URL, uuid, "cancel"); !strings.Contains(reply, "as it was") {
+		t.Error("could not leave the profile, got: " + reply)
+	}
+
+	user, _, _ := users.Get("34-1234")
+	if user.Phone != "01012345678" || !user.Notifications.RemindersOptOut || user.Notifications.EmailOptOut {
+		t.Error("profile was not saved", user)
+	}
+	session, _, _ = store.Get(uuid)
+	if _, found := session["profileStep"]; found {
+		t.Error("still editing the profile")
+	}
+}
+
+func TestCarpoolVehicle(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	users := Users.NewMemoryStore()
+	users.Ensure("34-1234", "Ahmed Ali")
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: users}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	// The time is already taken, since checking it against the driver's other carpools needs the database.
+	ride := time.Now().AddDate(0, 0, 2)
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true, "requestOrCreate": "create", "fromGUC": true, "latitude": 30.0, "longitude": 31.0, "time": ride}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	// A driver without a car registers one on the way.
+	if reply := chatOver(t, ts.URL, uuid, "a red one"); !strings.Contains(reply, "all four details") {
+		t.Error("accepted a car without its details, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "Kia Picanto, Red, abc 123, 3"); !strings.Contains(reply, "red Kia Picanto, plate ABC 123") || !strings.Contains(reply, "up to 3") {
+		t.Error("car was not chosen, got: " + reply)
+	}
+	user, _, _ := users.Get("34-1234")
+	if len(user.Vehicles) != 1 || user.Vehicles[0].Capacity != 3 {
+		t.Error("car was not added to the profile", user)
+	}
+
+	// The seats are limited by the car, not by a fixed number.
+	for _, seats := range []string{"4", "0", "many"} {
+		if reply := chatOver(t, ts.URL, uuid, seats); !strings.Contains(reply, "1 to 3 more passengers") {
+			t.Error("accepted " + seats + " seats in a car for 3, got: " + reply)
+		}
+	}
+
+	// A driver with more than one car picks one.
+	users.Save(&DB.User{GUCID: "34-1234", Name: "Ahmed Ali", Vehicles: []DB.Vehicle{
+		{Make: "Kia Picanto", Colour: "red", Plate: "ABC 123", Capacity: 3},
+		{Make: "Toyota Hiace", Colour: "white", Plate: "XYZ 9", Capacity: 8},
+	}})
+	session, _, _ = store.Get(uuid)
+	delete(session, "vehiclePlate")
+	delete(session, "capacity")
+	session["currentPassengers"] = []string{"34-1", "34-2"}
+	store.Save(uuid, session)
+	if reply := chatOver(t, ts.URL, uuid, "3"); !strings.Contains(reply, "don't have that car") || !strings.Contains(reply, "1. red Kia Picanto") || !strings.Contains(reply, "2. white Toyota Hiace") {
+		t.Error("chose a car that doesn't exist, or the cars were not listed, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "xyz9"); !strings.Contains(reply, "Toyota Hiace") || !strings.Contains(reply, "up to 6") {
+		t.Error("car was not chosen by its plate, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "7"); !strings.Contains(reply, "1 to 6 more passengers") {
+		t.Error("accepted more seats than the car has left, got: " + reply)
+	}
+}
+
+func TestChooseCarpool(t *testing.T) {
+	morning := DB.CarpoolRequest{PostID: 12, PossiblePassengers: []string{"34-1"}}
+	evening := DB.CarpoolRequest{PostID: 13, PossiblePassengers: []string{"34-1"}, CurrentPassengers: []string{"34-2"}}
+	both := []DB.CarpoolRequest{morning, evening}
+
+	if _, found := postIDIn("accept 34-1234"); found {
+		t.Error("took the GUC ID for a post ID")
+	}
+	if postID, found := postIDIn("accept 34-1234 in 13"); !found || postID != 13 {
+		t.Error("wrong post ID", postID)
+	}
+
+	tests := []struct {
+		carpools   []DB.CarpoolRequest
+		command    string
+		postID     uint64
+		errMessage string
+	}{
+		{both, "edit carpool 13", 13, ""},
+		{both, "edit carpool 14", 0, "don't have a carpool with the ID 14"},
+		{both, "edit carpool", 0, "You have 2 carpools, which one do you mean?"},
+		{[]DB.CarpoolRequest{morning}, "delete carpool", 12, ""},
+		{nil, "start ride", 0, "You don't have a carpool"},
+	}
+	for _, test := range tests {
+		carpool, err := chooseCarpool(Session{}, test.carpools, test.command)
+		if test.errMessage != "" {
+			if err == nil || !strings.Contains(err.Error(), test.errMessage) {
+				t.Errorf("%s: expected %q, got %v", test.command, test.errMessage, err)
+			}
+			continue
+		}
+		if err != nil || carpool.PostID != test.postID {
+			t.Errorf("%s: expected carpool %d, got %d %v", test.command, test.postID, carpool.PostID, err)
+		}
+	}
+
+	// A passenger is looked for in the carpools of the driver.
+	if carpools, err := passengerCarpools(Session{}, both, "reject 34-2", "34-2"); err != nil || len(carpools) != 1 || carpools[0].PostID != 13 {
+		t.Error("wrong carpool for the passenger", carpools, err)
+	}
+	if _, err := passengerCarpools(Session{}, both, "accept 34-1", "34-1"); err == nil {
+		t.Error("chose a carpool when the passenger asked to join both")
+	}
+	if carpools, err := passengerCarpools(Session{}, both, "accept 34-1 12", "34-1"); err != nil || len(carpools) != 1 || carpools[0].PostID != 12 {
+		t.Error("wrong carpool for the post ID", carpools, err)
+	}
+	// Both ways of a round trip are accepted together, unless the driver picks one.
+	morning.LinkedPostID, evening.LinkedPostID = 13, 12
+	roundTrip := []DB.CarpoolRequest{morning, evening}
+	if carpools, err := passengerCarpools(Session{}, roundTrip, "accept 34-1", "34-1"); err != nil || len(carpools) != 2 {
+		t.Error("did not accept both ways of the round trip", carpools, err)
+	}
+	if carpools, err := passengerCarpools(Session{}, roundTrip, "accept 34-1 13", "34-1"); err != nil || len(carpools) != 1 || carpools[0].PostID != 13 {
+		t.Error("wrong carpool for the post ID", carpools, err)
+	}
+	if _, err := passengerCarpools(Session{}, both, "accept 34-3", "34-3"); err == nil || !strings.Contains(err.Error(), "didn't ask to join") {
+		t.Error("accepted a passenger that didn't ask", err)
+	}
+}
+
+func TestLeaveRoundTrip(t *testing.T) {
+	session := Session{"myChoice": uint64(12), "myLinkedChoice": uint64(13)}
+	if !leaveCarpool(session, 12) || session["myChoice"] != uint64(13) {
+		t.Error("the other way of the round trip did not become the choice", session)
+	}
+	if _, found := session["myLinkedChoice"]; found {
+		t.Error("still linked", session)
+	}
+	if leaveCarpool(session, 13) || len(chosenCarpools(session)) != 0 {
+		t.Error("still in a carpool", session)
+	}
+
+	session = Session{"myChoice": uint64(12), "myLinkedChoice": uint64(13)}
+	if leaveCarpool(session, 13) || session["myChoice"] != uint64(12) || len(chosenCarpools(session)) != 1 {
+		t.Error("leaving the return trip changed the first way", session)
+	}
+	if leaveCarpool(session, 99) || session["myChoice"] != uint64(12) {
+		t.Error("left a carpool that wasn't chosen", session)
+	}
+}
+
+func TestReturnTripQuestion(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: Users.NewMemoryStore()}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	ride := time.Now().AddDate(0, 0, 2)
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true, "requestOrCreate": "create", "fromGUC": false, "latitude": 30.0, "longitude": 31.0, "time": ride, "vehiclePlate": "ABC 123", "capacity": 3, "availableSeats": 2, "returnOf": uint64(12)}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	if reply := chatOver(t, ts.URL, uuid, "sometime"); !strings.Contains(reply, "not a valid time") || !strings.Contains(reply, "going back from the GUC") {
+		t.Error("accepted a return time that isn't a time, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, ride.Add(-time.Hour).Format("2006-1-2 15:4")); !strings.Contains(reply, "after the first one") {
+		t.Error("accepted a return trip before the first way, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "no"); !strings.Contains(reply, "one way") {
+		t.Error("could not skip the return trip, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	for _, key := range draftKeys {
+		if _, found := session[key]; found {
+			t.Error("the carpool is still being created, found " + key)
+		}
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	schedules := Recurring.NewMemoryStore()
+	roster := Roster.NewMemoryStore(DB.Student{GUCID: "34-5678", Name: "Mona Hassan"})
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: Users.NewMemoryStore(), roster: roster, schedules: schedules, calendar: Calendar.NewMemoryStore()}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+	// The schedule is over, so no carpools are created from it, since that needs the database.
+	schedule := DB.Schedule{GUCID: "34-1234", Days: []time.Weekday{time.Sunday}, Hour: 8, Seats: 2, StartDate: time.Now().AddDate(0, -2, 0), EndDate: time.Now().AddDate(0, 0, -2)}
+	schedules.Insert(&schedule)
+	other := DB.Schedule{GUCID: "34-9999", Days: []time.Weekday{time.Monday}, Seats: 3, EndDate: time.Now().AddDate(0, 0, -2)}
+	schedules.Insert(&other)
+
+	if reply := chatOver(t, ts.URL, uuid, "view schedules"); !strings.Contains(reply, "Schedule: 1") || strings.Contains(reply, "Schedule: 2") {
+		t.Error("schedules were not shown, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "pause schedule 2"); !strings.Contains(reply, "don't have a schedule with the ID 2") {
+		t.Error("paused the schedule of someone else, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "repeat carpool every day"); !strings.Contains(reply, "To repeat a carpool every week") {
+		t.Error("expected to be told how to repeat a carpool, got: " + reply)
+	}
+
+	if reply := chatOver(t, ts.URL, uuid, "add 34-0000 to schedule 1"); !strings.Contains(reply, "can't find this GUC ID") {
+		t.Error("added a student that isn't on the roster, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "add 34-5678 to schedule 1"); !strings.Contains(reply, "Mona Hassan will ask to join every carpool") {
+		t.Error("standing passenger was not added, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "add 34-5678 to schedule 1"); !strings.Contains(reply, "already in it") {
+		t.Error("standing passenger was added twice, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "pause schedule 1 on 2026-11-01"); !strings.Contains(reply, "no carpool from schedule 1 on 2026-11-01") {
+		t.Error("day was not skipped, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "pause schedule 1"); !strings.Contains(reply, "is paused") {
+		t.Error("schedule was not paused, got: " + reply)
+	}
+	schedule, _, _ = schedules.Get(1)
+	if !schedule.Paused || len(schedule.Skipped) != 1 || schedule.Skipped[0] != "2026-11-01" || len(schedule.StandingPassengers) != 1 {
+		t.Error("schedule was not saved", schedule)
+	}
+
+	chatOver(t, ts.URL, uuid, "resume schedule 1")
+	chatOver(t, ts.URL, uuid, "remove 34-5678 from schedule 1")
+	schedule, _, _ = schedules.Get(1)
+	if schedule.Paused || len(schedule.StandingPassengers) != 0 {
+		t.Error("schedule was not resumed and emptied", schedule)
+	}
+}
+
+func TestCanJoin(t *testing.T) {
+	holiday := time.Now().AddDate(0, 0, 3)
+	srv := &server{calendar: Calendar.NewMemoryStore(DB.CalendarEntry{Kind: Calendar.Holiday, Name: "Armed Forces Day", Start: holiday.Format("2006-01-02"), End: holiday.Format("2006-01-02")})}
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali"}
+	open := DB.CarpoolRequest{PostID: 12, GUCID: "34-9999", AvailableSeats: 2, StartTime: time.Now().Add(time.Hour)}
+	if err := srv.canJoin(session, open); err != nil {
+		t.Error("could not join an open carpool", err)
+	}
+	departed := open
+	departed.Status = DB.StatusDeparted
+	if err := srv.canJoin(session, departed); err == nil || !strings.Contains(err.Error(), "departed") {
+		t.Error("joined a carpool that departed", err)
+	}
+	full := open
+	full.AvailableSeats = 0
+	if err := srv.canJoin(session, full); err == nil || !strings.Contains(err.Error(), "full") {
+		t.Error("joined a full carpool", err)
+	}
+	closed := open
+	closed.StartTime = holiday
+	if err := srv.canJoin(session, closed); err == nil || !strings.Contains(err.Error(), "Armed Forces Day") {
+		t.Error("joined a carpool on a day the GUC is closed", err)
+	}
+}
+
+func TestClosedCampus(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	holiday := time.Now().AddDate(0, 0, 3)
+	calendar := Calendar.NewMemoryStore(DB.CalendarEntry{Kind: Calendar.Holiday, Name: "Armed Forces Day", Start: holiday.Format("2006-01-02"), End: holiday.Format("2006-01-02")})
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: Users.NewMemoryStore(), calendar: calendar}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true, "requestOrCreate": "create", "fromGUC": false, "latitude": 30.0, "longitude": 31.0}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	if reply := chatOver(t, ts.URL, uuid, holiday.Format("2006-1-2")+" 08:30"); !strings.Contains(reply, "closed") || !strings.Contains(reply, "Armed Forces Day") {
+		t.Error("created a carpool on a holiday, got: " + reply)
+	}
+
+	// Only the admins can close the campus.
+	if reply := chatOver(t, ts.URL, uuid, "close campus on 2026-11-02"); !strings.Contains(reply, "Only the admins") {
+		t.Error("a student closed the campus, got: " + reply)
+	}
+	os.Setenv("ADMINS", "34-9999, 34-1234")
+	defer os.Unsetenv("ADMINS")
+	if reply := chatOver(t, ts.URL, uuid, "close campus tomorrow"); !strings.Contains(reply, "To close the campus") {
+		t.Error("expected to be told how to close the campus, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "close campus on 2026-11-03 until 2026-11-02"); !strings.Contains(reply, "open again after it closes") {
+		t.Error("closed the campus backwards, got: " + reply)
+	}
+	if entries, _ := calendar.Entries(); len(entries) != 1 {
+		t.Error("a closure was added", entries)
+	}
+}
+
+func TestTimetable(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	users := Users.NewMemoryStore()
+	users.Ensure("34-1234", "Ahmed Ali")
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: users, calendar: Calendar.NewMemoryStore()}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	if reply := chatOver(t, ts.URL, uuid, "timetable"); !strings.Contains(reply, "don't have your timetable") {
+		t.Error("expected to be asked for the timetable, got: " + reply)
+	}
+	// The messages are written into JSON as they are, so the new lines are escaped.
+	if reply := chatOver(t, ts.URL, uuid, "timetable\\nsun,9,CSEN 701"); !strings.Contains(reply, "there is no slot 9") {
+		t.Error("accepted a timetable with a bad slot, got: " + reply)
+	}
+	reply := chatOver(t, ts.URL, uuid, "my timetable:\\nsun,1,CSEN 701\\nsun,5,MATH 401")
+	if !strings.Contains(reply, "1: to the GUC before your 8:30am lecture on Sunday") || !strings.Contains(reply, "2: from the GUC after 5:15pm on Sunday") {
+		t.Error("wrong suggestions, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "subscribe to suggestion 3"); !strings.Contains(reply, "no suggestion 3") {
+		t.Error("subscribed to a suggestion that isn't there, got: " + reply)
+	}
+	chatOver(t, ts.URL, uuid, "subscribe suggestion 2")
+	chatOver(t, ts.URL, uuid, "subscribe suggestion 2")
+	if subscribers, _ := users.Subscribers(time.Sunday, true, 17*60+45, 17*60+45); len(subscribers) != 1 {
+		t.Error("expected the student to be subscribed", subscribers)
+	}
+	// A carpool that can leave a bit later fits too.
+	if subscribers, _ := users.Subscribers(time.Sunday, true, 16*60+30, 17*60+30); len(subscribers) != 1 {
+		t.Error("expected the student to hear about a carpool that can leave late enough", subscribers)
+	}
+	if subscribers, _ := users.Subscribers(time.Sunday, true, 16*60, 17*60); len(subscribers) != 0 {
+		t.Error("expected the student not to hear about a carpool that leaves too early", subscribers)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "timetable"); !strings.Contains(reply, "after 5:15pm on Sunday (subscribed)") {
+		t.Error("subscription was not shown, got: " + reply)
+	}
+	chatOver(t, ts.URL, uuid, "unsubscribe suggestion 2")
+	user, _, _ := users.Get("34-1234")
+	if len(user.Timetable) != 2 || len(user.Subscriptions) != 0 {
+		t.Error("timetable was not saved", user)
+	}
+
+	// A request made from a suggestion already has its time, so the location completes it.
+	session, _, _ = store.Get(uuid)
+	session["requestOrCreate"] = "request"
+	session["fromGUCreq"] = false
+	session["timereq"] = time.Now().AddDate(0, 0, 3)
+	store.Save(uuid, session)
+	if reply := chatOver(t, ts.URL, uuid, "latitude 30.1 longitude 31.2"); !strings.Contains(reply, "Your request is complete") {
+		t.Error("the request was not completed, got: " + reply)
+	}
+}
+
+func TestCalendarLink(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	users := Users.NewMemoryStore()
+	users.Ensure("34-1234", "Ahmed Ali")
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: users, calendar: Calendar.NewMemoryStore()}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	reply := chatOver(t, ts.URL, uuid, "calendar link")
+	user, _, _ := users.Get("34-1234")
+	if user.CalendarToken == "" || !strings.Contains(reply, ts.URL+"/calendar/"+user.CalendarToken+".ics") {
+		t.Fatal("expected the link to the calendar feed, got: " + reply)
+	}
+	if owner, _, _ := users.ByCalendarToken(user.CalendarToken); owner.GUCID != "34-1234" {
+		t.Error("the token does not lead to the student", owner)
+	}
+	chatOver(t, ts.URL, uuid, "calendar link")
+	if again, _, _ := users.Get("34-1234"); again.CalendarToken != user.CalendarToken {
+		t.Error("asking again changed the link")
+	}
+	chatOver(t, ts.URL, uuid, "new calendar link")
+	if renewed, _, _ := users.Get("34-1234"); renewed.CalendarToken == user.CalendarToken {
+		t.Error("the link was not renewed")
+	}
+
+	// The old link does not lead anywhere anymore.
+	res, err := http.Get(ts.URL + "/calendar/" + user.CalendarToken + ".ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Error("expected the old link to be gone, got", res.StatusCode)
+	}
+	// The secret link is not written to the logs.
+	if loggedPath("/calendar/"+user.CalendarToken+".ics") != "/calendar/…" || loggedPath("/chat") != "/chat" {
+		t.Error("the calendar token was logged")
+	}
+}
+
+func TestAmbiguousRideTime(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: Users.NewMemoryStore(), calendar: Calendar.NewMemoryStore()}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true, "requestOrCreate": "request", "fromGUCreq": false, "latitudereq": "30.1", "longitudereq": "31.2"}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	// "8" could be in the morning or in the evening, so the guess is confirmed first.
+	if reply := chatOver(t, ts.URL, uuid, "bokra 8"); !strings.Contains(reply, "Did you mean") || !strings.Contains(reply, "at 8:00am") {
+		t.Error("expected to confirm the time, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	tomorrow := Timezone.Now().AddDate(0, 0, 1)
+	if guess, _ := session["timeGuess"].(time.Time); !guess.Equal(time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 8, 0, 0, 0, Timezone.Location)) {
+		t.Error("wrong guess", guess)
+	}
+	if _, found := session["timereq"]; found {
+		t.Error("the guess was taken before it was confirmed")
+	}
+	if reply := chatOver(t, ts.URL, uuid, "whenever"); !strings.Contains(reply, "not a valid time") {
+		t.Error("accepted a time that isn't a time, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if _, found := session["timeGuess"]; found {
+		t.Error("the guess was kept after another answer")
+	}
+}
+
+func TestFlexibleTimes(t *testing.T) {
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2026, 11, 1, hour, minute, 0, 0, Timezone.Location)
+	}
+	carpool := DB.CarpoolRequest{PostID: 12, FromGUC: false, StartTime: at(8, 10)}
+
+	// A passenger that can leave anytime between 7:30 and 8:30 fits a carpool at 8:10.
+	session := Session{"fromGUCreq": false, "timereq": at(7, 30), "timereqEnd": at(8, 30)}
+	if !fitsRequest(session, carpool) {
+		t.Error("the carpool doesn't fit a window it's in")
+	}
+	session["fromGUCreq"] = true
+	if fitsRequest(session, carpool) {
+		t.Error("a carpool going the other way fits")
+	}
+	// A carpool at 9 fits a passenger at 8:30 only when the driver can leave half an hour early.
+	session = Session{"fromGUCreq": false, "timereq": at(8, 30)}
+	carpool.StartTime = at(9, 0)
+	if fitsRequest(session, carpool) {
+		t.Error("a later carpool fits")
+	}
+	carpool.Shift = 30
+	if !fitsRequest(session, carpool) {
+		t.Error("a carpool that can leave early enough doesn't fit")
+	}
+
+	// The rides overlap when one could still be going on when the other leaves.
+	if !ridesOverlap(carpool, at(10, 45), at(10, 45)) {
+		t.Error("a ride leaving during a late carpool doesn't overlap it")
+	}
+	if ridesOverlap(carpool, at(11, 45), at(12, 0)) {
+		t.Error("a ride leaving after the carpool is over overlaps it")
+	}
+
+	startTime, shift, err := carpoolTime(Session{}, When.Expression{Start: at(7, 30), End: at(8, 30)})
+	if err != nil || !startTime.Equal(at(8, 0)) || shift != 30 {
+		t.Error("wrong carpool time", startTime, shift, err)
+	}
+	if _, _, err = carpoolTime(Session{}, When.Expression{Start: at(7, 0), End: at(10, 0)}); err == nil {
+		t.Error("accepted a driver that can leave three hours apart")
+	}
+}
+
+func TestOneShotCommand(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	users := Users.NewMemoryStore()
+	users.Save(&DB.User{GUCID: "34-1234", Name: "Ahmed Ali", Vehicles: []DB.Vehicle{{Make: "Kia Picanto", Colour: "red", Plate: "ABC 123", Capacity: 3}}})
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: users, calendar: Calendar.NewMemoryStore()}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	// Only the missing details are asked for.
+	if reply := chatOver(t, ts.URL, uuid, "request from guc to Maadi"); !strings.Contains(reply, "When would you like your ride") {
+		t.Error("expected to be asked for the time, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if session["fromGUCreq"] != true || session["latitudereq"] != "29.9602" || session["placereq"] != "Maadi" {
+		t.Error("the details were not filled in", session)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "bokra 8"); !strings.Contains(reply, "Did you mean") {
+		t.Error("expected to confirm the time, got: " + reply)
+	}
+
+	// The time is already taken, since checking it against the driver's other carpools needs the database.
+	if reply := chatOver(t, ts.URL, uuid, "create to guc from Rehab 4 seats"); !strings.Contains(reply, "When would you like your ride") {
+		t.Error("expected to be asked for the time, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if session["fromGUC"] != false || session["latitude"] != 30.0586 || session["place"] != "Rehab" {
+		t.Error("the details were not filled in", session)
+	}
+	session["time"] = time.Now().AddDate(0, 0, 2)
+	store.Save(uuid, session)
+	// The driver's only car is taken, and it has 3 seats.
+	if reply := chatOver(t, ts.URL, uuid, "20 minutes late"); !strings.Contains(reply, "1 to 3 more passengers") {
+		t.Error("accepted more seats than the car has, got: " + reply)
+	}
+	reply := chatOver(t, ts.URL, uuid, "2")
+	if !strings.Contains(reply, "Here is your carpool: going to the GUC from Rehab") || !strings.Contains(reply, "red Kia Picanto") || !strings.Contains(reply, "with 2 seats") {
+		t.Error("expected the summary of the carpool, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "from Maadi"); !strings.Contains(reply, "from Maadi") {
+		t.Error("the place was not changed, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "no"); !strings.Contains(reply, "didn't create it") {
+		t.Error("expected the carpool to be forgotten, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if _, found := session["requestOrCreate"]; found {
+		t.Error("the carpool was not forgotten", session)
+	}
+}
+
+func TestBackAndUndo(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	users := Users.NewMemoryStore()
+	users.Save(&DB.User{GUCID: "34-1234", Name: "Ahmed Ali", Vehicles: []DB.Vehicle{{Make: "Kia Picanto", Colour: "red", Plate: "ABC 123", Capacity: 3}}})
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: users, calendar: Calendar.NewMemoryStore()}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	ride := time.Now().AddDate(0, 0, 2)
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true, "requestOrCreate": "create", "fromGUC": false, "latitude": 30.0, "longitude": 31.0, "time": ride, "shift": 0, "vehiclePlate": "ABC 123", "capacity": 3}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	// The driver only has one car, so going back from the seats goes back to the time.
+	if reply := chatOver(t, ts.URL, uuid, "back"); !strings.Contains(reply, "let's go back") || !strings.Contains(reply, "When would you like your ride") {
+		t.Error("expected to be asked for the time again, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if _, found := session["time"]; found || session["vehiclePlate"] != nil || session["latitude"] != 30.0 {
+		t.Error("went back to the wrong step", session)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "go back"); !strings.Contains(reply, "Where can you pick up people?") {
+		t.Error("expected to be asked for the place again, got: " + reply)
+	}
+	chatOver(t, ts.URL, uuid, "back")
+	if reply := chatOver(t, ts.URL, uuid, "back"); !strings.Contains(reply, "back at the start") {
+		t.Error("expected to leave the flow, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "back"); !strings.Contains(reply, "nothing to go back to") {
+		t.Error("went back without a flow, got: " + reply)
+	}
+
+	// A finished request goes back to the time, and asks for it.
+	session, _, _ = store.Get(uuid)
+	session["requestOrCreate"] = "request"
+	session["oneShot"] = true
+	session["confirming"] = true
+	session["fromGUCreq"] = true
+	session["latitudereq"] = "29.9602"
+	session["longitudereq"] = "31.2569"
+	session["placereq"] = "Maadi"
+	session["timereq"] = ride
+	store.Save(uuid, session)
+	if reply := chatOver(t, ts.URL, uuid, "back"); !strings.Contains(reply, "When would you like your ride") {
+		t.Error("expected to be asked for the time again, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if _, found := session["timereq"]; found || session["placereq"] != "Maadi" {
+		t.Error("went back to the wrong step", session)
+	}
+
+	// A cancelled request comes back with undo, but only once.
+	session["timereq"] = ride
+	session["requestComplete"] = true
+	delete(session, "requestOrCreate")
+	delete(session, "oneShot")
+	store.Save(uuid, session)
+	if reply := chatOver(t, ts.URL, uuid, "cancel request"); !strings.Contains(reply, "Type 'undo'") {
+		t.Error("expected to be told about undo, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if _, found := session["requestComplete"]; found {
+		t.Error("the request was not cancelled", session)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "undo"); !strings.Contains(reply, "Your request is back!") || !strings.Contains(reply, "going to Maadi") {
+		t.Error("expected the request to come back, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if session["requestComplete"] != true || session["latitudereq"] != "29.9602" || session["undo.placereq"] != nil {
+		t.Error("the request was not brought back", session)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "undo"); !strings.Contains(reply, "nothing I can undo") {
+		t.Error("undid twice, got: " + reply)
+	}
+
+	// Undo only works for a short while.
+	chatOver(t, ts.URL, uuid, "cancel request")
+	session, _, _ = store.Get(uuid)
+	session["undoAt"] = time.Now().Add(-undoGrace - time.Minute)
+	store.Save(uuid, session)
+	if reply := chatOver(t, ts.URL, uuid, "undo"); !strings.Contains(reply, "too late") {
+		t.Error("undid after the grace period, got: " + reply)
+	}
+
+	// A driver that doesn't want to delete a carpool with passengers keeps it.
+	session, _, _ = store.Get(uuid)
+	session["confirmDelete"] = uint64(12)
+	store.Save(uuid, session)
+	if reply := chatOver(t, ts.URL, uuid, "no"); !strings.Contains(reply, "I kept your carpool 12") {
+		t.Error("expected to keep the carpool, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if _, found := session["confirmDelete"]; found {
+		t.Error("still asking to delete the carpool", session)
+	}
+}
+
+// A student who chose Arabic in their profile is answered in Arabic, and can write their commands in Franco-Arabic.
+func TestArabicChat(t *testing.T) {
+	store := Sessions.NewMemoryStore()
+	users := Users.NewMemoryStore()
+	users.Save(&DB.User{GUCID: "34-1234", Name: "Ahmed Ali", Language: DB.LanguageEnglish})
+	ts := httptest.NewServer((&server{sessions: store, locks: Sessions.NewMemoryLocker(), users: users, calendar: Calendar.NewMemoryStore()}).routes())
+	defer ts.Close()
+	uuid, _ := Sessions.NewToken()
+	session := Session{"gucID": "34-1234", "name": "Ahmed Ali", "verified": true}
+	session.Touch(time.Now(), time.Hour)
+	store.Save(uuid, session)
+
+	if reply := chatOver(t, ts.URL, uuid, "undo"); !strings.Contains(reply, "There is nothing I can undo") {
+		t.Error("expected an English answer, got: " + reply)
+	}
+	chatOver(t, ts.URL, uuid, "edit profile language")
+	chatOver(t, ts.URL, uuid, "arabic")
+	session, _, _ = store.Get(uuid)
+	if session["language"] != DB.LanguageArabic {
+		t.Error("the language was not changed in the session", session)
+	}
+
+	if reply := chatOver(t, ts.URL, uuid, "3ayez arkab"); !strings.Contains(reply, "اخترت تطلب توصيلة") {
+		t.Error("expected to start a request in Arabic, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "rayeh el gam3a"); !strings.Contains(reply, "رايح الجامعة") {
+		t.Error("expected to go to the GUC, got: " + reply)
+	}
+	session, _, _ = store.Get(uuid)
+	if session["fromGUCreq"] != false {
+		t.Error("the direction was not read", session)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "back"); !strings.HasPrefix(reply, "تمام، يلا نرجع.") || !strings.Contains(reply, "إنت رايح الجامعة ولا خارج من الجامعة؟") {
+		t.Error("expected to go back in Arabic, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "undo"); !strings.Contains(reply, "مفيش حاجة أقدر أرجعها") {
+		t.Error("expected an Arabic answer, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "profile"); !strings.HasPrefix(reply, "دي بياناتك") || !strings.Contains(reply, "اللغة: \u2068العربي\u2069") {
+		t.Error("expected the profile in Arabic, got: " + reply)
+	}
+	if reply := chatOver(t, ts.URL, uuid, "timetable"); !strings.Contains(reply, "لسه معنديش جدول محاضراتك") {
+		t.Error("expected to be asked for the timetable in Arabic, got: " + reply)
+	}
+	// Why a value was not accepted is explained in Arabic too.
+	chatOver(t, ts.URL, uuid, "edit profile phone")
+	if reply := chatOver(t, ts.URL, uuid, "hello"); !strings.Contains(reply, "ده مش رقم موبايل.") || !strings.Contains(reply, "رقم موبايلك كام؟") || strings.Contains(reply, "phone number") {
+		t.Error("expected to be told the phone number is wrong in Arabic, got: " + reply)
+	}
+}
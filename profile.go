@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Users"
+)
+
+// The messages that ask for a new value of each field of the profile.
+var fieldPrompts = map[string]string{
+	Users.FieldPhone:     "profile.phone",
+	Users.FieldLanguage:  "profile.language",
+	Users.FieldHomeArea:  "profile.homeArea",
+	Users.FieldVehicle:   "profile.vehicle",
+	Users.FieldEmails:    "profile.emails",
+	Users.FieldReminders: "profile.reminders",
+}
+
+// Function that shows the profile of the user, and walks them through changing it one field at a time.
+func (s *server) profileHandler(res http.ResponseWriter, session Session, message string) {
+	comparable := strings.ToLower(strings.TrimSpace(message))
+	gucID := session["gucID"].(string)
+	step, editing := session["profileStep"].(string)
+	if editing && (comparable == "cancel" || comparable == "stop" || comparable == "back") {
+		delete(session, "profileStep")
+		writeJSON(res, JSON{
+			"message": say(session, "profile.kept", nil),
+		})
+		return
+	}
+
+	user, found, err := s.users.Get(gucID)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "db.getError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	if !found {
+		user = DB.User{GUCID: gucID, Name: session["name"].(string), Language: DB.LanguageEnglish}
+	}
+
+	// Show the profile, or start editing it.
+	if !editing {
+		if strings.Contains(comparable, "edit") || strings.Contains(comparable, "change") || strings.Contains(comparable, "update") {
+			if field, found := Users.FieldIn(comparable); found {
+				session["profileStep"] = field
+				writeJSON(res, JSON{
+					"message": say(session, fieldPrompts[field], nil),
+				})
+				return
+			}
+			session["profileStep"] = "field"
+			writeJSON(res, JSON{
+				"message": say(session, "profile.which", Messages.Params{"Fields": strings.Join(Users.Fields, ", ")}),
+			})
+			return
+		}
+		writeJSON(res, JSON{
+			"message": say(session, "profile.view", Messages.Params{"Profile": sayProfile(session, &user)}),
+		})
+		return
+	}
+
+	// Find out which field the user wants to change.
+	if step == "field" {
+		field, found := Users.FieldIn(comparable)
+		if !found {
+			writeJSON(res, JSON{
+				"message": say(session, "profile.onlyFields", Messages.Params{"Fields": strings.Join(Users.Fields, ", ")}),
+			})
+			return
+		}
+		session["profileStep"] = field
+		writeJSON(res, JSON{
+			"message": say(session, fieldPrompts[field], nil),
+		})
+		return
+	}
+
+	// Save the new value of the field.
+	err = Users.Set(&user, step, message)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "sorry", Messages.Params{"Error": sayError(session, err)}) + " " + say(session, fieldPrompts[step], nil),
+		})
+		return
+	}
+	err = s.users.Save(&user, Users.Paths[step])
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "profile.saveError", nil),
+		})
+		return
+	}
+	delete(session, "profileStep")
+	// The next answers are written in the language the student chose.
+	session["language"] = user.Language
+	writeJSON(res, JSON{
+		"message": say(session, "profile.changed", Messages.Params{"Field": step, "Profile": sayProfile(session, &user)}),
+	})
+}
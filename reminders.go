@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Scheduler"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+// Function that creates the scheduler for departure reminders, which are not sent on the days the GUC is closed on the calendar. The offsets are read from REMINDER_OFFSETS in minutes (eg. "60,15").
+func newReminderScheduler(calendar Calendar.Store) (*Scheduler.Scheduler, error) {
+	minutes := os.Getenv("REMINDER_OFFSETS")
+	if minutes == "" {
+		minutes = "60,15"
+	}
+	offsets, err := Scheduler.ParseOffsets(minutes)
+	if err != nil {
+		return nil, errors.New("REMINDER_OFFSETS: " + err.Error())
+	}
+	send := func(job DB.ScheduledJob) error {
+		return sendReminder(calendar, job)
+	}
+	return Scheduler.New(Scheduler.DBStore{}, offsets, send), nil
+}
+
+// Function that schedules the reminders of a carpool that was just created or edited.
+func scheduleReminders(postID uint64, startTime time.Time) {
+	err := reminders.ScheduleReminders(postID, startTime)
+	if err != nil {
+		log.Printf("could not schedule reminders for carpool %d: %s\n", postID, err.Error())
+	}
+}
+
+// Function that cancels the reminders of a deleted carpool.
+func cancelReminders(postID uint64) {
+	err := reminders.CancelReminders(postID)
+	if err != nil {
+		log.Printf("could not cancel reminders for carpool %d: %s\n", postID, err.Error())
+	}
+}
+
+// Function that sends a departure reminder to the driver and all the current passengers of a carpool.
+func sendReminder(calendar Calendar.Store, job DB.ScheduledJob) error {
+	carpoolRequests, err := DB.GetPostByID(job.PostID)
+	if err != nil {
+		return err
+	}
+	// The carpool was deleted, so there is no one to remind.
+	if len(carpoolRequests) == 0 {
+		return nil
+	}
+	carpoolRequest := carpoolRequests[0]
+	// The GUC is closed on that day, so no one is going.
+	year, err := Calendar.Load(calendar)
+	if err != nil {
+		log.Printf("could not load the academic calendar: %s\n", err.Error())
+	}
+	if _, closed := year.Closed(carpoolRequest.StartTime); closed {
+		return nil
+	}
+	data := Notifier.Data{
+		"PostID":     carpoolRequest.PostID,
+		"DriverName": DB.DisplayName(carpoolRequest.GUCID, carpoolRequest.Name),
+		"Minutes":    job.Minutes,
+		"StartTime":  Timezone.Format(carpoolRequest.StartTime),
+		"IsDriver":   true,
+	}
+	// Errors are only logged from here on, returning one would send the reminder again to the ones that already got it.
+	remind(carpoolRequest.GUCID, data["DriverName"].(string), data)
+
+	passengerRequests, err := DB.GetPassengerRequestsByPostID(job.PostID)
+	if err != nil {
+		log.Printf("could not get the passengers of carpool %d: %s\n", job.PostID, err.Error())
+		return nil
+	}
+	data["IsDriver"] = false
+	for _, gucID := range carpoolRequest.CurrentPassengers {
+		for _, passengerRequest := range passengerRequests {
+			if passengerRequest.Passenger.GUCID != gucID {
+				continue
+			}
+			remind(gucID, DB.DisplayName(gucID, passengerRequest.Passenger.Name), data)
+		}
+	}
+	return nil
+}
+
+// Function that sends a departure reminder to one student, unless they turned reminders off in their profile.
+func remind(gucID string, name string, data Notifier.Data) {
+	optedOut, err := DB.IsReminderOptedOut(gucID)
+	if err == nil && !optedOut {
+		err = notifier.Notify(gucID, name, Notifier.DepartureReminder, data)
+	}
+	if err != nil {
+		log.Printf("could not remind %s: %s\n", gucID, err.Error())
+	}
+}
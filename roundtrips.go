@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+)
+
+// Function that asks the driver if they are coming back, after they created a carpool.
+func returnQuestion(session Session) string {
+	if session["fromGUC"].(bool) {
+		return say(session, "return.comingBack", nil)
+	}
+	return say(session, "return.goingBack", nil)
+}
+
+// Function that creates the return trip of the carpool the driver just created, and links the two.
+func (s *server) returnLeg(session Session, outboundID uint64, message string) (string, error) {
+	comparable := strings.ToLower(strings.TrimSpace(message))
+	_, guessed := session["timeGuess"]
+	if !guessed && (comparable == "no" || comparable == "n" || comparable == "skip" || comparable == "cancel" || strings.Contains(comparable, "one way")) {
+		forgetDraft(session)
+		return say(session, "return.oneWay", nil), nil
+	}
+	expression, question, err := readRideTime(session, message)
+	if err != nil {
+		return "", errors.New(say(session, "return.invalidTime", Messages.Params{"Error": sayError(session, err), "Question": returnQuestion(session)}))
+	}
+	if question != "" {
+		return question, nil
+	}
+	returnTime, shift, err := carpoolTime(session, expression)
+	if err != nil {
+		return "", err
+	}
+	if !expression.Start.After(session["time"].(time.Time)) {
+		return "", errors.New(say(session, "return.beforeFirst", Messages.Params{"Time": sayTime(session, session["time"].(time.Time)), "Question": returnQuestion(session)}))
+	}
+	err = s.checkCampusOpen(session, returnTime)
+	if err != nil {
+		return "", err
+	}
+	err = checkDrivingConflict(session, expression.Start, expression.Latest())
+	if err != nil {
+		return "", err
+	}
+	err = checkRidingConflict(session, expression.Start, expression.Latest())
+	if err != nil {
+		return "", err
+	}
+
+	C, err := DB.NewCarpool(session["gucID"].(string), session["longitude"].(float64), session["latitude"].(float64), session["name"].(string), !session["fromGUC"].(bool), session["availableSeats"].(int), returnTime)
+	if err != nil {
+		return "", errors.New(say(session, "return.error", nil))
+	}
+	C.Vehicle = s.selectedVehicle(session)
+	C.LinkedPostID = outboundID
+	C.Shift = shift
+	err = DB.InsertDB(&C)
+	if err != nil {
+		return "", errors.New(say(session, "create.insertError", Messages.Params{"Error": sayError(session, err)}))
+	}
+	err = DB.SetLinkedCarpool(outboundID, C.PostID)
+	if err != nil {
+		return "", errors.New(say(session, "return.linkError", Messages.Params{"Error": sayError(session, err)}))
+	}
+	scheduleReminders(C.PostID, C.StartTime)
+	s.notifySubscribers(C)
+	forgetDraft(session)
+	returnID := strconv.FormatUint(C.PostID, 10)
+	return say(session, "return.done", Messages.Params{"PostID": returnID, "Time": sayTime(session, C.StartTime) + shiftToString(session, C.Shift)}), nil
+}
+
+// Function that returns the carpools the passenger chose: the one they chose, and the other leg if they chose a round trip.
+func chosenCarpools(session Session) []uint64 {
+	chosen := []uint64{}
+	for _, key := range []string{"myChoice", "myLinkedChoice"} {
+		if postID, found := session[key].(uint64); found {
+			chosen = append(chosen, postID)
+		}
+	}
+	return chosen
+}
+
+// Function that forgets a carpool the passenger chose. If it was one leg of a round trip, the other leg becomes their choice, and true is returned.
+func leaveCarpool(session Session, postID uint64) bool {
+	if linked, found := session["myLinkedChoice"].(uint64); found && linked == postID {
+		delete(session, "myLinkedChoice")
+		return false
+	}
+	if myChoice, found := session["myChoice"].(uint64); !found || myChoice != postID {
+		return false
+	}
+	delete(session, "myChoice")
+	linked, found := session["myLinkedChoice"].(uint64)
+	if !found {
+		return false
+	}
+	delete(session, "myLinkedChoice")
+	session["myChoice"] = linked
+	return true
+}
+
+// Function that checks the passenger can ask to join the carpool: it isn't theirs, it is still open with a free seat, and the GUC is open that day.
+func (s *server) canJoin(session Session, carpoolRequest DB.CarpoolRequest) error {
+	if strings.EqualFold(carpoolRequest.GUCID, session["gucID"].(string)) {
+		return errors.New(say(session, "join.own", nil))
+	}
+	postID := strconv.FormatUint(carpoolRequest.PostID, 10)
+	if status := carpoolRequest.CurrentStatus(); status != DB.StatusOpen && status != DB.StatusFull {
+		return errors.New(say(session, "join.notOpen", Messages.Params{"PostID": postID, "Status": say(session, "status."+status, nil)}))
+	}
+	if carpoolRequest.AvailableSeats < 1 {
+		return errors.New(say(session, "join.full", Messages.Params{"PostID": postID}))
+	}
+	return s.checkCampusOpen(session, carpoolRequest.StartTime)
+}
+
+// Function that asks the driver of a carpool to take the passenger with them.
+func (s *server) joinCarpool(session Session, carpoolRequest DB.CarpoolRequest) error {
+	err := s.canJoin(session, carpoolRequest)
+	if err != nil {
+		return err
+	}
+	myDetails, err := DB.NewPassengerRequest(session["gucID"].(string), session["name"].(string), carpoolRequest.PostID, 1)
+	if err != nil {
+		return errors.New(say(session, "join.createError", Messages.Params{"Error": sayError(session, err)}))
+	}
+	//insert after check
+	err = DB.InsertPassengerRequest(&myDetails)
+	if err != nil {
+		return errors.New(say(session, "join.saveError", Messages.Params{"Error": sayError(session, err)}))
+	}
+	possiblePassengers := append(carpoolRequest.PossiblePassengers, session["gucID"].(string))
+	err = DB.UpdateDB(carpoolRequest.PostID, carpoolRequest.Longitude, carpoolRequest.Latitude, carpoolRequest.FromGUC, carpoolRequest.AvailableSeats, carpoolRequest.CurrentPassengers, possiblePassengers, carpoolRequest.StartTime)
+	if err != nil {
+		// The driver must not see a request for a carpool the passenger is not in.
+		if deleteErr := DB.DeletePassengerRequest(carpoolRequest.PostID, myDetails.Passenger.GUCID); deleteErr != nil {
+			log.Printf("could not take back the request of %s for carpool %d: %s\n", myDetails.Passenger.GUCID, carpoolRequest.PostID, deleteErr.Error())
+		}
+		return errors.New(say(session, "join.updateError", Messages.Params{"Error": sayError(session, err)}))
+	}
+	return nil
+}
+
+// Function that takes back the request of the passenger to join the carpool, as it was before they joined it.
+func undoJoin(session Session, carpoolRequest DB.CarpoolRequest) {
+	gucID := session["gucID"].(string)
+	err := DB.DeletePassengerRequest(carpoolRequest.PostID, gucID)
+	if err == nil {
+		err = DB.UpdateDB(carpoolRequest.PostID, carpoolRequest.Longitude, carpoolRequest.Latitude, carpoolRequest.FromGUC, carpoolRequest.AvailableSeats, carpoolRequest.CurrentPassengers, carpoolRequest.PossiblePassengers, carpoolRequest.StartTime)
+	}
+	if err != nil {
+		log.Printf("could not take %s out of carpool %d: %s\n", gucID, carpoolRequest.PostID, err.Error())
+	}
+}
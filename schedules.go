@@ -0,0 +1,326 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Recurring"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Roster"
+)
+
+var (
+	repeatCommand = regexp.MustCompile(`^repeat\s+(?:carpool\s+)?([0-9]+)\s+(?:every|on)\s+(.+?)\s+until\s+(\S+)$`)
+	scheduleID    = regexp.MustCompile(`schedule\s+([0-9]+)`)
+	dateIn        = regexp.MustCompile(`\bon\s+([0-9]{4}-[0-9]{1,2}-[0-9]{1,2})\b`)
+)
+
+// Function that reads how far ahead the carpools of the weekly schedules are created, from SCHEDULE_AHEAD_DAYS (default 7).
+func scheduleAhead() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("SCHEDULE_AHEAD_DAYS"))
+	if err != nil || days <= 0 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Function that creates the carpools of all the weekly schedules that start in the next days.
+func (s *server) materializeSchedules() error {
+	now := time.Now()
+	schedules, err := s.schedules.Active(now)
+	if err != nil {
+		return err
+	}
+	year, err := Calendar.Load(s.calendar)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		err := s.materialize(schedule, year, now)
+		if err != nil {
+			log.Printf("could not create the carpools of schedule %d: %s\n", schedule.ID, err.Error())
+		}
+	}
+	return nil
+}
+
+// Function that creates the carpools of one weekly schedule that start in the next days, on the days with classes. Only one instance creates them at a time, so none is created twice.
+func (s *server) materialize(schedule DB.Schedule, year Calendar.Year, now time.Time) error {
+	unlock, err := s.locks.Lock("schedule:" + strconv.FormatUint(schedule.ID, 10))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	created, err := Recurring.Materialize(s.schedules, year, schedule, now, scheduleAhead())
+	for _, carpoolRequest := range created {
+		scheduleReminders(carpoolRequest.PostID, carpoolRequest.StartTime)
+		s.notifySubscribers(carpoolRequest)
+	}
+	return err
+}
+
+// Function that creates the carpools of one weekly schedule that start in the next days, right after the driver changed it.
+func (s *server) materializeNow(schedule DB.Schedule) error {
+	year, err := Calendar.Load(s.calendar)
+	if err != nil {
+		return err
+	}
+	return s.materialize(schedule, year, time.Now())
+}
+
+// Function that leaves the day of a carpool out of its weekly schedule, or brings it back, so a carpool the driver deleted is not created again.
+func (s *server) skipOccurrence(carpoolRequest DB.CarpoolRequest, skip bool) error {
+	if carpoolRequest.ScheduleID == 0 {
+		return nil
+	}
+	unlock, err := s.locks.Lock("schedule:" + strconv.FormatUint(carpoolRequest.ScheduleID, 10))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	schedule, found, err := s.schedules.Get(carpoolRequest.ScheduleID)
+	if err != nil || !found {
+		return err
+	}
+	if skip {
+		Recurring.Skip(&schedule, carpoolRequest.StartTime)
+	} else {
+		Recurring.Unskip(&schedule, carpoolRequest.StartTime)
+	}
+	return s.schedules.Save(&schedule)
+}
+
+// Function that lets a driver repeat a carpool every week, pause it, and choose the passengers that come every time.
+func (s *server) scheduleHandler(res http.ResponseWriter, session Session, message string) {
+	comparable := strings.ToLower(strings.TrimSpace(message))
+	gucID := session["gucID"].(string)
+
+	// Make a schedule out of a carpool.
+	if strings.HasPrefix(comparable, "repeat") {
+		s.repeatCarpool(res, session, comparable)
+		return
+	}
+
+	if strings.Contains(comparable, "view") {
+		schedules, err := s.schedules.ByDriver(gucID)
+		if err != nil {
+			writeJSON(res, JSON{
+				"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
+			})
+			return
+		}
+		if len(schedules) == 0 {
+			writeJSON(res, JSON{
+				"message": say(session, "schedules.none", nil),
+			})
+			return
+		}
+		str := ""
+		for _, schedule := range schedules {
+			str += saySchedule(session, schedule)
+		}
+		writeJSON(res, JSON{
+			"message": say(session, "schedules.view", nil) + "\n" + str,
+		})
+		return
+	}
+
+	// The other commands are about one schedule of the driver.
+	parts := scheduleID.FindStringSubmatch(comparable)
+	if parts == nil {
+		writeJSON(res, JSON{
+			"message": say(session, "schedule.which", nil),
+		})
+		return
+	}
+	ID, _ := strconv.ParseUint(parts[1], 10, 64)
+	schedule, found, err := s.schedules.Get(ID)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	if !found || schedule.GUCID != gucID {
+		writeJSON(res, JSON{
+			"message": say(session, "schedule.notYours", Messages.Params{"ScheduleID": parts[1]}),
+		})
+		return
+	}
+
+	reply := ""
+	switch {
+	case strings.Contains(comparable, "pause") || strings.Contains(comparable, "skip"):
+		reply, err = s.pauseSchedule(session, &schedule, comparable)
+	case strings.Contains(comparable, "resume"):
+		schedule.Paused = false
+		reply = say(session, "schedule.resumed", Messages.Params{"ScheduleID": parts[1]})
+	case strings.HasPrefix(comparable, "add") || strings.HasPrefix(comparable, "remove"):
+		reply, err = s.changeStandingPassengers(session, &schedule, comparable)
+	default:
+		writeJSON(res, JSON{
+			"message": say(session, "schedule.usage", Messages.Params{"ScheduleID": parts[1]}),
+		})
+		return
+	}
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "sorry", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	err = s.schedules.Save(&schedule)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "schedule.saveError", nil),
+		})
+		return
+	}
+	if !schedule.Paused {
+		err = s.materializeNow(schedule)
+		if err != nil {
+			log.Printf("could not create the carpools of schedule %d: %s\n", schedule.ID, err.Error())
+		}
+	}
+	writeJSON(res, JSON{
+		"message": reply,
+	})
+}
+
+// Function that makes a weekly schedule out of a carpool of the driver (eg. 'repeat carpool 12 every sun, tue until 2026-12-31').
+func (s *server) repeatCarpool(res http.ResponseWriter, session Session, comparable string) {
+	parts := repeatCommand.FindStringSubmatch(comparable)
+	if parts == nil {
+		writeJSON(res, JSON{
+			"message": say(session, "repeat.usage", nil),
+		})
+		return
+	}
+	carpoolRequests, err := driverCarpools(session["gucID"].(string))
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	carpoolRequest, err := chooseCarpool(session, carpoolRequests, "carpool "+parts[1])
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": sayError(session, err),
+		})
+		return
+	}
+	if carpoolRequest.ScheduleID != 0 {
+		writeJSON(res, JSON{
+			"message": say(session, "repeat.already", Messages.Params{"PostID": parts[1], "ScheduleID": strconv.FormatUint(carpoolRequest.ScheduleID, 10)}),
+		})
+		return
+	}
+	days, err := Recurring.ParseDays(parts[2])
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "sorry", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	endDate, err := Recurring.ParseDate(parts[3])
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "sorry", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	schedule, err := Recurring.FromCarpool(carpoolRequest, days, endDate)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "sorry", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	err = s.schedules.Insert(&schedule)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "schedule.saveError", nil),
+		})
+		return
+	}
+	err = s.materializeNow(schedule)
+	if err != nil {
+		log.Printf("could not create the carpools of schedule %d: %s\n", schedule.ID, err.Error())
+	}
+	writeJSON(res, JSON{
+		"message": say(session, "repeat.done", Messages.Params{"PostID": parts[1], "ScheduleID": strconv.FormatUint(schedule.ID, 10), "Schedule": saySchedule(session, schedule), "Days": int(scheduleAhead().Hours() / 24)}),
+	})
+}
+
+// Function that pauses a whole schedule, or only one day of it, and deletes the carpools it already created that won't happen.
+func (s *server) pauseSchedule(session Session, schedule *DB.Schedule, comparable string) (string, error) {
+	from, to := time.Now(), schedule.EndDate.AddDate(0, 0, 1)
+	ID := strconv.FormatUint(schedule.ID, 10)
+	reply := say(session, "schedule.paused", Messages.Params{"ScheduleID": ID})
+	if parts := dateIn.FindStringSubmatch(comparable); parts != nil {
+		date, err := Recurring.ParseDate(parts[1])
+		if err != nil {
+			return "", err
+		}
+		Recurring.Skip(schedule, date)
+		if date.After(from) {
+			from = date
+		}
+		to = date.AddDate(0, 0, 1)
+		reply = say(session, "schedule.skipped", Messages.Params{"ScheduleID": ID, "Date": parts[1]})
+	} else {
+		schedule.Paused = true
+	}
+	occurrences, err := s.schedules.Occurrences(schedule.ID, from, to)
+	if err != nil {
+		return "", err
+	}
+	for _, carpoolRequest := range occurrences {
+		err := cancelCarpool(session["name"].(string), carpoolRequest)
+		if err != nil {
+			return "", err
+		}
+	}
+	return reply, nil
+}
+
+// Function that adds a student to every carpool of the schedule, or stops adding them.
+func (s *server) changeStandingPassengers(session Session, schedule *DB.Schedule, comparable string) (string, error) {
+	passengerID := gucIDFormat.FindString(comparable)
+	ID := strconv.FormatUint(schedule.ID, 10)
+	if strings.HasPrefix(comparable, "remove") {
+		err := Recurring.RemoveStandingPassenger(schedule, passengerID)
+		if err != nil {
+			return "", err
+		}
+		// Take them out of the carpools the schedule already created too.
+		occurrences, err := s.schedules.Occurrences(schedule.ID, time.Now(), schedule.EndDate.AddDate(0, 0, 1))
+		if err != nil {
+			return "", err
+		}
+		for _, carpoolRequest := range occurrences {
+			err = s.schedules.RemovePassenger(carpoolRequest.PostID, passengerID)
+			if err != nil {
+				return "", err
+			}
+		}
+		return say(session, "standing.removed", Messages.Params{"Passenger": passengerID, "ScheduleID": ID}), nil
+	}
+	student, err := Roster.Lookup(s.roster, passengerID)
+	if err != nil {
+		return "", err
+	}
+	err = Recurring.AddStandingPassenger(schedule, student.GUCID)
+	if err != nil {
+		return "", err
+	}
+	return say(session, "standing.added", Messages.Params{"Name": student.Name, "ScheduleID": ID}), nil
+}
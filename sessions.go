@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Recurring"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Roster"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Sessions"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Users"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Verification"
+)
+
+// server : a server instance. Instances keep nothing about the users in memory, everything is in the session store, so many of them can run behind a load balancer.
+type server struct {
+	sessions  Sessions.Store
+	locks     Sessions.Locker
+	verifier  *Verification.Verifier
+	sso       *singleSignOn // nil if single sign-on is off
+	roster    Roster.Store
+	users     Users.Store
+	schedules Recurring.Store
+	calendar  Calendar.Store
+}
+
+// Function that creates a server instance with the store, the matching locker, a verifier that emails codes to the students on the roster and the identity provider if there is one.
+func newServer(store Sessions.Store) *server {
+	roster := Roster.DBStore{}
+	verifier := Verification.New(Verification.DBStore{}, Notifier.NewMailerFromEnv(), Roster.Address(roster), Verification.KeyFromEnv())
+	var locks Sessions.Locker = Sessions.NewMemoryLocker()
+	if _, inMongo := store.(Sessions.MongoStore); inMongo {
+		locks = Sessions.NewMongoLocker()
+	}
+	return &server{sessions: store, locks: locks, verifier: verifier, sso: newSSO(), roster: roster, users: Users.DBStore{}, schedules: Recurring.DBStore{}, calendar: Calendar.DBStore{}}
+}
+
+// Function that picks where the sessions are kept. SESSION_STORE=mongo keeps them in the database so they survive restarts and are shared by all the server instances, otherwise they are kept in memory.
+func newSessionStore() Sessions.Store {
+	if os.Getenv("SESSION_STORE") == "mongo" {
+		return Sessions.MongoStore{}
+	}
+	return Sessions.NewMemoryStore()
+}
+
+// Function that reads how long a session can stay idle before it expires, from SESSION_TTL in minutes (default 30).
+func sessionTTL() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("SESSION_TTL"))
+	if err != nil || minutes <= 0 {
+		minutes = 30
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// How long an expired session is kept, so the student is told it expired instead of that it is unknown.
+const expiredGrace = 24 * time.Hour
+
+// Function that removes the sessions that expired more than expiredGrace ago, so they don't pile up.
+func (s *server) removeExpiredSessions() error {
+	count, err := s.sessions.DeleteExpired(time.Now().Add(-expiredGrace))
+	if count > 0 {
+		log.Printf("removed %d expired sessions\n", count)
+	}
+	return err
+}
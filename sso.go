@@ -0,0 +1,184 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/OIDC"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Sessions"
+)
+
+// The cookie that remembers the state and nonce of a login between /login and /callback.
+const ssoCookie = "carpool_sso"
+
+// How long to wait before trying again to reach an identity provider that could not be reached.
+const ssoRetry = time.Minute
+
+// The university's identity provider, found again on the next login if it could not be reached before.
+type singleSignOn struct {
+	config   OIDC.Config
+	mutex    sync.Mutex
+	provider *OIDC.Provider
+	triedAt  time.Time
+}
+
+// Function that connects to the university's identity provider, if OIDC_ISSUER and OIDC_CLIENT_ID are set. Returns nil if single sign-on is off.
+func newSSO() *singleSignOn {
+	config, enabled := OIDC.ConfigFromEnv()
+	if !enabled {
+		return nil
+	}
+	sso := &singleSignOn{config: config}
+	sso.get()
+	return sso
+}
+
+// Function that returns the identity provider, trying to reach it again if it could not be reached a while ago. Returns nil if it can't be reached.
+func (sso *singleSignOn) get() *OIDC.Provider {
+	if sso == nil {
+		return nil
+	}
+	sso.mutex.Lock()
+	defer sso.mutex.Unlock()
+	if sso.provider != nil || time.Since(sso.triedAt) < ssoRetry {
+		return sso.provider
+	}
+	sso.triedAt = time.Now()
+	provider, err := OIDC.Discover(sso.config)
+	if err != nil {
+		log.Println("could not reach the identity provider, will try again on the next login: " + err.Error())
+		return nil
+	}
+	sso.provider = provider
+	return provider
+}
+
+// Function that returns an empty session in the language the browser asks for, to answer a student before their profile is loaded.
+func browserSession(req *http.Request) Session {
+	language := DB.LanguageEnglish
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("Accept-Language")), DB.LanguageArabic) {
+		language = DB.LanguageArabic
+	}
+	return Session{"language": language}
+}
+
+// Function to handle the login route, sends the user to log in at the identity provider.
+func (s *server) startSSO(res http.ResponseWriter, req *http.Request) {
+	browser := browserSession(req)
+	provider := s.sso.get()
+	if provider == nil {
+		res.WriteHeader(http.StatusNotFound)
+		writeJSON(res, JSON{
+			"message": say(browser, "sso.off", nil),
+		})
+		return
+	}
+	state, err := OIDC.NewState()
+	nonce, err2 := OIDC.NewState()
+	if err != nil || err2 != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": say(browser, "sso.startError", nil),
+		})
+		return
+	}
+	// The state and nonce stay with the browser, so the callback can reach any server instance.
+	http.SetCookie(res, &http.Cookie{
+		Name:     ssoCookie,
+		Value:    state + "." + nonce,
+		Path:     "/callback",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(res, req, provider.AuthURL(state, nonce), http.StatusFound)
+}
+
+// Function to handle the callback route, logs in the user the identity provider sent back and starts their session.
+func (s *server) finishSSO(res http.ResponseWriter, req *http.Request) {
+	browser := browserSession(req)
+	provider := s.sso.get()
+	if provider == nil {
+		res.WriteHeader(http.StatusNotFound)
+		writeJSON(res, JSON{
+			"message": say(browser, "sso.off", nil),
+		})
+		return
+	}
+	// Make sure this is the login this browser started.
+	cookie, err := req.Cookie(ssoCookie)
+	http.SetCookie(res, &http.Cookie{Name: ssoCookie, Path: "/callback", MaxAge: -1})
+	query := req.URL.Query()
+	if err != nil || !strings.Contains(cookie.Value, ".") {
+		res.WriteHeader(http.StatusBadRequest)
+		writeJSON(res, JSON{
+			"message": say(browser, "sso.unknown", nil),
+		})
+		return
+	}
+	values := strings.SplitN(cookie.Value, ".", 2)
+	state, nonce := values[0], values[1]
+	if query.Get("state") != state {
+		res.WriteHeader(http.StatusBadRequest)
+		writeJSON(res, JSON{
+			"message": say(browser, "sso.unknown", nil),
+		})
+		return
+	}
+	if query.Get("error") != "" {
+		res.WriteHeader(http.StatusUnauthorized)
+		writeJSON(res, JSON{
+			"message": say(browser, "sso.denied", Messages.Params{"Error": query.Get("error")}),
+		})
+		return
+	}
+	identity, err := provider.Exchange(query.Get("code"), nonce)
+	if err != nil {
+		res.WriteHeader(http.StatusUnauthorized)
+		writeJSON(res, JSON{
+			"message": say(browser, "login.failed", Messages.Params{"Error": sayError(browser, err)}),
+		})
+		return
+	}
+
+	// Only students on the roster can log in, with their official name.
+	student, ok := s.lookupStudent(res, browser, identity.GUCID)
+	if !ok {
+		return
+	}
+
+	// Start the same session the chat starts for a verified student.
+	uuid, err := Sessions.NewToken()
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": say(browser, "sso.sessionError", nil),
+		})
+		return
+	}
+	session := Session{}
+	session.Touch(time.Now(), sessionTTL())
+	err = s.restoreUser(uuid, session, student.GUCID, student.Name)
+	if err == nil {
+		err = s.sessions.Save(uuid, session)
+	}
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		writeJSON(res, JSON{
+			"message": say(browser, "db.getError", Messages.Params{"Error": sayError(browser, err)}),
+		})
+		return
+	}
+	// From here on the student is answered in the language of their profile.
+	s.loadLanguage(session)
+	writeJSON(res, JSON{
+		"uuid":    uuid,
+		"message": say(session, "login.welcome", Messages.Params{"Name": student.Name}),
+	})
+}
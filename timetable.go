@@ -0,0 +1,244 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Calendar"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timetable"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Timezone"
+)
+
+var suggestionCommand = regexp.MustCompile(`^(request|subscribe|unsubscribe)\s+(?:to\s+)?suggestion\s+([0-9]+)$`)
+
+// How many weeks ahead a suggested ride is looked for, when the GUC is closed on the next ones.
+const suggestionWeeks = 8
+
+// Function that lets a student upload their class timetable, and request or subscribe to the rides it needs.
+func (s *server) timetableHandler(res http.ResponseWriter, session Session, message string) {
+	gucID := session["gucID"].(string)
+	user, _, err := s.users.Get(gucID)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "db.retrieveError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	if user.GUCID == "" {
+		user = DB.User{GUCID: gucID, Name: session["name"].(string), Language: DB.LanguageEnglish}
+	}
+	comparable := strings.ToLower(strings.TrimSpace(message))
+
+	// The file is pasted after the first line of the message.
+	if newline := strings.Index(strings.TrimSpace(message), "\n"); newline >= 0 {
+		s.importTimetable(res, session, user, strings.TrimSpace(message)[newline+1:])
+		return
+	}
+
+	if strings.Contains(comparable, "remove") || strings.Contains(comparable, "delete") {
+		user.Timetable = nil
+		user.Subscriptions = nil
+		err = s.users.Save(&user, DB.UserTimetable, DB.UserSubscriptions)
+		if err != nil {
+			writeJSON(res, JSON{
+				"message": say(session, "timetable.saveError", nil),
+			})
+			return
+		}
+		writeJSON(res, JSON{
+			"message": say(session, "timetable.removed", nil),
+		})
+		return
+	}
+
+	parts := suggestionCommand.FindStringSubmatch(comparable)
+	if parts == nil {
+		if len(user.Timetable) == 0 {
+			writeJSON(res, JSON{
+				"message": say(session, "timetable.none", nil),
+			})
+			return
+		}
+		writeJSON(res, JSON{
+			"message": say(session, "timetable.view", nil) + "\n" + sayClasses(session, user.Timetable) + suggestionsToString(session, user),
+		})
+		return
+	}
+	suggestions := Timetable.Suggest(user.Timetable)
+	number, _ := strconv.Atoi(parts[2])
+	if number < 1 || number > len(suggestions) {
+		writeJSON(res, JSON{
+			"message": say(session, "suggestion.notFound", Messages.Params{"Number": parts[2]}),
+		})
+		return
+	}
+	suggestion := suggestions[number-1]
+
+	if parts[1] == "request" {
+		reply, err := s.requestSuggestion(session, suggestion)
+		if err != nil {
+			reply = sayError(session, err)
+		}
+		writeJSON(res, JSON{
+			"message": reply,
+		})
+		return
+	}
+	reply := say(session, "suggestion.subscribed", Messages.Params{"Suggestion": saySuggestion(session, suggestion), "Number": parts[2]})
+	if parts[1] == "unsubscribe" {
+		user.Subscriptions = removeSubscription(user.Subscriptions, suggestion)
+		reply = say(session, "suggestion.unsubscribed", Messages.Params{"Suggestion": saySuggestion(session, suggestion)})
+	} else if !subscribed(user, suggestion) {
+		user.Subscriptions = append(user.Subscriptions, suggestion)
+	}
+	err = s.users.Save(&user, DB.UserSubscriptions)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "subscription.saveError", nil),
+		})
+		return
+	}
+	writeJSON(res, JSON{
+		"message": reply,
+	})
+}
+
+// Function that reads the timetable the student pasted and saves it in their profile. Their subscriptions are kept.
+func (s *server) importTimetable(res http.ResponseWriter, session Session, user DB.User, file string) {
+	var classes []DB.Class
+	var err error
+	if strings.Contains(strings.ToUpper(file), "BEGIN:VCALENDAR") {
+		classes, err = Timetable.ParseICS(strings.NewReader(file))
+	} else {
+		classes, err = Timetable.ParseCSV(strings.NewReader(file))
+	}
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "timetable.readError", Messages.Params{"Error": sayError(session, err)}),
+		})
+		return
+	}
+	user.Timetable = classes
+	err = s.users.Save(&user, DB.UserTimetable)
+	if err != nil {
+		writeJSON(res, JSON{
+			"message": say(session, "timetable.saveError", nil),
+		})
+		return
+	}
+	writeJSON(res, JSON{
+		"message": say(session, "timetable.saved", nil) + "\n" + sayClasses(session, classes) + suggestionsToString(session, user),
+	})
+}
+
+// Function that lists the rides the timetable of the student needs, and how to request or subscribe to them.
+func suggestionsToString(session Session, user DB.User) string {
+	str := say(session, "suggestions.title", nil) + "\n"
+	for i, suggestion := range Timetable.Suggest(user.Timetable) {
+		str += "\t" + strconv.Itoa(i+1) + ": " + saySuggestion(session, suggestion)
+		if subscribed(user, suggestion) {
+			str += say(session, "suggestions.subscribed", nil)
+		}
+		str += "\n"
+	}
+	return str + say(session, "suggestions.usage", nil)
+}
+
+// Function that checks the student already subscribed to the suggested ride.
+func subscribed(user DB.User, suggestion DB.RideSubscription) bool {
+	for _, subscription := range user.Subscriptions {
+		if subscription == suggestion {
+			return true
+		}
+	}
+	return false
+}
+
+// Function that returns the subscriptions without the suggested ride.
+func removeSubscription(subscriptions []DB.RideSubscription, suggestion DB.RideSubscription) []DB.RideSubscription {
+	kept := []DB.RideSubscription{}
+	for _, subscription := range subscriptions {
+		if subscription != suggestion {
+			kept = append(kept, subscription)
+		}
+	}
+	return kept
+}
+
+// Function that starts a request for the next suggested ride the GUC is open for, with the direction and the time already filled in.
+func (s *server) requestSuggestion(session Session, suggestion DB.RideSubscription) (string, error) {
+	year, err := Calendar.Load(s.calendar)
+	if err != nil {
+		return "", errors.New(say(session, "calendar.error", nil))
+	}
+	next := Timetable.Next(suggestion, Timezone.Now())
+	for weeks := 0; ; weeks++ {
+		if _, closed := year.Closed(next); !closed {
+			break
+		}
+		if weeks == suggestionWeeks {
+			return "", errors.New(say(session, "suggestion.closed", Messages.Params{"Day": sayWeekday(session, suggestion.Day), "Weeks": suggestionWeeks}))
+		}
+		next = next.AddDate(0, 0, 7)
+	}
+	// Any carpool leaving in the window of the suggestion fits the request.
+	earliest := time.Date(next.Year(), next.Month(), next.Day(), suggestion.Earliest/60, suggestion.Earliest%60, 0, 0, Timezone.Location)
+	latest := time.Date(next.Year(), next.Month(), next.Day(), suggestion.Latest/60, suggestion.Latest%60, 0, 0, Timezone.Location)
+	err = checkDrivingConflict(session, earliest, latest)
+	if err != nil {
+		return "", err
+	}
+	forgetRequest(session)
+	session["requestOrCreate"] = "request"
+	session["fromGUCreq"] = suggestion.FromGUC
+	session["timereq"] = earliest
+	if latest.After(earliest) {
+		session["timereqEnd"] = latest
+	}
+	question := say(session, "request.wherePickUp", nil)
+	if suggestion.FromGUC {
+		question = say(session, "request.whereGo", nil)
+	}
+	return say(session, "suggestion.request", Messages.Params{"Suggestion": saySuggestion(session, suggestion), "Time": sayExpression(session, requestWindow(session)), "Question": question}), nil
+}
+
+// Function that emails the students whose timetable a new carpool fits, so they can join it.
+func (s *server) notifySubscribers(carpoolRequest DB.CarpoolRequest) {
+	if carpoolRequest.AvailableSeats < 1 {
+		return
+	}
+	startTime := carpoolRequest.StartTime.In(Timezone.Location)
+	minute := startTime.Hour()*60 + startTime.Minute()
+	users, err := s.users.Subscribers(startTime.Weekday(), carpoolRequest.FromGUC, minute-carpoolRequest.Shift, minute+carpoolRequest.Shift)
+	if err != nil {
+		log.Printf("could not find the students subscribed to carpool %d: %s\n", carpoolRequest.PostID, err.Error())
+		return
+	}
+	if len(users) == 0 {
+		return
+	}
+	data := Notifier.Data{
+		"PostID":     carpoolRequest.PostID,
+		"DriverName": DB.DisplayName(carpoolRequest.GUCID, carpoolRequest.Name),
+		"FromGUC":    carpoolRequest.FromGUC,
+		"StartTime":  Timezone.Format(carpoolRequest.StartTime),
+	}
+	for _, user := range users {
+		inCarpool := user.GUCID == carpoolRequest.GUCID
+		for _, passenger := range carpoolRequest.CurrentPassengers {
+			inCarpool = inCarpool || passenger == user.GUCID
+		}
+		if inCarpool {
+			continue
+		}
+		sendEmail(user.GUCID, user.Name, Notifier.CarpoolMatch, data)
+	}
+}
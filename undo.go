@@ -0,0 +1,218 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Notifier"
+)
+
+// How long a student has to undo deleting a carpool, rejecting a passenger or cancelling their request.
+const undoGrace = 5 * time.Minute
+
+// The commands that can be undone.
+const (
+	undoDelete = "delete"
+	undoReject = "reject"
+	undoCancel = "cancel"
+)
+
+var undoCommand = regexp.MustCompile(`^\s*undo\b`)
+
+// The session keys of the last command that can be undone. The session keys the command forgot are kept too, starting with undoPrefix.
+var undoKeys = []string{"undoAction", "undoAt", "undoPostIDs", "undoAccepted", "undoPassenger"}
+
+const undoPrefix = "undo."
+
+// The session keys cancelling a request forgets, and undoing it brings back.
+var cancelledKeys = append(append([]string{}, requestKeys...), "requestOrCreate", "myChoice", "myLinkedChoice")
+
+// Function that tells the student they can undo the command they just typed.
+func undoHint(session Session) string {
+	return " " + say(session, "undo.hint", Messages.Params{"Minutes": int(undoGrace / time.Minute)})
+}
+
+// Function that copies the session keys a command is about to forget, so they can be brought back.
+func keep(session Session, keys []string) Session {
+	kept := Session{}
+	for _, key := range keys {
+		if value, found := session[key]; found {
+			kept[key] = value
+		}
+	}
+	return kept
+}
+
+// Function that remembers the last command the student can undo: what it was, the carpools it changed, the ones the passenger was accepted in, and the session keys it forgot.
+func rememberUndo(session Session, action string, postIDs []uint64, accepted []uint64, passenger string, kept Session) {
+	forgetUndo(session)
+	session["undoAction"] = action
+	session["undoAt"] = time.Now()
+	session["undoPostIDs"] = idsToStrings(postIDs)
+	session["undoAccepted"] = idsToStrings(accepted)
+	session["undoPassenger"] = passenger
+	for key, value := range kept {
+		session[undoPrefix+key] = value
+	}
+}
+
+// Function that forgets the last command the student could undo.
+func forgetUndo(session Session) {
+	for _, key := range undoKeys {
+		delete(session, key)
+	}
+	for _, key := range cancelledKeys {
+		delete(session, undoPrefix+key)
+	}
+}
+
+// Function that writes post IDs as strings, so they can be kept in the session.
+func idsToStrings(postIDs []uint64) []string {
+	strs := []string{}
+	for _, postID := range postIDs {
+		strs = append(strs, strconv.FormatUint(postID, 10))
+	}
+	return strs
+}
+
+// Function that checks the post ID is one of the post IDs kept in the session.
+func containsID(strs []string, postID uint64) bool {
+	for _, str := range strs {
+		if str == strconv.FormatUint(postID, 10) {
+			return true
+		}
+	}
+	return false
+}
+
+// Function that undoes the last carpool the driver deleted, the last passenger they rejected, or the request the passenger cancelled, if it was a short while ago.
+func (s *server) undoHandler(res http.ResponseWriter, session Session) {
+	action, found := session["undoAction"].(string)
+	at, _ := session["undoAt"].(time.Time)
+	postIDs, _ := session["undoPostIDs"].([]string)
+	accepted, _ := session["undoAccepted"].([]string)
+	passenger, _ := session["undoPassenger"].(string)
+	kept := Session{}
+	for _, key := range cancelledKeys {
+		if value, found := session[undoPrefix+key]; found {
+			kept[key] = value
+		}
+	}
+	forgetUndo(session)
+	if !found {
+		writeJSON(res, JSON{
+			"message": say(session, "undo.nothing", Messages.Params{"Minutes": int(undoGrace / time.Minute)}),
+		})
+		return
+	}
+	if time.Since(at) > undoGrace {
+		writeJSON(res, JSON{
+			"message": say(session, "undo.tooLate", Messages.Params{"Minutes": int(undoGrace / time.Minute)}),
+		})
+		return
+	}
+
+	switch action {
+	case undoDelete:
+		writeJSON(res, JSON{
+			"message": s.restoreCarpools(session, postIDs),
+		})
+	case undoReject:
+		restored := []string{}
+		for _, postIDString := range postIDs {
+			postID, _ := strconv.ParseUint(postIDString, 10, 64)
+			err := DB.RestorePassenger(passenger, postID, containsID(accepted, postID))
+			if err != nil {
+				writeJSON(res, JSON{
+					"message": say(session, "undo.rejectError", Messages.Params{"Passenger": passenger, "PostID": postIDString, "Error": sayError(session, err)}),
+				})
+				return
+			}
+			emailPassenger(passenger, postID, Notifier.RideRestored, session)
+			restored = append(restored, postIDString)
+		}
+		writeJSON(res, JSON{
+			"message": say(session, "undo.rejected", Messages.Params{"Passenger": passenger, "PostIDs": strings.Join(restored, say(session, "and", nil))}),
+		})
+	case undoCancel:
+		writeJSON(res, JSON{
+			"message": restoreRequest(session, postIDs, accepted, kept),
+		})
+	}
+}
+
+// Function that brings back the carpools the driver deleted, with the passengers that didn't choose another carpool since, and lets those know.
+func (s *server) restoreCarpools(session Session, postIDs []string) string {
+	for _, postIDString := range postIDs {
+		postID, _ := strconv.ParseUint(postIDString, 10, 64)
+		carpoolRequest, err := DB.RestoreCarpool(postID)
+		if err != nil {
+			return say(session, "undo.deleteError", Messages.Params{"PostID": postIDString, "Error": sayError(session, err)})
+		}
+		scheduleReminders(carpoolRequest.PostID, carpoolRequest.StartTime)
+		err = s.skipOccurrence(carpoolRequest, false)
+		if err != nil {
+			log.Printf("could not bring carpool %d back into schedule %d: %s\n", postID, carpoolRequest.ScheduleID, err.Error())
+		}
+		// The other way of a round trip is linked again, if it wasn't deleted too.
+		if carpoolRequest.LinkedPostID != 0 {
+			linked, err := DB.GetPostByID(carpoolRequest.LinkedPostID)
+			if err == nil && len(linked) > 0 && linked[0].LinkedPostID == 0 {
+				err = DB.SetLinkedCarpool(carpoolRequest.LinkedPostID, postID)
+			} else if err == nil {
+				err = DB.SetLinkedCarpool(postID, 0)
+			}
+			if err != nil {
+				return say(session, "undo.linkError", Messages.Params{"PostID": postIDString, "Error": sayError(session, err)})
+			}
+		}
+		passengerRequests, err := DB.GetPassengerRequestsByPostID(postID)
+		if err != nil {
+			log.Printf("could not email the passengers of carpool %d: %s\n", postID, err.Error())
+		}
+		for _, passengerRequest := range passengerRequests {
+			if passengerRequest.Notify == 1 || passengerRequest.Notify == 2 {
+				sendEmail(passengerRequest.Passenger.GUCID, passengerRequest.Passenger.Name, Notifier.RideRestored, Notifier.Data{"DriverName": session["name"], "PostID": postID, "Deleted": true, "Accepted": passengerRequest.Notify == 2})
+			}
+		}
+	}
+	return say(session, "undo.deleted", Messages.Params{"PostIDs": strings.Join(postIDs, say(session, "and", nil)), "PostID": postIDs[0]})
+}
+
+// Function that brings back the request the passenger cancelled, and puts them back in the carpool they left.
+func restoreRequest(session Session, postIDs []string, accepted []string, kept Session) string {
+	// The other way of a round trip the passenger is still in doesn't count.
+	if chosen, found := session["myChoice"]; found && chosen != kept["myChoice"] && chosen != kept["myLinkedChoice"] {
+		return say(session, "undo.chosenSince", nil)
+	}
+	forgetRequest(session)
+	delete(session, "requestOrCreate")
+	for key, value := range kept {
+		session[key] = value
+	}
+	reply := say(session, "undo.cancelled", nil)
+	if _, complete := session["requestComplete"]; complete {
+		reply += " " + say(session, "undo.details", Messages.Params{"Details": getDetails(session)})
+	}
+	for _, postIDString := range postIDs {
+		postID, _ := strconv.ParseUint(postIDString, 10, 64)
+		err := DB.RestorePassenger(session["gucID"].(string), postID, containsID(accepted, postID))
+		if err != nil {
+			leaveCarpool(session, postID)
+			reply += " " + say(session, "undo.rejoinError", Messages.Params{"PostID": postIDString, "Error": sayError(session, err)})
+			continue
+		}
+		if containsID(accepted, postID) {
+			reply += " " + say(session, "undo.inAgain", Messages.Params{"PostID": postIDString})
+		} else {
+			reply += " " + say(session, "undo.waitingAgain", Messages.Params{"PostID": postIDString})
+		}
+	}
+	return reply
+}
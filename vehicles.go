@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/DB"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Messages"
+	"github.com/AbdelrahmanKhaledAmer/GUC-Carpool/Users"
+)
+
+// Function that asks the driver which car they are driving. A driver with only one car drives it without being asked.
+func (s *server) askVehicle(session Session) (string, error) {
+	user, _, err := s.users.Get(session["gucID"].(string))
+	if err != nil {
+		return "", err
+	}
+	switch len(user.Vehicles) {
+	case 0:
+		return say(session, "vehicle.which", nil) + " " + say(session, "vehicle.describe", nil), nil
+	case 1:
+		return s.useVehicle(session, user.Vehicles[0]), nil
+	}
+	question := say(session, "vehicle.which", nil) + " " + say(session, "vehicle.choose", nil)
+	for i, vehicle := range user.Vehicles {
+		question += "\n" + strconv.Itoa(i+1) + ". " + vehicle.VehicleToString()
+	}
+	return question, nil
+}
+
+// Function that takes the car the driver chose, or registers the car of a driver that doesn't have one yet.
+func (s *server) chooseVehicle(session Session, message string) (string, error) {
+	user, found, err := s.users.Get(session["gucID"].(string))
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		user = DB.User{GUCID: session["gucID"].(string), Name: session["name"].(string), Language: DB.LanguageEnglish}
+	}
+	if len(user.Vehicles) == 0 {
+		vehicle, err := Users.ParseVehicle(message)
+		if err != nil {
+			return say(session, "vehicle.invalid", Messages.Params{"Error": sayError(session, err)}) + " " + say(session, "vehicle.describe", nil), nil
+		}
+		Users.AddVehicle(&user, vehicle)
+		err = s.users.Save(&user, DB.UserVehicles)
+		if err != nil {
+			return "", err
+		}
+		return say(session, "vehicle.added", nil) + " " + s.useVehicle(session, vehicle), nil
+	}
+	vehicle, found := Users.FindVehicle(user.Vehicles, message)
+	if !found {
+		question, err := s.askVehicle(session)
+		if err != nil {
+			return "", err
+		}
+		return say(session, "vehicle.notYours", nil) + " " + question, nil
+	}
+	return s.useVehicle(session, vehicle), nil
+}
+
+// Function that remembers the car the driver chose, and asks how many passengers it can take.
+func (s *server) useVehicle(session Session, vehicle DB.Vehicle) string {
+	session["vehiclePlate"] = vehicle.Plate
+	session["capacity"] = vehicle.Capacity
+	currentPassengers, _ := session["currentPassengers"].([]string)
+	return say(session, "vehicle.chosen", Messages.Params{"Vehicle": vehicle.VehicleToString()}) + " " + say(session, "command.seats", Messages.Params{"Seats": vehicle.Capacity - len(currentPassengers)})
+}
+
+// Function that returns the car the driver chose for the carpool. The session only keeps its plate and capacity, so the rest comes from the profile.
+func (s *server) selectedVehicle(session Session) DB.Vehicle {
+	vehicle := DB.Vehicle{Plate: session["vehiclePlate"].(string), Capacity: session["capacity"].(int)}
+	user, _, err := s.users.Get(session["gucID"].(string))
+	if err != nil {
+		return vehicle
+	}
+	if registered, found := Users.FindVehicle(user.Vehicles, vehicle.Plate); found {
+		return registered
+	}
+	return vehicle
+}